@@ -0,0 +1,329 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PendingEvent is an event delivered by AckQueue which hasn't been acknowledged yet.
+type PendingEvent struct {
+	ID    string `json:"id"`
+	Event Event  `json:"event"`
+	// Attempts is the number of deliveries made so far.
+	Attempts int `json:"attempts"`
+	// Deadline is the time of the next delivery unless the event is acknowledged.
+	Deadline time.Time `json:"deadline"`
+}
+
+// PendingEventStore persists unacknowledged events of AckQueue, so they survive restarts.
+// Put should replace the event with the same ID.
+type PendingEventStore interface {
+	Put(e *PendingEvent) error
+	Remove(id string) error
+	List() ([]*PendingEvent, error)
+}
+
+// MemoryPendingEventStore is an in-memory PendingEventStore. Events are lost on restart, so use it for tests.
+type MemoryPendingEventStore struct {
+	mu     sync.Mutex
+	events map[string]PendingEvent
+}
+
+// NewMemoryPendingEventStore returns an empty MemoryPendingEventStore.
+func NewMemoryPendingEventStore() *MemoryPendingEventStore {
+	return &MemoryPendingEventStore{
+		events: make(map[string]PendingEvent),
+	}
+}
+
+// Put saves a copy of the event.
+func (s *MemoryPendingEventStore) Put(e *PendingEvent) error {
+	s.mu.Lock()
+	s.events[e.ID] = *e
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Remove removes the event.
+func (s *MemoryPendingEventStore) Remove(id string) error {
+	s.mu.Lock()
+	delete(s.events, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns copies of all events ordered by deadline.
+func (s *MemoryPendingEventStore) List() ([]*PendingEvent, error) {
+	s.mu.Lock()
+	events := make([]*PendingEvent, 0, len(s.events))
+	for _, e := range s.events {
+		e := e
+		events = append(events, &e)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Deadline.Before(events[j].Deadline)
+	})
+
+	return events, nil
+}
+
+// Delivery is an event delivered by AckQueue. Every delivery must be acknowledged with Ack after processing
+// or rejected with Nack, otherwise the event is delivered again after the ack timeout.
+type Delivery struct {
+	Event Event
+	// Attempt is the number of the delivery starting from 1.
+	Attempt int
+
+	queue   *AckQueue
+	pending PendingEvent
+}
+
+// Ack removes the event from the store and marks it processed in EventStore of WithAckProcessedStore.
+func (d *Delivery) Ack() error {
+	if err := d.queue.store.Remove(d.pending.ID); err != nil {
+		return err
+	}
+	if d.queue.processed != nil {
+		return MarkEventProcessed(d.queue.processed, d.Event)
+	}
+
+	return nil
+}
+
+// Nack schedules the event for redelivery after the nack delay.
+func (d *Delivery) Nack() error {
+	pending := d.pending
+	pending.Deadline = d.queue.clock.Now().Add(d.queue.nackDelay)
+
+	return d.queue.store.Put(&pending)
+}
+
+// AckQueue provides at-least-once processing of webhook events: events are saved to PendingEventStore
+// before Pyrus gets 200 and stay there until the handler acknowledges them. Events which haven't been
+// acknowledged within the timeout, rejected ones and events left from the previous run are delivered again.
+type AckQueue struct {
+	client     *Client
+	store      PendingEventStore
+	processed  EventStore
+	clock      Clock
+	ackTimeout time.Duration
+	nackDelay  time.Duration
+	interval   time.Duration
+
+	deliveries chan *Delivery
+}
+
+// AckOption helps to create an option for AckQueue.
+type AckOption func(*AckQueue)
+
+// WithAckTimeout allows to override default timeout of 1 minute after which unacknowledged events are redelivered.
+func WithAckTimeout(d time.Duration) AckOption {
+	return func(q *AckQueue) {
+		q.ackTimeout = d
+	}
+}
+
+// WithNackDelay allows to override default delay of 5 seconds before redelivery of rejected events.
+func WithNackDelay(d time.Duration) AckOption {
+	return func(q *AckQueue) {
+		q.nackDelay = d
+	}
+}
+
+// WithAckCheckInterval allows to override default interval of 1 second between checks for events to redeliver.
+func WithAckCheckInterval(d time.Duration) AckOption {
+	return func(q *AckQueue) {
+		q.interval = d
+	}
+}
+
+// WithAckProcessedStore allows to mark acknowledged events processed in the store, so Replay skips them.
+func WithAckProcessedStore(store EventStore) AckOption {
+	return func(q *AckQueue) {
+		q.processed = store
+	}
+}
+
+// NewAckQueue returns an instance of AckQueue.
+func NewAckQueue(client *Client, store PendingEventStore, opts ...AckOption) *AckQueue {
+	q := &AckQueue{
+		client:     client,
+		store:      store,
+		clock:      clientClock(client),
+		ackTimeout: time.Minute,
+		nackDelay:  5 * time.Second,
+		interval:   time.Second,
+		deliveries: make(chan *Delivery, client.eventBufferSize),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// Deliveries returns chan of deliveries. It's not closed, stop reading it when Run returns.
+func (q *AckQueue) Deliveries() <-chan *Delivery {
+	return q.deliveries
+}
+
+// Handler returns webhook handler which saves events to the store and delivers them.
+// Signature, filters and body limit of the client are applied as in WebhookHTTPHandler.
+func (q *AckQueue) Handler() http.Handler {
+	c := q.client
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := c.readWebhookBody(w, r)
+		if !ok {
+			return
+		}
+		defer c.recoverWebhook(w, b)
+
+		event, err := c.ParseWebhook(b, r.Header.Get(WebhookSignatureHeader))
+		if errors.Is(err, ErrInvalidSignature) {
+			c.logger.Error("Invalid signature!", err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			c.logger.Error("Error while decoding a request body!", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !c.acceptWebhook(*event) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		pending := &PendingEvent{
+			ID:       pendingEventID(event),
+			Event:    *event,
+			Attempts: 1,
+			Deadline: q.clock.Now().Add(q.ackTimeout),
+		}
+		if err := q.store.Put(pending); err != nil {
+			c.logger.Error("Error while saving an event!", err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		// The event is saved, so if the chan is full it's delivered by Run after the timeout
+		select {
+		case q.deliveries <- q.delivery(pending):
+		default:
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return q.client.limitWebhook(&webhookHandler{next: handler})
+}
+
+// Run delivers events left in the store from the previous run and then redelivers events
+// past their deadline every check interval until the context is done or the client is closed.
+func (q *AckQueue) Run(ctx context.Context) error {
+	if q.interval <= 0 {
+		return fmt.Errorf("ack check interval must be positive, got %v", q.interval)
+	}
+
+	events, err := q.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if err := q.redeliver(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-q.client.Done():
+			return ErrClientClosed
+		case <-ticker.C:
+		}
+
+		if err := q.redeliverDue(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// redeliverDue redelivers events past their deadline.
+func (q *AckQueue) redeliverDue(ctx context.Context) error {
+	events, err := q.store.List()
+	if err != nil {
+		return err
+	}
+
+	now := q.clock.Now()
+	for _, e := range events {
+		if e.Deadline.After(now) {
+			continue
+		}
+		if err := q.redeliver(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *AckQueue) redeliver(ctx context.Context, e *PendingEvent) error {
+	e.Attempts++
+	e.Deadline = q.clock.Now().Add(q.ackTimeout)
+	if err := q.store.Put(e); err != nil {
+		return err
+	}
+	e.Event.client = q.client
+
+	select {
+	case q.deliveries <- q.delivery(e):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-q.client.Done():
+		return ErrClientClosed
+	}
+}
+
+func (q *AckQueue) delivery(e *PendingEvent) *Delivery {
+	return &Delivery{
+		Event:   e.Event,
+		Attempt: e.Attempts,
+		queue:   q,
+		pending: *e,
+	}
+}
+
+// pendingEventID identifies the event by its subject and the last note, so redeliveries of the same
+// webhook by Pyrus are stored once.
+func pendingEventID(e *Event) string {
+	if e.Subject() == EventSubjectAnnouncement {
+		id := "announcement:" + strconv.Itoa(e.AnnouncementID)
+		if e.LastAnnouncementComment != nil {
+			id += ":" + strconv.Itoa(e.LastAnnouncementComment.ID)
+		}
+		return id
+	}
+
+	noteID := 0
+	if e.Task != nil && e.Task.Task != nil {
+		noteID = e.Task.LastNoteID
+	}
+
+	return "task:" + eventKey(e.TaskID, noteID) + ":" + e.Event
+}
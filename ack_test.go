@@ -0,0 +1,114 @@
+package pyrus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckQueue(t *testing.T) {
+	body, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithClock(clock))
+	require.NoError(t, err)
+
+	store := NewMemoryPendingEventStore()
+	processed := NewMemoryEventStore()
+	q := NewAckQueue(c, store, WithAckTimeout(time.Minute), WithNackDelay(10*time.Second),
+		WithAckProcessedStore(processed))
+
+	ts := httptest.NewServer(q.Handler())
+	defer ts.Close()
+
+	deliver := func() {
+		resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, body))
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	t.Run("redelivered after timeout", func(t *testing.T) {
+		deliver()
+		// Redelivery of the same webhook by Pyrus is stored once
+		deliver()
+
+		d := <-q.Deliveries()
+		assert.Equal(t, 1, d.Attempt)
+		<-q.Deliveries()
+
+		pending, err := store.List()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		require.NoError(t, q.redeliverDue(context.Background()))
+		assert.Empty(t, q.Deliveries())
+
+		clock.Advance(time.Minute)
+		require.NoError(t, q.redeliverDue(context.Background()))
+
+		d = <-q.Deliveries()
+		assert.Equal(t, 2, d.Attempt)
+		assert.Equal(t, d.Event.TaskID, pending[0].Event.TaskID)
+
+		require.NoError(t, d.Nack())
+		clock.Advance(10 * time.Second)
+		require.NoError(t, q.redeliverDue(context.Background()))
+
+		d = <-q.Deliveries()
+		assert.Equal(t, 3, d.Attempt)
+		require.NoError(t, d.Ack())
+
+		pending, err = store.List()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		ok, err := processed.Processed(d.Event.TaskID, d.Event.Task.LastNoteID)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("pending events are delivered on run", func(t *testing.T) {
+		q := NewAckQueue(c, store, WithAckCheckInterval(time.Hour))
+		require.NoError(t, store.Put(&PendingEvent{
+			ID:       "left",
+			Attempts: 1,
+			Deadline: clock.Now().Add(time.Hour),
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- q.Run(ctx)
+		}()
+
+		d := <-q.Deliveries()
+		assert.Equal(t, 2, d.Attempt)
+		require.NoError(t, d.Ack())
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("invalid check interval", func(t *testing.T) {
+		q := NewAckQueue(c, store, WithAckCheckInterval(0))
+		assert.EqualError(t, q.Run(context.Background()), "ack check interval must be positive, got 0s")
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		req := signedWebhookRequest(t, ts.URL, body)
+		req.Header.Set("X-Pyrus-Sig", "invalid")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,30 @@
+// Package pyrusecho exposes pyrus webhook handler for echo.
+package pyrusecho
+
+import (
+	"github.com/L11R/pyrusapi-go"
+	"github.com/labstack/echo/v4"
+)
+
+// Router is implemented by both *echo.Echo and *echo.Group.
+type Router interface {
+	Any(path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) []*echo.Route
+}
+
+// WebhookHandler returns echo handler built on top of Client.WebhookHTTPHandler and channel with Event's.
+// Signature is calculated over the raw body, so the request must not be bound before the handler.
+// Middlewares restoring the body after reading it, e.g. BodyDump, are fine.
+func WebhookHandler(c *pyrus.Client) (echo.HandlerFunc, <-chan pyrus.Event) {
+	handler, events := c.WebhookHTTPHandler()
+
+	return echo.WrapHandler(handler), events
+}
+
+// MountWebhook registers WebhookHandler in the router by the path for all methods, so requests with methods
+// other than POST get 405 like with Client.MountWebhook, and returns Event chan.
+func MountWebhook(c *pyrus.Client, path string, r Router) <-chan pyrus.Event {
+	handler, events := WebhookHandler(c)
+	r.Any(path, handler)
+
+	return events
+}
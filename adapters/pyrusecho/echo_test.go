@@ -0,0 +1,78 @@
+package pyrusecho
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/L11R/pyrusapi-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityKey = "key"
+
+func signedRequest(t *testing.T, method string, body []byte) *http.Request {
+	hasher := hmac.New(sha1.New, []byte(securityKey))
+	_, err := hasher.Write(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, "/pyrus", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pyrus.WebhookSignatureHeader, hex.EncodeToString(hasher.Sum(nil)))
+
+	return req
+}
+
+func TestMountWebhook(t *testing.T) {
+	b, err := os.ReadFile("../../testdata/event.json")
+	require.NoError(t, err)
+
+	c, err := pyrus.NewClient("login", securityKey)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	t.Run("raw body", func(t *testing.T) {
+		e := echo.New()
+		events := MountWebhook(c, "/pyrus", e)
+
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, signedRequest(t, http.MethodPost, b))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("body dumped by middleware", func(t *testing.T) {
+		e := echo.New()
+		var dumped []byte
+		events := MountWebhook(c, "/pyrus", e.Group("", middleware.BodyDump(func(_ echo.Context, req, _ []byte) {
+			dumped = req
+		})))
+
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, signedRequest(t, http.MethodPost, b))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, b, dumped)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("other methods", func(t *testing.T) {
+		e := echo.New()
+		MountWebhook(c, "/pyrus", e)
+
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, signedRequest(t, http.MethodGet, b))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
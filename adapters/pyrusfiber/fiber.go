@@ -0,0 +1,27 @@
+// Package pyrusfiber exposes pyrus webhook handler for fiber.
+package pyrusfiber
+
+import (
+	"github.com/L11R/pyrusapi-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// WebhookHandler returns fiber handler built on top of Client.WebhookHTTPHandler and channel with Event's.
+// Fiber has no *http.Request, so the request is converted from the raw body kept by fasthttp and the signature
+// is checked against exactly what Pyrus has sent. Note that fasthttp rejects bodies larger than
+// fiber.Config.BodyLimit (4 MB by default) before the handler is called.
+func WebhookHandler(c *pyrus.Client) (fiber.Handler, <-chan pyrus.Event) {
+	handler, events := c.WebhookHTTPHandler()
+
+	return adaptor.HTTPHandler(handler), events
+}
+
+// MountWebhook registers WebhookHandler in the router by the path for all methods, so requests with methods
+// other than POST get 405 like with Client.MountWebhook, and returns Event chan.
+func MountWebhook(c *pyrus.Client, path string, r fiber.Router) <-chan pyrus.Event {
+	handler, events := WebhookHandler(c)
+	r.All(path, handler)
+
+	return events
+}
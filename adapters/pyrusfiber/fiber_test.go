@@ -0,0 +1,77 @@
+package pyrusfiber
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/L11R/pyrusapi-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityKey = "key"
+
+func signedRequest(t *testing.T, method string, body []byte) *http.Request {
+	hasher := hmac.New(sha1.New, []byte(securityKey))
+	_, err := hasher.Write(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, "/pyrus", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pyrus.WebhookSignatureHeader, hex.EncodeToString(hasher.Sum(nil)))
+
+	return req
+}
+
+func TestMountWebhook(t *testing.T) {
+	b, err := os.ReadFile("../../testdata/event.json")
+	require.NoError(t, err)
+
+	c, err := pyrus.NewClient("login", securityKey)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	t.Run("raw body", func(t *testing.T) {
+		app := fiber.New()
+		events := MountWebhook(c, "/pyrus", app)
+
+		resp, err := app.Test(signedRequest(t, http.MethodPost, b))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("body read by middleware", func(t *testing.T) {
+		app := fiber.New()
+		events := MountWebhook(c, "/pyrus", app.Group("", func(ctx *fiber.Ctx) error {
+			// fiber keeps the body, so reading it in a middleware doesn't break the signature
+			assert.Equal(t, b, ctx.Body())
+			return ctx.Next()
+		}))
+
+		resp, err := app.Test(signedRequest(t, http.MethodPost, b))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("other methods", func(t *testing.T) {
+		app := fiber.New()
+		MountWebhook(c, "/pyrus", app)
+
+		resp, err := app.Test(signedRequest(t, http.MethodGet, b))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
@@ -0,0 +1,37 @@
+// Package pyrusgin exposes pyrus webhook handler for gin.
+package pyrusgin
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/L11R/pyrusapi-go"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler returns gin handler built on top of Client.WebhookHTTPHandler and channel with Event's.
+// Signature is calculated over the raw body, so if it was already read by ShouldBindBodyWith, the copy kept
+// in the context is used instead. Other binding must not happen before the handler.
+func WebhookHandler(c *pyrus.Client) (gin.HandlerFunc, <-chan pyrus.Event) {
+	handler, events := c.WebhookHTTPHandler()
+
+	return func(ctx *gin.Context) {
+		if b, ok := ctx.Get(gin.BodyBytesKey); ok {
+			if body, ok := b.([]byte); ok {
+				ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+				ctx.Request.ContentLength = int64(len(body))
+			}
+		}
+
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+	}, events
+}
+
+// MountWebhook registers WebhookHandler in the router by the path for all methods, so requests with methods
+// other than POST get 405 like with Client.MountWebhook, and returns Event chan.
+func MountWebhook(c *pyrus.Client, path string, r gin.IRoutes) <-chan pyrus.Event {
+	handler, events := WebhookHandler(c)
+	r.Any(path, handler)
+
+	return events
+}
@@ -0,0 +1,81 @@
+package pyrusgin
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/L11R/pyrusapi-go"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityKey = "key"
+
+func signedRequest(t *testing.T, method string, body []byte) *http.Request {
+	hasher := hmac.New(sha1.New, []byte(securityKey))
+	_, err := hasher.Write(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, "/pyrus", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pyrus.WebhookSignatureHeader, hex.EncodeToString(hasher.Sum(nil)))
+
+	return req
+}
+
+func TestMountWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	b, err := os.ReadFile("../../testdata/event.json")
+	require.NoError(t, err)
+
+	c, err := pyrus.NewClient("login", securityKey)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	t.Run("raw body", func(t *testing.T) {
+		r := gin.New()
+		events := MountWebhook(c, "/pyrus", r)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, signedRequest(t, http.MethodPost, b))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("body bound by middleware", func(t *testing.T) {
+		r := gin.New()
+		r.Use(func(ctx *gin.Context) {
+			var v map[string]interface{}
+			require.NoError(t, ctx.ShouldBindBodyWith(&v, binding.JSON))
+			ctx.Next()
+		})
+		events := MountWebhook(c, "/pyrus", r)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, signedRequest(t, http.MethodPost, b))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		event := <-events
+		assert.NotNil(t, event.Task)
+	})
+
+	t.Run("other methods", func(t *testing.T) {
+		r := gin.New()
+		MountWebhook(c, "/pyrus", r)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, signedRequest(t, http.MethodGet, b))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -2,12 +2,12 @@ package pyrus
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha1"
-	"crypto/subtle"
-	"encoding/hex"
+	"compress/gzip"
+	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"mime"
 	"mime/multipart"
@@ -16,47 +16,173 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+// WebhookSignatureHeader is the header containing HMAC-SHA1 signature of the webhook body.
+const WebhookSignatureHeader = "X-Pyrus-Sig"
+
+// ErrInvalidSignature is returned by ParseWebhook if the signature doesn't match the body.
+var ErrInvalidSignature = errors.New("invalid signature")
+
 const (
 	baseURL   = "https://api.pyrus.com/v4"
 	userAgent = "Pyrus API golang client v0.0.1"
+
+	// defaultTaskListPageSize is the item_count used by API by default.
+	defaultTaskListPageSize = 200
 )
 
 type Client struct {
-	baseURL string
+	clientConfig
 
 	login       string
 	securityKey string
 
 	accessToken string
+	staticToken bool
 	mu          sync.RWMutex
 
+	webhookSem chan struct{}
+
+	droppedMu sync.Mutex
+	dropped   DroppedEvents
+
+	cache ResponseCache
+
+	quota   QuotaStatus
+	quotaMu sync.RWMutex
+
+	inflight *requestGroup
+
+	closed        chan struct{}
+	closeOnce     sync.Once
+	webhookMu     sync.RWMutex
+	webhookChans  []chan Event
+	webhookClosed bool
+
+	debugDump    io.Writer
+	debugEnabled int32
+	debugMu      sync.Mutex
+}
+
+// clientConfig holds settings of Client shared with clients derived from it, e.g. by EventClient.
+// It must not contain locks or per-client state, since it's copied as a whole.
+type clientConfig struct {
+	baseURL     string
+	apiVersion  string
+	authBaseURL string
+	fileBaseURL string
+
 	logger          Logger
 	httpClient      *http.Client
 	eventBufferSize int
+
+	webhookLimit int
+	webhookWait  time.Duration
+
+	webhookFilters    []func(e Event) bool
+	webhookSecrets    []string
+	webhookSchemaMode WebhookSchemaMode
+
+	webhookMaxBodySize int64
+
+	validationMode ValidationMode
+	validator      func(req interface{}) error
+
+	eventSendTimeout time.Duration
+	onEventDropped   func(e Event, reason EventDropReason)
+
+	cacheTTL  time.Duration
+	cacheTTLs map[CacheClass]time.Duration
+
+	gzipMinSize int
+
+	timeout          time.Duration
+	endpointTimeouts map[string]time.Duration
+
+	errorLanguage Language
+
+	quotaHook func(QuotaStatus)
+
+	stats StatsCollector
+	usage *usageTracker
+
+	throttle *adaptiveThrottle
+
+	uploadIndex UploadIndex
+
+	rawResponses      bool
+	lazyFieldDecoding bool
+
+	rootCAs            *x509.CertPool
+	caCertFiles        []string
+	insecureSkipVerify bool
+	clientCertPEM      []byte
+	clientKeyPEM       []byte
+	clientCertFile     string
+	clientKeyFile      string
+
+	clock Clock
 }
 
 // IClient is the main interface. Provided to implement dummy implementations useful for testing.
+// It's composed of per-domain interfaces, so consumers could depend on and mock only what they use.
 type IClient interface {
 	Auth(login, securityKey string) (string, error)
+	FormService
+	TaskService
+	AnnouncementService
+	FileService
+	CatalogService
+	MemberService
+	CallService
+	WebhookService
+}
+
+// FormService is a part of IClient working with forms.
+type FormService interface {
 	Forms() (*FormsResponse, error)
 	Form(formID int) (*FormResponse, error)
 	Registry(formID int, req *RegistryRequest) (*FormRegisterResponse, error)
+}
+
+// TaskService is a part of IClient working with tasks and task lists.
+type TaskService interface {
 	Task(taskID int) (*TaskResponse, error)
 	CreateTask(req *TaskRequest) (*TaskResponse, error)
 	CommentTask(taskID int, req *TaskCommentRequest) (*TaskResponse, error)
+	DownloadPrintForm(taskID, printFormID int) (*DownloadResponse, error)
+	Lists() (*ListsResponses, error)
+	TaskList(listID, itemCount int, includeArchived bool) (*TaskListResponse, error)
+	Inbox(itemCount int) (*TaskListResponse, error)
+}
+
+// AnnouncementService is a part of IClient working with announcements.
+type AnnouncementService interface {
 	Announcement(announcementID int) (*AnnouncementResponse, error)
 	CreateAnnouncement(req *AnnouncementRequest) (*AnnouncementResponse, error)
 	CommentAnnouncement(announcementID int, req *AnnouncementCommentRequest) (*AnnouncementResponse, error)
+}
+
+// FileService is a part of IClient working with files.
+type FileService interface {
 	UploadFile(name string, file io.Reader) (*UploadResponse, error)
 	DownloadFile(fileID int) (*DownloadResponse, error)
+}
+
+// CatalogService is a part of IClient working with catalogs.
+type CatalogService interface {
 	Catalogs() (*CatalogsResponse, error)
 	Catalog(catalogID int) (*CatalogResponse, error)
 	CreateCatalog(name string, headers []string, items []*CatalogItem) (*CatalogResponse, error)
 	SyncCatalog(catalogID int, apply bool, headers []string, items []*CatalogItem) (*SyncCatalogResponse, error)
+}
+
+// MemberService is a part of IClient working with members, roles and contacts of organization.
+type MemberService interface {
 	Contacts(includeInactive bool) (*ContactsResponse, error)
 	Members() (*MembersResponse, error)
 	CreateMember(req *MemberRequest) (*Member, error)
@@ -66,12 +192,17 @@ type IClient interface {
 	CreateRole(name string, members []int) (*Role, error)
 	UpdateRole(roleID int, name string, add, remove []int, banned bool) (*Role, error)
 	Profile() (*ProfileResponse, error)
-	Lists() (*ListsResponses, error)
-	TaskList(listID, itemCount int, includeArchived bool) (*TaskListResponse, error)
-	Inbox(itemCount int) (*TaskListResponse, error)
+}
+
+// CallService is a part of IClient implementing calls API.
+type CallService interface {
 	RegisterCall(req *RegisterCallRequest) (*RegisterCallResponse, error)
 	AddCallDetails(callGUID string, req *AddCallDetailsRequest) error
 	RegisterCallEvent(callGUID string, eventType CallEventType, extension string) error
+}
+
+// WebhookService is a part of IClient receiving webhooks.
+type WebhookService interface {
 	WebhookHandler() (http.HandlerFunc, <-chan Event)
 }
 
@@ -106,33 +237,119 @@ func WithEventBufferSize(size int) Option {
 	}
 }
 
+// WithRequestCompression enables gzip compression of JSON request bodies larger than minSize bytes.
+// Responses are always requested and decompressed with gzip.
+func WithRequestCompression(minSize int) Option {
+	return func(c *Client) {
+		c.gzipMinSize = minSize
+	}
+}
+
+// WithTimeout allows to set default timeout of every request including reading of the response body.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithEndpointTimeout allows to override default timeout for API paths starting with the prefix,
+// e.g. "/files/upload" or "/files/download". The longest matching prefix wins.
+func WithEndpointTimeout(pathPrefix string, d time.Duration) Option {
+	return func(c *Client) {
+		if c.endpointTimeouts == nil {
+			c.endpointTimeouts = make(map[string]time.Duration)
+		}
+		c.endpointTimeouts[pathPrefix] = d
+	}
+}
+
 func WithBaseURL(baseURL string) Option {
 	return func(c *Client) {
 		c.baseURL = baseURL
 	}
 }
 
-// NewClient returns an instance of Client.
+// NewClient returns an instance of Client or an error if login, security key or options are invalid.
 func NewClient(login, securityKey string, opts ...Option) (*Client, error) {
 	c := &Client{
-		baseURL: baseURL,
+		clientConfig: clientConfig{
+			baseURL: baseURL,
+
+			logger:          &noopLogger{},
+			httpClient:      http.DefaultClient,
+			eventBufferSize: 100,
+
+			webhookMaxBodySize: defaultWebhookMaxBodySize,
+			usage:              &usageTracker{},
+			clock:              systemClock{},
+		},
 
 		login:       login,
 		securityKey: securityKey,
 
-		logger:          &noopLogger{},
-		httpClient:      http.DefaultClient,
-		eventBufferSize: 100,
+		closed: make(chan struct{}),
 	}
 
 	// Apply optional opts
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.logger = &redactingLogger{next: c.logger, redact: c.redact}
+
+	if err := c.validateConfig(); err != nil {
+		return nil, err
+	}
+	if err := c.configureTLS(); err != nil {
+		return nil, err
+	}
 
 	return c, nil
 }
 
+// validateConfig checks the client configuration, so mistakes are reported by NewClient instead of the first call.
+func (c *Client) validateConfig() error {
+	if c.login == "" {
+		return errors.New("login is required")
+	}
+	if c.securityKey == "" {
+		return errors.New("security key is required")
+	}
+	if c.httpClient == nil {
+		return errors.New("http client must not be nil")
+	}
+	if c.clock == nil {
+		return errors.New("clock must not be nil")
+	}
+	if c.eventBufferSize < 0 {
+		return fmt.Errorf("event buffer size must not be negative, got %d", c.eventBufferSize)
+	}
+	if c.webhookLimit < 0 {
+		return fmt.Errorf("webhook concurrency limit must not be negative, got %d", c.webhookLimit)
+	}
+	if c.throttle != nil && c.throttle.maxRate <= 0 {
+		return fmt.Errorf("throttling rate must be positive, got %v", c.throttle.maxRate)
+	}
+
+	urls := []struct {
+		name, value string
+	}{
+		{"base URL", c.baseURL},
+		{"auth base URL", c.authBaseURL},
+		{"file base URL", c.fileBaseURL},
+	}
+	for i, v := range urls {
+		if i > 0 && v.value == "" {
+			continue
+		}
+		u, err := url.Parse(v.value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s must be http or https URL, got %q", v.name, v.value)
+		}
+	}
+
+	return nil
+}
+
 func (c *Client) getAndSetAccessToken() error {
 	accessToken, err := c.Auth(c.login, c.securityKey)
 	if err != nil {
@@ -147,12 +364,49 @@ func (c *Client) getAndSetAccessToken() error {
 }
 
 func (c *Client) performRequest(method, path string, q *url.Values, reqBody, respBody interface{}) error {
+	return c.performRequestContext(context.Background(), method, path, q, reqBody, respBody)
+}
+
+func (c *Client) performRequestContext(ctx context.Context, method, path string, q *url.Values, reqBody, respBody interface{}) error {
+	if c.coalescable(method, reqBody, respBody) {
+		return c.performCoalesced(ctx, method, path, q, respBody)
+	}
+
+	return c.performAttempt(ctx, method, path, q, reqBody, respBody, 1)
+}
+
+// performAttempt performs the request and wraps an error with the request context.
+func (c *Client) performAttempt(ctx context.Context, method, path string, q *url.Values, reqBody, respBody interface{}, attempt int) error {
+	err := c.doRequest(ctx, method, path, q, reqBody, respBody, attempt)
+	if err == nil {
+		return nil
+	}
+
+	// Already wrapped by the nested attempt or authorization
+	var re *RequestError
+	if errors.As(err, &re) {
+		return err
+	}
+
+	return &RequestError{
+		Method:  method,
+		Path:    path,
+		Attempt: attempt,
+		Err:     err,
+	}
+}
+
+func (c *Client) doRequest(ctx context.Context, method, path string, q *url.Values, reqBody, respBody interface{}, attempt int) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
 	auth := false
 	if path == "/auth" {
 		auth = true
 	}
 
-	u, err := url.Parse(c.baseURL + path)
+	u, err := url.Parse(c.requestBaseURLFor(ctx, path) + path)
 	if err != nil {
 		c.logger.Error("Error while parsing a URL!", err)
 		return err
@@ -171,25 +425,32 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 		reqErr error
 	)
 	contentTypeHeader := "application/json"
+	contentEncodingHeader := ""
 	if multipartRequest {
-		buf := bytes.NewBuffer(nil)
-
-		w := multipart.NewWriter(buf)
-		fw, err := w.CreateFormFile("file", reqBody.(*fileRequest).Filename)
-		if err != nil {
-			c.logger.Error("Error while creating a new form file!", err)
-			return err
+		// File is streamed through the pipe, so it's never kept in memory
+		pr, pw := io.Pipe()
+		defer pr.Close() //nolint:errcheck
+
+		// Transport waits for the body until it's written, so the pipe is closed to abort reads of a slow file
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				pr.CloseWithError(ctx.Err()) //nolint:errcheck
+			case <-stop:
+			}
+		}()
+
+		w := multipart.NewWriter(pw)
+		go func() {
+			pw.CloseWithError(c.writeMultipartFile(ctx, w, reqBody.(*fileRequest))) //nolint:errcheck
+		}()
+
+		req, reqErr = http.NewRequestWithContext(ctx, method, u.String(), pr)
+		if reqErr == nil {
+			req.ContentLength = multipartLength(w.Boundary(), reqBody.(*fileRequest))
 		}
-		if _, err := io.Copy(fw, reqBody.(*fileRequest).Reader); err != nil {
-			c.logger.Error("Error while writing a file!", err)
-			return err
-		}
-		if err := w.Close(); err != nil {
-			c.logger.Error("Error while trying to close multipart writer!", err)
-			return err
-		}
-
-		req, reqErr = http.NewRequest(method, u.String(), buf)
 		contentTypeHeader = w.FormDataContentType()
 	} else if reqBody != nil {
 		buf := bytes.NewBuffer(nil)
@@ -198,17 +459,43 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 			return err
 		}
 
-		req, reqErr = http.NewRequest(method, u.String(), buf)
+		if c.gzipMinSize > 0 && buf.Len() >= c.gzipMinSize {
+			compressed := bytes.NewBuffer(nil)
+			gw := gzip.NewWriter(compressed)
+			if _, err := gw.Write(buf.Bytes()); err != nil {
+				c.logger.Error("Error while compressing a request body!", err)
+				return err
+			}
+			if err := gw.Close(); err != nil {
+				c.logger.Error("Error while compressing a request body!", err)
+				return err
+			}
+
+			buf = compressed
+			contentEncodingHeader = "gzip"
+		}
+
+		req, reqErr = http.NewRequestWithContext(ctx, method, u.String(), buf)
 	} else {
-		req, reqErr = http.NewRequest(method, u.String(), nil)
+		req, reqErr = http.NewRequestWithContext(ctx, method, u.String(), nil)
 	}
 	if reqErr != nil {
-		c.logger.Error("Error while creating a request!", err)
-		return err
+		c.logger.Error("Error while creating a request!", reqErr)
+		return reqErr
+	}
+
+	if timeout := c.timeoutFor(path); timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
 	}
 
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("Content-Type", contentTypeHeader)
+	req.Header.Set("Accept-Encoding", "gzip")
+	if contentEncodingHeader != "" {
+		req.Header.Set("Content-Encoding", contentEncodingHeader)
+	}
 
 	// It's wise to get first token without unnecessary request
 	c.mu.RLock()
@@ -226,20 +513,99 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 	}
 	c.mu.RUnlock()
 
+	var (
+		cacheKey string
+		cached   *CachedResponse
+	)
+	cacheTTL, cacheable := c.cacheTTLFor(path)
+	if cacheable {
+		cacheKey = u.String()
+	}
+	if cacheKey != "" && method == http.MethodGet {
+		if cr, ok := c.cache.Get(cacheKey); ok {
+			if c.clock.Now().Before(cr.Expires) {
+				return c.decodeCached(cr.Body, respBody)
+			}
+
+			cached = cr
+			if cr.ETag != "" {
+				req.Header.Set("If-None-Match", cr.ETag)
+			}
+			if cr.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cr.LastModified)
+			}
+		}
+	}
+
+	if c.debugging() {
+		c.dumpRequest(req)
+	}
+
+	if err := c.waitThrottle(ctx); err != nil {
+		return err
+	}
+
+	start := c.clock.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.observeRequest(method, path, 0, start, 0)
 		c.logger.Error("Error while doing a request!", err)
 		return err
 	}
 	defer resp.Body.Close() //nolint:errcheck
+	if c.debugging() {
+		c.dumpResponse(resp)
+	}
+	c.updateQuota(resp.Header)
+	c.observeThrottle(resp.StatusCode, resp.Header)
+
+	counter := &countingReader{r: resp.Body}
+	defer func() {
+		c.observeRequest(method, path, resp.StatusCode, start, counter.n)
+	}()
+
+	// Accept-Encoding is set explicitly, so transport doesn't decompress the body itself
+	var body io.Reader = counter
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gr, err := gzip.NewReader(counter)
+		if err != nil && err != io.EOF {
+			c.logger.Error("Error while decompressing a response body!", err)
+			return err
+		}
+		if err == nil {
+			defer gr.Close() //nolint:errcheck
+			body = gr
+		}
+	}
+
+	body = responseCharsetReader(ctx, body)
+
+	if cacheKey != "" && method != http.MethodGet {
+		// Modified entities and their lists have to be downloaded again
+		c.cache.Delete(cacheKey)
+		if class, ok := cacheClassOf(path); ok {
+			c.cache.Delete(c.requestBaseURLFor(ctx, path) + "/" + string(class))
+		}
+		cacheKey = ""
+	}
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		c.cache.Set(cacheKey, &CachedResponse{
+			Body:         cached.Body,
+			ETag:         cached.ETag,
+			LastModified: cached.LastModified,
+			Expires:      c.clock.Now().Add(cacheTTL),
+		})
+
+		return c.decodeCached(cached.Body, respBody)
+	}
 
 	// Get new access_token in case of old session
-	if resp.StatusCode == 401 && !auth {
+	if resp.StatusCode == 401 && !auth && !c.staticToken {
 		if err := c.getAndSetAccessToken(); err != nil {
 			return err
 		}
 
-		return c.performRequest(method, path, q, reqBody, respBody)
+		return c.performAttempt(ctx, method, path, q, reqBody, respBody, attempt+1)
 	}
 
 	// Don't read if there is no need in response body at all
@@ -271,7 +637,7 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 			return errors.New("writer was expected")
 		}
 
-		if _, err := io.Copy(w, resp.Body); err != nil {
+		if _, err := io.Copy(w, &contextReader{ctx: ctx, r: body}); err != nil {
 			c.logger.Error("Error while trying to download file!", err)
 			return err
 		}
@@ -279,17 +645,61 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 		return nil
 	}
 
-	decoder := json.NewDecoder(resp.Body)
+	decoder := json.NewDecoder(body)
 	if resp.StatusCode != 200 {
-		var pe Error
+		pe := Error{StatusCode: resp.StatusCode}
 		if err := decoder.Decode(&pe); err != nil {
 			c.logger.Error("Error while decoding a response body!", err)
 			return err
 		}
+		if c.errorLanguage != "" {
+			pe.localize(c.errorLanguage)
+		}
 
 		return pe
 	}
 
+	if cacheKey != "" {
+		body, err := io.ReadAll(body)
+		if err != nil {
+			c.logger.Error("Error while reading a response body!", err)
+			return err
+		}
+		if err := c.unmarshal(body, &respBody); err != nil {
+			c.logger.Error("Error while decoding a response body!", err)
+			return err
+		}
+		if c.rawResponses {
+			attachRaw(respBody, body)
+		}
+
+		c.cache.Set(cacheKey, &CachedResponse{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      c.clock.Now().Add(cacheTTL),
+		})
+
+		return nil
+	}
+
+	if c.rawResponses || c.lazyFieldDecoding {
+		body, err := io.ReadAll(body)
+		if err != nil {
+			c.logger.Error("Error while reading a response body!", err)
+			return err
+		}
+		if err := c.unmarshal(body, &respBody); err != nil {
+			c.logger.Error("Error while decoding a response body!", err)
+			return err
+		}
+		if c.rawResponses {
+			attachRaw(respBody, body)
+		}
+
+		return nil
+	}
+
 	if err := decoder.Decode(&respBody); err != nil {
 		c.logger.Error("Error while decoding a response body!", err)
 		return err
@@ -298,6 +708,43 @@ func (c *Client) performRequest(method, path string, q *url.Values, reqBody, res
 	return nil
 }
 
+// decodeCached decodes the cached response body.
+func (c *Client) decodeCached(body []byte, respBody interface{}) error {
+	if err := c.unmarshal(body, &respBody); err != nil {
+		return err
+	}
+	if c.rawResponses {
+		attachRaw(respBody, body)
+	}
+
+	return nil
+}
+
+// unmarshal decodes the response body or webhook payload according to WithLazyFieldDecoding.
+func (c *Client) unmarshal(data []byte, v interface{}) error {
+	if c.lazyFieldDecoding {
+		// Responses are passed as *interface{}, but lazy decoding depends on the type of the response
+		if p, ok := v.(*interface{}); ok && *p != nil {
+			v = *p
+		}
+		return unmarshalLazy(data, v)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// timeoutFor returns the timeout of the path.
+func (c *Client) timeoutFor(path string) time.Duration {
+	timeout, matched := c.timeout, -1
+	for prefix, d := range c.endpointTimeouts {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matched {
+			timeout, matched = d, len(prefix)
+		}
+	}
+
+	return timeout
+}
+
 // Auth performs authorization and returns access_token.
 func (c *Client) Auth(login, securityKey string) (string, error) {
 	var respBody AuthResponse
@@ -334,9 +781,19 @@ func (c *Client) Form(formID int) (*FormResponse, error) {
 // Registry returns the list of tasks that were created based on the specified form.
 // The response only contains general information about the task, like the list of filled form fields and its workflow.
 // You can use Task method to get all task comments.
+// CSV registry in windows-1251 encoding is converted to UTF-8.
 func (c *Client) Registry(formID int, req *RegistryRequest) (*FormRegisterResponse, error) {
+	if err := c.validate(req); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if req != nil {
+		ctx = contextWithResponseCharset(ctx, req.Encoding)
+	}
+
 	var tasks FormRegisterResponse
-	if err := c.performRequest(http.MethodPost, "/forms/"+strconv.Itoa(formID)+"/register", nil, req, &tasks); err != nil {
+	if err := c.performRequestContext(ctx, http.MethodPost, "/forms/"+strconv.Itoa(formID)+"/register", nil, req, &tasks); err != nil {
 		return nil, err
 	}
 
@@ -355,7 +812,7 @@ func (c *Client) Task(taskID int) (*TaskResponse, error) {
 
 // CreateTask creates a task and returns it with a comment.
 func (c *Client) CreateTask(req *TaskRequest) (*TaskResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := c.validate(req); err != nil {
 		return nil, err
 	}
 
@@ -369,10 +826,24 @@ func (c *Client) CreateTask(req *TaskRequest) (*TaskResponse, error) {
 
 // CommentTask comments a task and returns it with all comments, including the added one.
 func (c *Client) CommentTask(taskID int, req *TaskCommentRequest) (*TaskResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := c.validate(req); err != nil {
 		return nil, err
 	}
 
+	if req.ExpectedLastNoteID != 0 {
+		current, err := c.Task(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Task != nil && current.Task.LastNoteID != req.ExpectedLastNoteID {
+			return nil, &ConflictError{
+				TaskID:             taskID,
+				ExpectedLastNoteID: req.ExpectedLastNoteID,
+				LastNoteID:         current.Task.LastNoteID,
+			}
+		}
+	}
+
 	var task TaskResponse
 	if err := c.performRequest(http.MethodPost, "/tasks/"+strconv.Itoa(taskID)+"/comments", nil, req, &task); err != nil {
 		return nil, err
@@ -393,7 +864,7 @@ func (c *Client) Announcement(announcementID int) (*AnnouncementResponse, error)
 
 // CreateAnnouncement creates an announcement and returns it with a comment.
 func (c *Client) CreateAnnouncement(req *AnnouncementRequest) (*AnnouncementResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := c.validate(req); err != nil {
 		return nil, err
 	}
 
@@ -405,9 +876,9 @@ func (c *Client) CreateAnnouncement(req *AnnouncementRequest) (*AnnouncementResp
 	return &announcement, nil
 }
 
-// CommentAnnouncement comments a task and returns it with all comments, including the added one.
+// CommentAnnouncement comments an announcement and returns it with all comments, including the added one.
 func (c *Client) CommentAnnouncement(announcementID int, req *AnnouncementCommentRequest) (*AnnouncementResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := c.validate(req); err != nil {
 		return nil, err
 	}
 
@@ -421,24 +892,105 @@ func (c *Client) CommentAnnouncement(announcementID int, req *AnnouncementCommen
 
 // UploadFile uploads files for subsequent attachment to tasks.
 // Files that are not referenced by any task are removed after a while.
+// Files known to exceed MaxUploadSize are rejected with ErrTooLargeRequestLength before the upload starts.
+// If the size of the file is known, the request is sent with Content-Length instead of chunked encoding.
 func (c *Client) UploadFile(name string, file io.Reader) (*UploadResponse, error) {
-	var upload UploadResponse
-	if err := c.performRequest(http.MethodPost, "/files/upload", nil, &fileRequest{
+	return c.UploadFileContext(context.Background(), name, file)
+}
+
+// UploadFileWithOptions is like UploadFile, but accepts UploadOption, e.g. WithUploadSize.
+func (c *Client) UploadFileWithOptions(name string, file io.Reader, opts ...UploadOption) (*UploadResponse, error) {
+	return c.UploadFileContext(context.Background(), name, file, opts...)
+}
+
+// UploadFileContext is like UploadFile, but the upload is aborted as soon as the context is done.
+func (c *Client) UploadFileContext(ctx context.Context, name string, file io.Reader, opts ...UploadOption) (*UploadResponse, error) {
+	req := &fileRequest{
 		Filename: name,
 		Reader:   file,
-	}, &upload); err != nil {
+		Size:     -1,
+	}
+	if size, ok := readerSize(file); ok {
+		req.Size = size
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	if err := c.checkUploadSize(req.Size); err != nil {
+		return nil, err
+	}
+
+	var hr *hashingReader
+	if c.uploadIndex != nil {
+		if rs, ok := file.(io.ReadSeeker); ok {
+			md5Hash, size, err := hashSeeker(rs)
+			if err != nil {
+				c.logger.Error("Error while hashing a file!", err)
+				return nil, err
+			}
+			if guid, ok := c.uploadIndex.Get(uploadKey(md5Hash, size)); ok {
+				return &UploadResponse{GUID: guid, MD5Hash: md5Hash, Deduplicated: true}, nil
+			}
+		}
+
+		hr = newHashingReader(file)
+		req.Reader = hr
+	}
+
+	var upload UploadResponse
+	if err := c.performRequestContext(ctx, http.MethodPost, "/files/upload", nil, req, &upload); err != nil {
+		// Aborted upload may surface as an error of the torn down body pipe instead of the context one
+		if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+			var reqErr *RequestError
+			if errors.As(err, &reqErr) {
+				wrapped := *reqErr
+				wrapped.Err = ctxErr
+				return nil, &wrapped
+			}
+			return nil, ctxErr
+		}
 		return nil, err
 	}
 
+	// File is indexed only if Pyrus received exactly what was sent
+	if hr != nil && upload.GUID != "" && uploadKey(upload.MD5Hash, hr.n) == hr.key() {
+		c.uploadIndex.Put(hr.key(), upload.GUID)
+	}
+
 	return &upload, nil
 }
 
 // DownloadFile downloads file from Pyrus.
 func (c *Client) DownloadFile(fileID int) (*DownloadResponse, error) {
+	return c.DownloadFileContext(context.Background(), fileID)
+}
+
+// DownloadFileContext is like DownloadFile, but the download is aborted as soon as the context is done.
+func (c *Client) DownloadFileContext(ctx context.Context, fileID int) (*DownloadResponse, error) {
 	buf := bytes.NewBuffer(nil)
 
 	var filename string
-	if err := c.performRequest(http.MethodGet, "/files/download/"+strconv.Itoa(fileID), nil, buf, &filename); err != nil {
+	if err := c.performRequestContext(ctx, http.MethodGet, "/files/download/"+strconv.Itoa(fileID), nil, buf, &filename); err != nil {
+		return nil, err
+	}
+
+	return &DownloadResponse{
+		Filename: filename,
+		RawFile:  buf.Bytes(),
+	}, nil
+}
+
+// DownloadPrintForm renders the print form of the task and downloads the generated document.
+// Available print forms are listed in FormResponse.PrintForms.
+func (c *Client) DownloadPrintForm(taskID, printFormID int) (*DownloadResponse, error) {
+	q := &url.Values{}
+	q.Set("print_form_id", strconv.Itoa(printFormID))
+
+	buf := bytes.NewBuffer(nil)
+
+	var filename string
+	if err := c.performRequest(http.MethodGet, "/tasks/"+strconv.Itoa(taskID)+"/print_forms", q, buf, &filename); err != nil {
 		return nil, err
 	}
 
@@ -470,12 +1022,17 @@ func (c *Client) Catalog(catalogID int) (*CatalogResponse, error) {
 
 // CreateCatalog creates a catalog and returns it with all its elements.
 func (c *Client) CreateCatalog(name string, headers []string, items []*CatalogItem) (*CatalogResponse, error) {
-	var catalog CatalogResponse
-	if err := c.performRequest(http.MethodPut, "/catalogs", nil, &catalogRequest{
+	req := &catalogRequest{
 		Name:           name,
 		CatalogHeaders: headers,
 		Items:          items,
-	}, &catalog); err != nil {
+	}
+	if err := c.validate(req); err != nil {
+		return nil, err
+	}
+
+	var catalog CatalogResponse
+	if err := c.performRequest(http.MethodPut, "/catalogs", nil, req, &catalog); err != nil {
 		return nil, err
 	}
 
@@ -484,12 +1041,17 @@ func (c *Client) CreateCatalog(name string, headers []string, items []*CatalogIt
 
 // SyncCatalog updates catalog header and items and returns a list of items that have been added, modified, or deleted.
 func (c *Client) SyncCatalog(catalogID int, apply bool, headers []string, items []*CatalogItem) (*SyncCatalogResponse, error) {
-	var syncCatalog SyncCatalogResponse
-	if err := c.performRequest(http.MethodPost, "/catalogs/"+strconv.Itoa(catalogID), nil, &syncCatalogRequest{
+	req := &syncCatalogRequest{
 		Apply:          apply,
 		CatalogHeaders: headers,
 		Items:          items,
-	}, &syncCatalog); err != nil {
+	}
+	if err := c.validate(req); err != nil {
+		return nil, err
+	}
+
+	var syncCatalog SyncCatalogResponse
+	if err := c.performRequest(http.MethodPost, "/catalogs/"+strconv.Itoa(catalogID), nil, req, &syncCatalog); err != nil {
 		return nil, err
 	}
 
@@ -523,6 +1085,10 @@ func (c *Client) Members() (*MembersResponse, error) {
 
 // CreateMember creates a user and returns it.
 func (c *Client) CreateMember(req *MemberRequest) (*Member, error) {
+	if err := c.validate(req); err != nil {
+		return nil, err
+	}
+
 	var member Member
 	if err := c.performRequest(http.MethodPost, "/members", nil, req, &member); err != nil {
 		return nil, err
@@ -533,6 +1099,10 @@ func (c *Client) CreateMember(req *MemberRequest) (*Member, error) {
 
 // UpdateMember updates a user and returns it.
 func (c *Client) UpdateMember(memberID int, req *MemberRequest) (*Member, error) {
+	if err := c.validate(req); err != nil {
+		return nil, err
+	}
+
 	var member Member
 	if err := c.performRequest(http.MethodPut, "/members/"+strconv.Itoa(memberID), nil, req, &member); err != nil {
 		return nil, err
@@ -610,41 +1180,78 @@ func (c *Client) Lists() (*ListsResponses, error) {
 }
 
 // TaskList returns all the tasks in the specified list.
+// If the list is truncated by itemCount, the response has HasMore flag and allows to get the rest with NextPage.
 func (c *Client) TaskList(listID, itemCount int, includeArchived bool) (*TaskListResponse, error) {
-	q := &url.Values{}
-	if itemCount != 0 {
-		q.Set("item_count", strconv.Itoa(itemCount))
-	}
+	q := url.Values{}
 	if includeArchived {
 		q.Set("include_archived", "y")
 	}
 
-	var taskList TaskListResponse
-	if err := c.performRequest(http.MethodGet, "/lists/"+strconv.Itoa(listID)+"/tasks", q, nil, &taskList); err != nil {
-		return nil, err
-	}
-
-	return &taskList, nil
+	return c.taskListPage("/lists/"+strconv.Itoa(listID)+"/tasks", q, itemCount, make(map[int]struct{}))
 }
 
 // Inbox returns all inbox tasks.
+// If the inbox is truncated by itemCount, the response has HasMore flag and allows to get the rest with NextPage.
 func (c *Client) Inbox(itemCount int) (*TaskListResponse, error) {
-	q := &url.Values{}
+	return c.taskListPage("/inbox", url.Values{}, itemCount, make(map[int]struct{}))
+}
+
+// taskListPage requests the tasks list skipping already seen tasks.
+// The API doesn't support offsets, so every next page is requested with item_count increased by the page size.
+func (c *Client) taskListPage(path string, q url.Values, pageSize int, seen map[int]struct{}) (*TaskListResponse, error) {
+	// Every page works with its own copies, so NextPage of the same page returns the same tasks
+	query := make(url.Values, len(q))
+	for k, v := range q {
+		query[k] = append([]string(nil), v...)
+	}
+	pageSeen := make(map[int]struct{}, len(seen))
+	for id := range seen {
+		pageSeen[id] = struct{}{}
+	}
+	q, seen = query, pageSeen
+
+	itemCount := pageSize
+	if len(seen) > 0 {
+		if pageSize == 0 {
+			pageSize = defaultTaskListPageSize
+		}
+		itemCount = len(seen) + pageSize
+	}
 	if itemCount != 0 {
 		q.Set("item_count", strconv.Itoa(itemCount))
 	}
 
 	var taskList TaskListResponse
-	if err := c.performRequest(http.MethodGet, "/inbox", q, nil, &taskList); err != nil {
+	if err := c.performRequest(http.MethodGet, path, &q, nil, &taskList); err != nil {
 		return nil, err
 	}
 
+	tasks := make([]*TaskHeader, 0, len(taskList.Tasks))
+	for _, t := range taskList.Tasks {
+		if _, ok := seen[t.ID]; ok {
+			continue
+		}
+		seen[t.ID] = struct{}{}
+		tasks = append(tasks, t)
+	}
+
+	// Nothing new means that we can't move further even if API says there is more
+	if len(seen) > 0 && len(tasks) == 0 {
+		taskList.HasMore = false
+		taskList.HasMode = false
+	}
+
+	taskList.Tasks = tasks
+	taskList.nextPage = func() (*TaskListResponse, error) {
+		return c.taskListPage(path, q, pageSize, seen)
+	}
+
 	return &taskList, nil
 }
 
 // RegisterCall returns the GUID of the incoming call, and the id of the generated request.
 func (c *Client) RegisterCall(req *RegisterCallRequest) (*RegisterCallResponse, error) {
-	if err := req.Validate(); err != nil {
+	if err := c.validate(req); err != nil {
 		return nil, err
 	}
 
@@ -658,6 +1265,10 @@ func (c *Client) RegisterCall(req *RegisterCallRequest) (*RegisterCallResponse,
 
 // AddCallDetails adds call details by call_guid.
 func (c *Client) AddCallDetails(callGUID string, req *AddCallDetailsRequest) error {
+	if err := c.validate(req); err != nil {
+		return err
+	}
+
 	if err := c.performRequest(http.MethodPut, "/calls/"+callGUID, nil, req, nil); err != nil {
 		return err
 	}
@@ -666,11 +1277,17 @@ func (c *Client) AddCallDetails(callGUID string, req *AddCallDetailsRequest) err
 }
 
 // RegisterCallEvent registers call event by call_guid.
+// Besides predefined CallEventType constants custom event types are also accepted.
 func (c *Client) RegisterCallEvent(callGUID string, eventType CallEventType, extension string) error {
-	if err := c.performRequest(http.MethodPost, "/calls/"+callGUID+"/event", nil, &registerCallEventRequest{
+	req := &registerCallEventRequest{
 		EventType: eventType,
 		Extension: extension,
-	}, nil); err != nil {
+	}
+	if err := c.validate(req); err != nil {
+		return err
+	}
+
+	if err := c.performRequest(http.MethodPost, "/calls/"+callGUID+"/event", nil, req, nil); err != nil {
 		return err
 	}
 
@@ -680,43 +1297,120 @@ func (c *Client) RegisterCallEvent(callGUID string, eventType CallEventType, ext
 // WebhookHandler returns HTTP handler and channel with Event's.
 // Handler automatically checks X-Pyrus-Sig, parses Event and sends it over channel..
 func (c *Client) WebhookHandler() (http.HandlerFunc, <-chan Event) {
-	eventChan := make(chan Event, c.eventBufferSize)
+	handler, events := c.webhookHandler()
+
+	return c.limitWebhook(handler), events
+}
+
+// webhookHandler returns WebhookHandler without the concurrency limit.
+func (c *Client) webhookHandler() (http.HandlerFunc, <-chan Event) {
+	eventChan := c.newWebhookChan()
 
 	writeError := func(w http.ResponseWriter, code int, err error) {
 		respBody, _ := json.Marshal(map[string]string{"error": err.Error()})
-		w.WriteHeader(http.StatusBadRequest)
 		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
 		if _, err := w.Write(respBody); err != nil {
 			c.logger.Error("Error while writing a response!", err)
 		}
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		b, err := io.ReadAll(r.Body)
-		if err != nil {
-			c.logger.Error("Error while reading a request body!", err)
-			writeError(w, http.StatusInternalServerError, err)
+		b, ok := c.readWebhookBody(w, r)
+		if !ok {
 			return
 		}
+		defer c.recoverWebhook(w, b)
 
-		hasher := hmac.New(sha1.New, []byte(c.securityKey))
-		hasher.Write(b)
-		hash := hex.EncodeToString(hasher.Sum(nil))
-		if subtle.ConstantTimeCompare([]byte(hash), []byte(strings.ToLower(r.Header.Get("X-Pyrus-Sig")))) != 1 {
-			err := errors.New("invalid signature")
+		event, err := c.ParseWebhook(b, r.Header.Get(WebhookSignatureHeader))
+		if errors.Is(err, ErrInvalidSignature) {
 			c.logger.Error("Invalid signature!", err)
 			writeError(w, http.StatusUnauthorized, err)
 			return
 		}
-
-		var event Event
-		if err := json.Unmarshal(b, &event); err != nil {
+		if err != nil {
 			c.logger.Error("Error while decoding a request body!", err)
 			writeError(w, http.StatusBadRequest, err)
 			return
 		}
 
-		eventChan <- event
+		if !c.acceptWebhook(*event) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		ctx, cancel := c.eventSendContext(r.Context())
+		defer cancel()
+		if err := c.sendWebhookEvent(ctx, eventChan, *event); err != nil {
+			reason := EventDropReasonBufferFull
+			if errors.Is(err, ErrClientClosed) {
+				reason = EventDropReasonClosed
+			}
+			c.dropEvent(*event, reason)
+
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 	}, eventChan
 }
+
+// ParseWebhook verifies the signature of the raw webhook body and decodes the event.
+// Use it to handle webhooks in frameworks not based on net/http or consuming the request body in middlewares.
+func (c *Client) ParseWebhook(body []byte, signature string) (*Event, error) {
+	if !c.validSignature(body, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var schemaErr error
+	if c.webhookSchemaMode == WebhookSchemaFlag || c.webhookSchemaMode == WebhookSchemaReject {
+		schemaErr = ValidateWebhookPayload(body)
+		if schemaErr != nil && c.webhookSchemaMode == WebhookSchemaReject {
+			return nil, schemaErr
+		}
+	}
+
+	var event Event
+	if err := c.unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	event.client = c
+	event.SchemaError = schemaErr
+	event.setLastComments()
+
+	return &event, nil
+}
+
+// readWebhookBody reads the webhook body up to WithWebhookMaxBodySize. Larger requests get 413
+// and failed reads 500, in both cases false is returned.
+func (c *Client) readWebhookBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if c.webhookMaxBodySize > 0 {
+		if r.ContentLength > c.webhookMaxBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, c.webhookMaxBodySize)
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil && c.webhookMaxBodySize > 0 && int64(len(b)) >= c.webhookMaxBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		c.logger.Error("Error while reading a request body!", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return b, true
+}
+
+// recoverWebhook must be deferred by webhook handlers. It recovers from panics, logs them with the request payload
+// and returns 500, so a single malformed event can't kill the server.
+func (c *Client) recoverWebhook(w http.ResponseWriter, payload []byte) {
+	if rec := recover(); rec != nil {
+		c.logger.Error("Panic while handling a webhook!", fmt.Errorf("%v, payload: %s", rec, payload))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -2,6 +2,8 @@ package pyrus
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/hex"
@@ -46,6 +48,8 @@ var (
 	fileID           int
 	callGUID         string
 
+	announcementID = 123456
+
 	logger, _ = zap.NewDevelopment()
 	cl        IClient
 	ts        *httptest.Server
@@ -98,65 +102,71 @@ func TestMain(m *testing.M) {
 	}
 
 	var (
-		requestAuth              = "POST:/auth"
-		requestForms             = "GET:/forms"
-		requestForm              = "GET:/forms/" + strconv.Itoa(formID)
-		requestRegistry          = "POST:/forms/" + strconv.Itoa(formID) + "/register"
-		requestTask              = "GET:/tasks/" + strconv.Itoa(taskID)
-		requestCreateTask        = "POST:/tasks"
-		requestCommentTask       = "POST:/tasks/" + strconv.Itoa(taskID) + "/comments"
-		requestUploadFile        = "POST:/files/upload"
-		requestDownloadFile      = "GET:/files/download/" + strconv.Itoa(fileID)
-		requestCatalogs          = "GET:/catalogs"
-		requestCatalog           = "GET:/catalogs/" + strconv.Itoa(catalogID)
-		requestCreateCatalog     = "PUT:/catalogs"
-		requestSyncCatalog       = "POST:/catalogs/" + strconv.Itoa(catalogID)
-		requestContacts          = "GET:/contacts"
-		requestMembers           = "GET:/members"
-		requestCreateMember      = "POST:/members"
-		requestUpdateMember      = "PUT:/members/" + strconv.Itoa(memberID)
-		requestDeleteMember      = "DELETE:/members/" + strconv.Itoa(memberID)
-		requestRoles             = "GET:/roles"
-		requestCreateRole        = "POST:/roles"
-		requestUpdateRole        = "PUT:/roles/" + strconv.Itoa(roleID)
-		requestProfile           = "GET:/profile"
-		requestLists             = "GET:/lists"
-		requestListsTasks        = "GET:/lists/" + strconv.Itoa(listID) + "/tasks"
-		requestInbox             = "GET:/inbox"
-		requestRegisterCall      = "POST:/calls"
-		requestAddCallDetails    = "PUT:/calls/" + callGUID
-		requestRegisterCallEvent = "POST:/calls/" + callGUID + "/event"
+		requestAuth                = "POST:/auth"
+		requestForms               = "GET:/forms"
+		requestForm                = "GET:/forms/" + strconv.Itoa(formID)
+		requestRegistry            = "POST:/forms/" + strconv.Itoa(formID) + "/register"
+		requestTask                = "GET:/tasks/" + strconv.Itoa(taskID)
+		requestCreateTask          = "POST:/tasks"
+		requestCommentTask         = "POST:/tasks/" + strconv.Itoa(taskID) + "/comments"
+		requestAnnouncement        = "GET:/announcements/" + strconv.Itoa(announcementID)
+		requestCommentAnnouncement = "POST:/announcements/" + strconv.Itoa(announcementID) + "/comments"
+		requestUploadFile          = "POST:/files/upload"
+		requestDownloadFile        = "GET:/files/download/" + strconv.Itoa(fileID)
+		requestDownloadPrintForm   = "GET:/tasks/" + strconv.Itoa(taskID) + "/print_forms"
+		requestCatalogs            = "GET:/catalogs"
+		requestCatalog             = "GET:/catalogs/" + strconv.Itoa(catalogID)
+		requestCreateCatalog       = "PUT:/catalogs"
+		requestSyncCatalog         = "POST:/catalogs/" + strconv.Itoa(catalogID)
+		requestContacts            = "GET:/contacts"
+		requestMembers             = "GET:/members"
+		requestCreateMember        = "POST:/members"
+		requestUpdateMember        = "PUT:/members/" + strconv.Itoa(memberID)
+		requestDeleteMember        = "DELETE:/members/" + strconv.Itoa(memberID)
+		requestRoles               = "GET:/roles"
+		requestCreateRole          = "POST:/roles"
+		requestUpdateRole          = "PUT:/roles/" + strconv.Itoa(roleID)
+		requestProfile             = "GET:/profile"
+		requestLists               = "GET:/lists"
+		requestListsTasks          = "GET:/lists/" + strconv.Itoa(listID) + "/tasks"
+		requestInbox               = "GET:/inbox"
+		requestRegisterCall        = "POST:/calls"
+		requestAddCallDetails      = "PUT:/calls/" + callGUID
+		requestRegisterCallEvent   = "POST:/calls/" + callGUID + "/event"
 	)
 
 	requests := map[string]string{
-		requestAuth:              "testdata/auth.json",
-		requestForms:             "testdata/forms.json",
-		requestForm:              "testdata/form.json",
-		requestRegistry:          "testdata/registry.json",
-		requestTask:              "testdata/task.json",
-		requestCreateTask:        "testdata/task.json",
-		requestCommentTask:       "testdata/task.json",
-		requestUploadFile:        "testdata/uploaded_file.json",
-		requestDownloadFile:      "testdata/downloaded_file.bin",
-		requestCatalogs:          "testdata/catalogs.json",
-		requestCatalog:           "testdata/catalog.json",
-		requestCreateCatalog:     "testdata/catalog.json",
-		requestSyncCatalog:       "testdata/sync_catalog.json",
-		requestContacts:          "testdata/contacts.json",
-		requestMembers:           "testdata/members.json",
-		requestCreateMember:      "testdata/member.json",
-		requestUpdateMember:      "testdata/member.json",
-		requestDeleteMember:      "testdata/member.json",
-		requestRoles:             "testdata/roles.json",
-		requestCreateRole:        "testdata/role.json",
-		requestUpdateRole:        "testdata/role.json",
-		requestProfile:           "testdata/profile.json",
-		requestLists:             "testdata/lists.json",
-		requestListsTasks:        "testdata/lists_tasks.json",
-		requestInbox:             "testdata/inbox.json",
-		requestRegisterCall:      "testdata/call.json",
-		requestAddCallDetails:    "",
-		requestRegisterCallEvent: "",
+		requestAuth:                "testdata/auth.json",
+		requestForms:               "testdata/forms.json",
+		requestForm:                "testdata/form.json",
+		requestRegistry:            "testdata/registry.json",
+		requestTask:                "testdata/task.json",
+		requestCreateTask:          "testdata/task.json",
+		requestCommentTask:         "testdata/task.json",
+		requestAnnouncement:        "testdata/announcement.json",
+		requestCommentAnnouncement: "testdata/announcement.json",
+		requestUploadFile:          "testdata/uploaded_file.json",
+		requestDownloadFile:        "testdata/downloaded_file.bin",
+		requestDownloadPrintForm:   "testdata/downloaded_file.bin",
+		requestCatalogs:            "testdata/catalogs.json",
+		requestCatalog:             "testdata/catalog.json",
+		requestCreateCatalog:       "testdata/catalog.json",
+		requestSyncCatalog:         "testdata/sync_catalog.json",
+		requestContacts:            "testdata/contacts.json",
+		requestMembers:             "testdata/members.json",
+		requestCreateMember:        "testdata/member.json",
+		requestUpdateMember:        "testdata/member.json",
+		requestDeleteMember:        "testdata/member.json",
+		requestRoles:               "testdata/roles.json",
+		requestCreateRole:          "testdata/role.json",
+		requestUpdateRole:          "testdata/role.json",
+		requestProfile:             "testdata/profile.json",
+		requestLists:               "testdata/lists.json",
+		requestListsTasks:          "testdata/lists_tasks.json",
+		requestInbox:               "testdata/inbox.json",
+		requestRegisterCall:        "testdata/call.json",
+		requestAddCallDetails:      "",
+		requestRegisterCallEvent:   "",
 	}
 
 	// seed responses
@@ -210,6 +220,7 @@ func TestMain(m *testing.M) {
 				case requestForms,
 					requestForm,
 					requestTask,
+					requestAnnouncement,
 					requestCatalogs,
 					requestCatalog,
 					requestContacts,
@@ -245,7 +256,7 @@ func TestMain(m *testing.M) {
 			v := v
 
 			switch k {
-			case requestDownloadFile:
+			case requestDownloadFile, requestDownloadPrintForm:
 				w.Header().Set("Content-Type", "text/plain")
 				w.Header().Set("Content-Disposition", "attachment; filename=\"index.html\"; filename*=UTF-8''index.html")
 			case requestAddCallDetails, requestRegisterCallEvent:
@@ -408,18 +419,35 @@ func TestNewClient(t *testing.T) {
 			fakePyrusSecurityKey,
 			WithBaseURL("%zzzzz"),
 		)
-		require.NoError(t, err)
-		assert.NotNil(t, c)
+		assert.EqualError(t, err, `base URL must be http or https URL, got "%zzzzz"`)
+		assert.Nil(t, c)
 
-		_, err = c.Profile()
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithBaseURL("nonexistent"))
 		assert.Error(t, err)
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithFileBaseURL("ftp://files.example.org"))
+		assert.EqualError(t, err, `file base URL must be http or https URL, got "ftp://files.example.org"`)
+	})
+
+	t.Run("client with invalid options", func(t *testing.T) {
+		_, err := NewClient("", fakePyrusSecurityKey)
+		assert.EqualError(t, err, "login is required")
+		_, err = NewClient(fakePyrusLogin, "")
+		assert.EqualError(t, err, "security key is required")
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithHTTPClient(nil))
+		assert.EqualError(t, err, "http client must not be nil")
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithClock(nil))
+		assert.EqualError(t, err, "clock must not be nil")
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithEventBufferSize(-1))
+		assert.EqualError(t, err, "event buffer size must not be negative, got -1")
+		_, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookConcurrencyLimit(-1, time.Second))
+		assert.EqualError(t, err, "webhook concurrency limit must not be negative, got -1")
 	})
 
 	t.Run("client with nonexistent host", func(t *testing.T) {
 		c, err := NewClient(
 			fakePyrusLogin,
 			fakePyrusSecurityKey,
-			WithBaseURL("nonexistent"),
+			WithBaseURL("http://nonexistent.invalid"),
 		)
 		require.NoError(t, err)
 		assert.NotNil(t, c)
@@ -505,7 +533,25 @@ func TestClient_WebhookHandler(t *testing.T) {
 		assert.NoError(t, resp.Body.Close())
 
 		require.Equal(t, http.StatusOK, resp.StatusCode)
-		assert.NotNil(t, <-events)
+		event := <-events
+		assert.NotNil(t, event)
+		assert.Equal(t, EventSubjectTask, event.Subject())
+	})
+
+	t.Run("announcement event", func(t *testing.T) {
+		b, err := os.ReadFile("testdata/event_announcement.json")
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, b))
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		event := <-events
+		assert.Equal(t, EventSubjectAnnouncement, event.Subject())
+		require.NotNil(t, event.Announcement)
+		assert.Equal(t, "Пример объявления", event.Announcement.Text)
+		assert.Len(t, event.Announcement.Comments, 1)
 	})
 
 	t.Run("invalid hash", func(t *testing.T) {
@@ -513,9 +559,13 @@ func TestClient_WebhookHandler(t *testing.T) {
 
 		resp, err := http.DefaultClient.Do(req)
 		require.NoError(t, err)
-		assert.NoError(t, resp.Body.Close())
+		defer resp.Body.Close()
 
-		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"error":"invalid signature"}`, string(b))
 	})
 
 	t.Run("invalid body", func(t *testing.T) {
@@ -540,6 +590,18 @@ func TestClient_WebhookHandler(t *testing.T) {
 	})
 }
 
+func signedWebhookRequest(t *testing.T, url string, body []byte) *http.Request {
+	hasher := hmac.New(sha1.New, []byte(fakePyrusSecurityKey))
+	_, err := hasher.Write(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Pyrus-Sig", hex.EncodeToString(hasher.Sum(nil)))
+
+	return req
+}
+
 func TestClient_Auth(t *testing.T) {
 	token, err := cl.Auth(fakePyrusLogin, fakePyrusSecurityKey)
 	require.NoError(t, err)
@@ -635,13 +697,70 @@ func TestClient_Lists(t *testing.T) {
 func TestClient_TaskList(t *testing.T) {
 	taskList, err := cl.TaskList(listID, 200, true)
 	require.NoError(t, err)
-	assert.NotNil(t, taskList)
+	require.NotNil(t, taskList)
+	assert.Len(t, taskList.Tasks, 2)
+	assert.True(t, taskList.HasMore)
+	assert.True(t, taskList.HasMode)
+
+	// fake server always returns the same tasks, so the next page is empty and the last one
+	next, err := taskList.NextPage()
+	require.NoError(t, err)
+	assert.Empty(t, next.Tasks)
+	assert.False(t, next.HasMore)
+
+	_, err = next.NextPage()
+	assert.ErrorIs(t, err, ErrNoMorePages)
+}
+
+func TestClient_TaskList_repeatedNextPage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		// List of 5 tasks truncated by item_count
+		n, err := strconv.Atoi(r.URL.Query().Get("item_count"))
+		require.NoError(t, err)
+		resp := TaskListResponse{HasMore: n < 5}
+		for id := 1; id <= n && id <= 5; id++ {
+			resp.Tasks = append(resp.Tasks, &TaskHeader{ID: id})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	ids := func(r *TaskListResponse) []int {
+		var ids []int
+		for _, task := range r.Tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	first, err := c.TaskList(listID, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids(first))
+
+	for i := 0; i < 2; i++ {
+		next, err := first.NextPage()
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 4}, ids(next))
+	}
 }
 
 func TestClient_Inbox(t *testing.T) {
 	inbox, err := cl.Inbox(200)
 	require.NoError(t, err)
-	assert.NotNil(t, inbox)
+	require.NotNil(t, inbox)
+	assert.False(t, inbox.HasMore)
+
+	_, err = inbox.NextPage()
+	assert.ErrorIs(t, err, ErrNoMorePages)
 }
 
 func TestClient_CreateTask(t *testing.T) {
@@ -676,6 +795,92 @@ func TestClient_CommentTask(t *testing.T) {
 	assert.NotNil(t, task)
 }
 
+func TestClient_CommentTask_ExpectedLastNoteID(t *testing.T) {
+	task, err := cl.CommentTask(taskID, &TaskCommentRequest{
+		Text:               "Пример текста задачи",
+		ExpectedLastNoteID: 123456,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, task)
+
+	_, err = cl.CommentTask(taskID, &TaskCommentRequest{
+		Text:               "Пример текста задачи",
+		ExpectedLastNoteID: 123455,
+	})
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 123456, conflict.LastNoteID)
+	assert.Equal(t, 123455, conflict.ExpectedLastNoteID)
+}
+
+func TestClient_Announcement(t *testing.T) {
+	announcement, err := cl.Announcement(announcementID)
+	require.NoError(t, err)
+	require.NotNil(t, announcement.Announcement)
+	assert.Equal(t, announcementID, announcement.Announcement.ID)
+	assert.Len(t, announcement.Announcement.Comments, 1)
+}
+
+func TestClient_CommentAnnouncement(t *testing.T) {
+	announcement, err := cl.CommentAnnouncement(announcementID, &AnnouncementCommentRequest{
+		Text: "Пример комментария",
+		Attachments: []*NewFile{
+			{GUID: "8f803ee4-274b-4373-a2cd-d77aed9250cc"},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, announcement)
+
+	_, err = cl.CommentAnnouncement(announcementID, &AnnouncementCommentRequest{
+		Text: "Пример комментария",
+		Attachments: []*NewFile{
+			{GUID: "8f803ee4-274b-4373-a2cd-d77aed9250cc", AttachmentID: 123456},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestClient_AddParticipants(t *testing.T) {
+	c := cl.(*Client)
+
+	t.Run("internal persons", func(t *testing.T) {
+		task, err := c.AddParticipants(taskID, &Person{ID: 123456}, &Person{ID: 654321}, &Person{ID: 216831})
+		require.NoError(t, err)
+		assert.NotNil(t, task)
+	})
+
+	t.Run("external person", func(t *testing.T) {
+		_, err := c.AddParticipants(taskID, &Person{ID: 123456}, &Person{ID: 777777})
+		require.Error(t, err)
+
+		var pe Error
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, ErrCannotAddExternalUser, pe.Code)
+	})
+
+	t.Run("no persons", func(t *testing.T) {
+		_, err := c.AddParticipants(taskID)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_RemoveParticipants(t *testing.T) {
+	task, err := cl.(*Client).RemoveParticipants(taskID, &Person{ID: 123456})
+	require.NoError(t, err)
+	assert.NotNil(t, task)
+}
+
+func TestClient_CommentViaEmail(t *testing.T) {
+	c := cl.(*Client)
+
+	task, err := c.CommentViaEmail(taskID, "client@example.org", "Пример ответа", nil)
+	require.NoError(t, err)
+	assert.NotNil(t, task)
+
+	_, err = c.CommentViaEmail(taskID, "invalid email", "Пример ответа", nil)
+	assert.Error(t, err)
+}
+
 func TestClient_UploadFile(t *testing.T) {
 	f, err := os.Open("testdata/uploaded_file.json")
 	require.NoError(t, err)
@@ -692,6 +897,13 @@ func TestClient_DownloadFile(t *testing.T) {
 	assert.NotNil(t, file)
 }
 
+func TestClient_DownloadPrintForm(t *testing.T) {
+	file, err := cl.DownloadPrintForm(taskID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "index.html", file.Filename)
+	assert.NotEmpty(t, file.RawFile)
+}
+
 func TestClient_CreateCatalog(t *testing.T) {
 	catalog, err := cl.CreateCatalog("BotTest", []string{"Имя", "Адрес"}, []*CatalogItem{
 		{
@@ -779,7 +991,27 @@ func TestClient_RegisterCall(t *testing.T) {
 		IntegrationGUID: "5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5",
 	})
 	require.NoError(t, err)
-	assert.NotNil(t, call)
+	require.NotNil(t, call)
+	assert.Equal(t, 43534533145, call.TaskID.Int())
+}
+
+func TestNumericID_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		A NumericID `json:"a"`
+		B NumericID `json:"b"`
+		C NumericID `json:"c"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"a": 123, "b": "456", "c": null}`), &v))
+	assert.Equal(t, 123, v.A.Int())
+	assert.Equal(t, 456, v.B.Int())
+	assert.Equal(t, 0, v.C.Int())
+	assert.Equal(t, "456", v.B.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"a": "abc"}`), &v))
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": 123, "b": 456, "c": 0}`, string(b))
 }
 
 func TestClient_AddCallDetails(t *testing.T) {
@@ -787,9 +1019,447 @@ func TestClient_AddCallDetails(t *testing.T) {
 		Rating: 5,
 	})
 	require.NoError(t, err)
+
+	start := time.Now()
+	end := start.Add(-time.Minute)
+	tests := []*AddCallDetailsRequest{
+		{Rating: 6},
+		{StartTime: &start, EndTime: &end},
+		{DisconnectParty: "nobody"},
+		{CallStatus: "lost"},
+		{FileGUID: "not-a-guid"},
+	}
+	for _, req := range tests {
+		assert.Error(t, cl.AddCallDetails("5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5", req))
+	}
 }
 
 func TestClient_RegisterCallEvent(t *testing.T) {
 	err := cl.RegisterCallEvent("5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5", CallEventTypeShow, "")
 	require.NoError(t, err)
+
+	err = cl.RegisterCallEvent("5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5", CallEventType("custom"), "101")
+	require.NoError(t, err)
+
+	err = cl.RegisterCallEvent("5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5", "", "")
+	assert.Error(t, err)
+}
+
+func TestTaskWithComments_LastClientChannel(t *testing.T) {
+	task := &TaskWithComments{
+		Comments: []*TaskComment{
+			{Channel: &Channel{Type: ChannelTypeTelegram, From: &ChannelUser{Name: "telegram_user"}}},
+			{Channel: &Channel{Type: ChannelTypeVK, From: &ChannelUser{Name: "vk_user"}}},
+			{Channel: &Channel{Type: ChannelTypeVK, To: &ChannelUser{Name: "vk_user"}}},
+			{Text: "Внутренний комментарий"},
+		},
+	}
+
+	ch := task.LastClientChannel()
+	require.NotNil(t, ch)
+	assert.Equal(t, ChannelTypeVK, ch.Type)
+
+	ch = task.LastClientChannel(ChannelTypeTelegram)
+	require.NotNil(t, ch)
+	assert.Equal(t, "telegram_user", ch.From.Name)
+
+	assert.Nil(t, task.LastClientChannel(ChannelTypeViber))
+}
+
+func TestClient_ReplyToClient(t *testing.T) {
+	_, err := cl.(*Client).ReplyToClient(taskID, "Пример ответа", nil)
+	assert.Error(t, err)
+
+	_, err = cl.(*Client).CommentViaTelegram(taskID, "Пример ответа", nil)
+	assert.Error(t, err)
+}
+
+func TestMentionBuilder(t *testing.T) {
+	ivan := &Person{ID: 1, FirstName: "Иван", LastName: "Иванов"}
+	bot := &Person{ID: 2, Email: "bot@example.org"}
+
+	req := NewMentionBuilder().
+		Text("Коллеги ").
+		Mentions(ivan, bot).
+		Text(", посмотрите, пожалуйста. ").
+		Mention(ivan).
+		Apply(&TaskCommentRequest{})
+
+	assert.Equal(t, "Коллеги @Иван Иванов, @bot@example.org, посмотрите, пожалуйста. @Иван Иванов", req.Text)
+	assert.Equal(t, []int{1, 2}, req.Mentions)
+}
+
+func TestClient_AttachCallRecording(t *testing.T) {
+	c := cl.(*Client)
+
+	err := c.AttachCallRecording(callGUID, "record.MP3", strings.NewReader("ID3"))
+	require.NoError(t, err)
+
+	err = c.AttachCallRecording(callGUID, "record.flac", strings.NewReader("fLaC"))
+	var pe Error
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, ErrUnsupportedAttachmentFormat, pe.Code)
+}
+
+func TestWithRequestCompression(t *testing.T) {
+	var compressedRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gr
+			compressedRequests++
+		}
+		b, err := io.ReadAll(body)
+		require.NoError(t, err)
+
+		var resp []byte
+		switch r.URL.Path {
+		case "/auth":
+			resp = []byte(`{"access_token":"token"}`)
+		case "/tasks/1/comments":
+			var req TaskCommentRequest
+			require.NoError(t, json.Unmarshal(b, &req))
+			resp = []byte(`{"task":{"id":1,"text":"` + req.Text + `"}}`)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			gw.Write(resp) //nolint:errcheck
+			gw.Close()     //nolint:errcheck
+			return
+		}
+		w.Write(resp) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithRequestCompression(100))
+	require.NoError(t, err)
+
+	text := strings.Repeat("Пример ", 50)
+	task, err := c.CommentTask(1, &TaskCommentRequest{Text: text})
+	require.NoError(t, err)
+	assert.Equal(t, text, task.Task.Text)
+	// auth request is smaller than threshold
+	assert.Equal(t, 1, compressedRequests)
+}
+
+func TestWithTransportProfile(t *testing.T) {
+	c, err := NewClient("login", "key", WithTransportProfile(TransportProfileHighThroughput))
+	require.NoError(t, err)
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 100, tr.MaxIdleConnsPerHost)
+	assert.Zero(t, c.httpClient.Timeout)
+
+	c, err = NewClient("login", "key", WithTransportProfile(TransportProfileLowLatency))
+	require.NoError(t, err)
+	tr, ok = c.httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 15*time.Second, tr.ResponseHeaderTimeout)
+}
+
+func TestWithTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte(`{"forms":[]}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithTimeout(20*time.Millisecond))
+	require.NoError(t, err)
+	_, err = c.Forms()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	c, err = NewClient("login", "key",
+		WithBaseURL(ts.URL),
+		WithTimeout(20*time.Millisecond),
+		WithEndpointTimeout("/forms", time.Second),
+	)
+	require.NoError(t, err)
+	_, err = c.Forms()
+	assert.NoError(t, err)
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.Equal(t, ErrorCategoryTransient, ErrServerError.Category())
+	assert.Equal(t, ErrorCategoryQuota, ErrTooManyRequests.Category())
+	assert.Equal(t, ErrorCategoryAuth, ErrAccessDeniedTask.Category())
+	assert.Equal(t, ErrorCategoryValidation, ErrInvalidJSON.Category())
+	assert.Equal(t, ErrorCategoryUnknown, ErrorCode("something_new").Category())
+
+	assert.True(t, IsRetryable(Error{Code: ErrTooManyRequests}))
+	assert.True(t, IsRetryable(Error{Code: ErrServerError}))
+	assert.True(t, IsRetryable(io.ErrUnexpectedEOF))
+	assert.False(t, IsRetryable(Error{Code: ErrInvalidJSON}))
+	assert.False(t, IsRetryable(errors.New("some error")))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestRequestError(t *testing.T) {
+	c, err := NewClient("wrong", "wrong", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	require.NoError(t, err)
+
+	_, err = c.Forms()
+	var re *RequestError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, http.MethodPost, re.Method)
+	assert.Equal(t, "/auth", re.Path)
+	assert.Equal(t, 1, re.Attempt)
+
+	var pe Error
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, ErrInvalidCredentials, pe.Code)
+	assert.Contains(t, err.Error(), "POST /auth (attempt 1)")
+}
+
+func TestError_Is(t *testing.T) {
+	c, err := NewClient("wrong", "wrong", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	require.NoError(t, err)
+
+	_, err = c.Forms()
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.NotErrorIs(t, err, ErrValidation)
+
+	assert.ErrorIs(t, Error{StatusCode: http.StatusNotFound, Message: "No HTTP resource was found"}, ErrNotFound)
+	assert.ErrorIs(t, Error{Code: ErrTooManyRequests}, ErrRateLimited)
+	assert.ErrorIs(t, Error{Code: ErrInvalidJSON}, ErrValidation)
+	assert.NotErrorIs(t, Error{Code: ErrServerError}, ErrValidation)
+}
+
+func TestWithErrorLanguage(t *testing.T) {
+	c, err := NewClient("wrong", "wrong", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()), WithErrorLanguage(LanguageRussian))
+	require.NoError(t, err)
+
+	_, err = c.Forms()
+	var pe Error
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "Неверный логин или секретный ключ", pe.Description)
+	assert.NotEmpty(t, pe.OriginalDescription)
+	assert.Equal(t, "Invalid login or security key", pe.LocalizedDescription(LanguageEnglish))
+
+	assert.Equal(t, "", ErrorCode("something_new").Description(LanguageEnglish))
+	assert.Equal(t, "Ошибка", Error{Code: "something_new", Description: "Ошибка"}.LocalizedDescription(LanguageEnglish))
+	for code := range errorCodes {
+		assert.NotEmpty(t, code.Description(LanguageEnglish), code)
+		assert.NotEmpty(t, code.Description(LanguageRussian), code)
+	}
+}
+
+type recordingLogger struct {
+	errs []error
+}
+
+func (l *recordingLogger) Error(_ string, err error) {
+	l.errs = append(l.errs, err)
+}
+
+func TestClient_recoverWebhook(t *testing.T) {
+	logger := &recordingLogger{}
+	c, err := NewClient("login", fakePyrusSecurityKey, WithLogger(logger), WithWebhookFilter(func(Event) bool {
+		panic("malformed event")
+	}))
+	require.NoError(t, err)
+
+	handler, _ := c.WebhookHandler()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, []byte(`{"task_id":1}`)))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	require.Len(t, logger.errs, 1)
+	assert.Contains(t, logger.errs[0].Error(), "malformed event")
+	assert.Contains(t, logger.errs[0].Error(), `{"task_id":1}`)
+}
+
+func TestClient_ParseWebhook(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	req := signedWebhookRequest(t, "http://localhost", b)
+	event, err := cl.(*Client).ParseWebhook(b, req.Header.Get(WebhookSignatureHeader))
+	require.NoError(t, err)
+	assert.NotZero(t, event.TaskID)
+	require.NotNil(t, event.LastComment)
+	assert.Equal(t, "Проверка", event.LastComment.Text)
+	require.Len(t, event.ChangedFields(), 1)
+	assert.Equal(t, 20, event.ChangedField("Ресурс").ID)
+	assert.Nil(t, event.ChangedField(21))
+
+	_, err = cl.(*Client).ParseWebhook(b, "invalid")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestEvent_setLastComments(t *testing.T) {
+	created := time.Date(2021, 7, 21, 13, 0, 0, 0, time.UTC)
+	event := Event{
+		Task: &TaskWithComments{Comments: []*TaskComment{
+			{ID: 3, CreateDate: created.Add(time.Minute)},
+			{ID: 1, CreateDate: created},
+			{ID: 4, CreateDate: created.Add(time.Minute)},
+		}},
+		Announcement: &AnnouncementWithComments{Comments: []*AnnouncementComment{
+			{ID: 2, CreateDate: created.Add(time.Hour)},
+			{ID: 5, CreateDate: created},
+		}},
+	}
+	event.setLastComments()
+	assert.Equal(t, 4, event.LastComment.ID)
+	assert.Equal(t, 2, event.LastAnnouncementComment.ID)
+
+	assert.Nil(t, Event{}.ChangedFields())
+}
+
+func TestClient_MountWebhook(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	events := cl.(*Client).MountWebhook("/pyrus", mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	req := signedWebhookRequest(t, ts.URL+"/pyrus", b)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	<-events
+
+	resp, err = http.Get(ts.URL + "/pyrus")
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	req = signedWebhookRequest(t, ts.URL+"/pyrus", b)
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookMaxBodySize(10))
+	require.NoError(t, err)
+	handler, _ := c.WebhookHTTPHandler()
+	limited := httptest.NewServer(handler)
+	defer limited.Close()
+
+	resp, err = http.DefaultClient.Do(signedWebhookRequest(t, limited.URL, b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// Without Content-Length the limit is hit while reading
+	req = signedWebhookRequest(t, limited.URL, b)
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	req.ContentLength = -1
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestClient_limitWebhook(t *testing.T) {
+	c, err := NewClient("login", "key", WithWebhookConcurrencyLimit(1, 50*time.Millisecond))
+	require.NoError(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(c.limitWebhook(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})))
+	defer ts.Close()
+
+	first := make(chan int, 1)
+	go func() {
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			first <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		first <- resp.StatusCode
+	}()
+	<-started
+
+	resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+
+	go func() { <-started }()
+	resp, err = http.Post(ts.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Zero disables the limit
+	c, err = NewClient("login", "key", WithWebhookConcurrencyLimit(0, time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, c.webhookSem)
+}
+
+func TestWithWebhookSecrets(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	sign := func(secret string) string {
+		hasher := hmac.New(sha1.New, []byte(secret))
+		_, err := hasher.Write(b)
+		require.NoError(t, err)
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	c, err := NewClient("login", "new", WithWebhookSecrets("old"))
+	require.NoError(t, err)
+
+	_, err = c.ParseWebhook(b, sign("new"))
+	assert.NoError(t, err)
+	_, err = c.ParseWebhook(b, strings.ToUpper(sign("old")))
+	assert.NoError(t, err)
+	_, err = c.ParseWebhook(b, sign("other"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestWithWebhookMaxBodySize(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookMaxBodySize(10))
+	require.NoError(t, err)
+
+	handler, _ := c.WebhookHandler()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// Chunked body without Content-Length
+	req := signedWebhookRequest(t, ts.URL, b)
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b)))
+	req.ContentLength = -1
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
 }
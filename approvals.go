@@ -0,0 +1,173 @@
+package pyrus
+
+import "strings"
+
+// ApprovalMatrix is a list of approval steps with their approvers, the first element is the first step.
+// Use it to describe the target state of task approvals and TaskCommentRequest.ChangeApprovals
+// to generate the comment changing current approvals into it.
+type ApprovalMatrix [][]*Person
+
+// NewApprovalMatrix returns the matrix of approvers from Task.Approvals.
+func NewApprovalMatrix(approvals [][]*Approval) ApprovalMatrix {
+	m := make(ApprovalMatrix, len(approvals))
+	for i, step := range approvals {
+		m[i] = make([]*Person, 0, len(step))
+		for _, a := range step {
+			if a != nil && a.Person != nil {
+				m[i] = append(m[i], a.Person)
+			}
+		}
+	}
+
+	return m
+}
+
+// Step returns approvers of the step starting from 1.
+func (m ApprovalMatrix) Step(step int) []*Person {
+	if step < 1 || step > len(m) {
+		return nil
+	}
+
+	return m[step-1]
+}
+
+// InsertStep returns a copy of the matrix with a new step inserted before the step, the following steps are shifted.
+// Step greater than the number of steps appends the step to the end.
+func (m ApprovalMatrix) InsertStep(step int, persons ...*Person) ApprovalMatrix {
+	if step < 1 {
+		step = 1
+	}
+	if step > len(m)+1 {
+		step = len(m) + 1
+	}
+
+	c := m.clone()
+	c = append(c, nil)
+	copy(c[step:], c[step-1:])
+	c[step-1] = append([]*Person(nil), persons...)
+
+	return c
+}
+
+// AddApprover returns a copy of the matrix with the person added to the step, missing steps are created empty.
+func (m ApprovalMatrix) AddApprover(step int, p *Person) ApprovalMatrix {
+	if step < 1 {
+		return m.clone()
+	}
+
+	c := m.clone()
+	for len(c) < step {
+		c = append(c, []*Person{})
+	}
+	if indexOfPerson(c[step-1], p) < 0 {
+		c[step-1] = append(c[step-1], p)
+	}
+
+	return c
+}
+
+// RemoveApprover returns a copy of the matrix without the person at the step.
+func (m ApprovalMatrix) RemoveApprover(step int, p *Person) ApprovalMatrix {
+	c := m.clone()
+	if step < 1 || step > len(c) {
+		return c
+	}
+
+	if i := indexOfPerson(c[step-1], p); i >= 0 {
+		c[step-1] = append(c[step-1][:i], c[step-1][i+1:]...)
+	}
+
+	return c
+}
+
+func (m ApprovalMatrix) clone() ApprovalMatrix {
+	c := make(ApprovalMatrix, len(m))
+	for i, step := range m {
+		c[i] = append([]*Person{}, step...)
+	}
+
+	return c
+}
+
+// ChangeApprovals fills ApprovalsAdded and ApprovalsRemoved with the difference between current and target approvers.
+// Current approvers are usually taken from NewApprovalMatrix(task.Approvals).
+func (r *TaskCommentRequest) ChangeApprovals(current, target ApprovalMatrix) {
+	steps := len(current)
+	if len(target) > steps {
+		steps = len(target)
+	}
+
+	var added, removed [][]*Person
+	for i := 0; i < steps; i++ {
+		cur, tgt := current.Step(i+1), target.Step(i+1)
+
+		for _, p := range tgt {
+			if p != nil && indexOfPerson(cur, p) < 0 {
+				added = addToStep(added, i, approverRef(p))
+			}
+		}
+		for _, p := range cur {
+			if p != nil && indexOfPerson(tgt, p) < 0 {
+				removed = addToStep(removed, i, approverRef(p))
+			}
+		}
+	}
+
+	r.ApprovalsAdded = added
+	r.ApprovalsRemoved = removed
+}
+
+// RerequestApprovals asks persons to approve the step starting from 1 again, e.g. after the task has been changed.
+func (r *TaskCommentRequest) RerequestApprovals(step int, persons ...*Person) {
+	if step < 1 {
+		return
+	}
+
+	for _, p := range persons {
+		if p == nil {
+			continue
+		}
+		r.ApprovalsRerequested = addToStep(r.ApprovalsRerequested, step-1, approverRef(p))
+	}
+}
+
+// addToStep appends the person to the step with the index, missing steps are created empty.
+func addToStep(m [][]*Person, i int, p *Person) [][]*Person {
+	for len(m) <= i {
+		m = append(m, []*Person{})
+	}
+	m[i] = append(m[i], p)
+
+	return m
+}
+
+// approverRef returns a person reference accepted by Pyrus: only id or only email.
+func approverRef(p *Person) *Person {
+	if p.ID != 0 {
+		return &Person{ID: p.ID}
+	}
+
+	return &Person{Email: p.Email}
+}
+
+// indexOfPerson searches the person by id or by email if id is unknown.
+func indexOfPerson(persons []*Person, p *Person) int {
+	for i, candidate := range persons {
+		if samePerson(candidate, p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func samePerson(a, b *Person) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if a.ID != 0 && b.ID != 0 {
+		return a.ID == b.ID
+	}
+
+	return a.Email != "" && strings.EqualFold(a.Email, b.Email)
+}
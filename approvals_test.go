@@ -0,0 +1,40 @@
+package pyrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalMatrix(t *testing.T) {
+	current := NewApprovalMatrix([][]*Approval{
+		{{Person: &Person{ID: 1, Email: "one@example.org"}, Step: 1}, {Person: &Person{ID: 2}, Step: 1}},
+		{{Person: &Person{ID: 3}, Step: 2}},
+	})
+	require.Len(t, current, 2)
+
+	inserted := current.InsertStep(2, &Person{ID: 4})
+	require.Len(t, inserted, 3)
+	assert.Equal(t, 4, inserted.Step(2)[0].ID)
+	assert.Equal(t, 3, inserted.Step(3)[0].ID)
+	assert.Len(t, current, 2, "original matrix must not change")
+
+	target := current.RemoveApprover(1, &Person{ID: 2}).AddApprover(1, &Person{ID: 5}).AddApprover(3, &Person{ID: 6})
+	assert.Len(t, current.Step(1), 2)
+
+	var req TaskCommentRequest
+	req.ChangeApprovals(current, target)
+	assert.Equal(t, [][]*Person{{{ID: 5}}, {}, {{ID: 6}}}, req.ApprovalsAdded)
+	assert.Equal(t, [][]*Person{{{ID: 2}}}, req.ApprovalsRemoved)
+	assert.NoError(t, req.Validate())
+
+	req = TaskCommentRequest{}
+	req.ChangeApprovals(current, current.InsertStep(1, &Person{ID: 4}))
+	assert.Equal(t, [][]*Person{{{ID: 4}}, {{ID: 1}, {ID: 2}}, {{ID: 3}}}, req.ApprovalsAdded)
+	assert.Equal(t, [][]*Person{{{ID: 1}, {ID: 2}}, {{ID: 3}}}, req.ApprovalsRemoved)
+
+	req = TaskCommentRequest{}
+	req.RerequestApprovals(2, current.Step(2)...)
+	assert.Equal(t, [][]*Person{{}, {{ID: 3}}}, req.ApprovalsRerequested)
+}
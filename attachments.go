@@ -0,0 +1,198 @@
+package pyrus
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// attachmentsConcurrency is a number of files downloaded simultaneously by DownloadTaskAttachments.
+const attachmentsConcurrency = 4
+
+// TaskAttachments returns unique files of the task: its attachments, attachments of comments
+// and files of form fields including nested ones.
+func TaskAttachments(task *TaskWithComments) []*File {
+	if task == nil {
+		return nil
+	}
+
+	var files []*File
+	seen := make(map[int]struct{})
+	add := func(list []*File) {
+		for _, f := range list {
+			if f == nil {
+				continue
+			}
+			if _, ok := seen[f.ID]; ok {
+				continue
+			}
+			seen[f.ID] = struct{}{}
+			files = append(files, f)
+		}
+	}
+
+	if task.Task != nil {
+		add(task.Attachments)
+		Walk(task.Fields, func(_ FieldPath, f *FormField) bool {
+			if f.Type == FieldTypeFile {
+				if v, err := f.DecodedValue(); err == nil {
+					list, _ := v.([]*File)
+					add(list)
+				}
+			}
+			return true
+		})
+	}
+	for _, c := range task.Comments {
+		if c != nil {
+			add(c.Attachments)
+		}
+	}
+
+	return files
+}
+
+// DownloadTaskAttachments downloads all files of the task concurrently and writes them into a zip archive.
+// Files with the same name are renamed like "scan (2).pdf".
+func (c *Client) DownloadTaskAttachments(taskID int, w io.Writer) error {
+	task, err := c.Task(taskID)
+	if err != nil {
+		return err
+	}
+
+	files := TaskAttachments(task.Task)
+	names := archiveNames(files)
+
+	zw := zip.NewWriter(w)
+	err = c.DownloadFilesFunc(files, attachmentsConcurrency, func(d *FileDownload) error {
+		if d.Err != nil {
+			return d.Err
+		}
+
+		fw, err := zw.Create(names[d.Index])
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(d.Response.RawFile)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// FileDownload is a result of the file download made by DownloadFiles.
+type FileDownload struct {
+	// Index is the index of the file in the slice passed to DownloadFiles.
+	Index    int
+	File     *File
+	Response *DownloadResponse
+	Err      error
+}
+
+// DownloadFiles downloads the files in parallel with at most limit simultaneous requests
+// and returns results in the order of files. Failed downloads don't stop others, check Err of every result.
+func (c *Client) DownloadFiles(files []*File, limit int) []*FileDownload {
+	results := make([]*FileDownload, len(files))
+	c.DownloadFilesFunc(files, limit, func(d *FileDownload) error { //nolint:errcheck
+		results[d.Index] = d
+		return nil
+	})
+
+	return results
+}
+
+// DownloadFilesFunc downloads the files in parallel with at most limit simultaneous requests
+// and passes every result to fn as soon as it's ready, so files don't have to be kept in memory.
+// Calls of fn are not concurrent. If fn returns an error, remaining and running downloads are canceled
+// and the error is returned.
+func (c *Client) DownloadFilesFunc(files []*File, limit int, fn func(d *FileDownload) error) error {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	// Canceling aborts downloads in flight as well, not only the queued ones
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan int)
+		results = make(chan *FileDownload)
+	)
+	for n := 0; n < limit; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				d := &FileDownload{Index: i, File: files[i]}
+				if files[i] == nil {
+					d.Err = errors.New("file cannot be nil")
+				} else {
+					d.Response, d.Err = c.DownloadFileContext(ctx, files[i].ID)
+				}
+
+				select {
+				case results <- d:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for d := range results {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveNames returns safe unique names of the files for the archive.
+func archiveNames(files []*File) []string {
+	names := make([]string, len(files))
+	used := make(map[string]struct{})
+	for i, f := range files {
+		name := strings.NewReplacer("/", "_", "\\", "_").Replace(strings.TrimSpace(f.Name))
+		if name == "" || name == "." || name == ".." {
+			name = strconv.Itoa(f.ID)
+		}
+
+		ext := path.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 2; ; n++ {
+			if _, ok := used[strings.ToLower(name)]; !ok {
+				break
+			}
+			name = base + " (" + strconv.Itoa(n) + ")" + ext
+		}
+
+		used[strings.ToLower(name)] = struct{}{}
+		names[i] = name
+	}
+
+	return names
+}
@@ -0,0 +1,171 @@
+package pyrus
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DownloadTaskAttachments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case r.URL.Path == "/tasks/1":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"task":{"id":1,` + //nolint:errcheck
+				`"attachments":[{"id":10,"name":"scan.pdf"}],` +
+				`"fields":[{"id":1,"type":"file","value":[{"id":11,"name":"Scan.pdf"},{"id":10,"name":"scan.pdf"}]}],` +
+				`"comments":[{"id":100,"attachments":[{"id":12,"name":"../act.docx"}]}]}}`))
+		case strings.HasPrefix(r.URL.Path, "/files/download/"):
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="file"`)
+			w.Write([]byte("content " + strings.TrimPrefix(r.URL.Path, "/files/download/"))) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.DownloadTaskAttachments(1, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		contents[f.Name] = string(b)
+	}
+	assert.Equal(t, map[string]string{
+		"scan.pdf":     "content 10",
+		"Scan (2).pdf": "content 11",
+		".._act.docx":  "content 12",
+	}, contents)
+
+	assert.Error(t, c.DownloadTaskAttachments(2, &buf))
+}
+
+func TestClient_DownloadFiles(t *testing.T) {
+	var (
+		mu                 sync.Mutex
+		inFlight, maxLimit int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxLimit {
+			maxLimit = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		id := strings.TrimPrefix(r.URL.Path, "/files/download/")
+		if id == "3" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":"access_denied_file","error":"no access"}`)) //nolint:errcheck
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+id+`.txt"`)
+		w.Write([]byte(id)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	files := []*File{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}, {ID: 6}}
+	results := c.DownloadFiles(files, 2)
+	require.Len(t, results, len(files))
+	for i, d := range results {
+		assert.Equal(t, i, d.Index)
+		assert.Same(t, files[i], d.File)
+		if d.File.ID == 3 {
+			assert.Error(t, d.Err)
+			continue
+		}
+		require.NoError(t, d.Err)
+		assert.Equal(t, strconv.Itoa(d.File.ID)+".txt", d.Response.Filename)
+	}
+	assert.LessOrEqual(t, maxLimit, 2)
+
+	stopErr := errors.New("stop")
+	err = c.DownloadFilesFunc(files, 3, func(d *FileDownload) error { return stopErr })
+	assert.ErrorIs(t, err, stopErr)
+}
+
+func TestClient_DownloadFilesFunc_cancel(t *testing.T) {
+	aborted := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/files/download/1":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="1.txt"`)
+			w.Write([]byte("1")) //nolint:errcheck
+		case "/files/download/2":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="2.txt"`)
+			w.Write([]byte("first chunk")) //nolint:errcheck
+			w.(http.Flusher).Flush()
+			// the download never ends unless the client aborts it
+			<-r.Context().Done()
+			close(aborted)
+		}
+	}))
+	defer ts.Close()
+	// unblocks the handler if the download isn't canceled, so ts.Close doesn't hang
+	defer ts.CloseClientConnections()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	stopErr := errors.New("stop")
+	err = c.DownloadFilesFunc([]*File{{ID: 1}, {ID: 2}}, 2, func(d *FileDownload) error {
+		require.NoError(t, d.Err)
+		assert.Equal(t, 1, d.File.ID)
+		return stopErr
+	})
+	assert.ErrorIs(t, err, stopErr)
+
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("running download wasn't canceled")
+	}
+}
@@ -0,0 +1,369 @@
+package pyrus
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// TaskBundle is a portable copy of the task: the task itself, definition of its form and contents of all its files.
+// It's used to migrate tasks between forms or environments with ExportTask and ImportTask.
+type TaskBundle struct {
+	Task *TaskWithComments `json:"task"`
+	// Form is the definition of the source form, it's nil for simple tasks.
+	Form  *FormResponse `json:"form,omitempty"`
+	Files []*BundleFile `json:"-"`
+}
+
+// BundleFile is a file of TaskBundle with its contents.
+type BundleFile struct {
+	File *File
+	Data []byte
+}
+
+// importSkippedTypes are the fields which are computed by Pyrus and can't be filled.
+var importSkippedTypes = map[FieldType]struct{}{
+	FieldTypeStep:         {},
+	FieldTypeStatus:       {},
+	FieldTypeCreationDate: {},
+	FieldTypeAuthor:       {},
+	FieldTypeNote:         {},
+}
+
+// ExportTask fetches the task, its form and downloads all its files into TaskBundle.
+func (c *Client) ExportTask(taskID int) (*TaskBundle, error) {
+	task, err := c.Task(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Task == nil || task.Task.Task == nil {
+		return nil, errors.New("task is empty")
+	}
+
+	bundle := &TaskBundle{Task: task.Task}
+	if task.Task.FormID != 0 {
+		if bundle.Form, err = c.Form(task.Task.FormID); err != nil {
+			return nil, err
+		}
+	}
+
+	files := TaskAttachments(task.Task)
+	bundle.Files = make([]*BundleFile, len(files))
+	err = c.DownloadFilesFunc(files, attachmentsConcurrency, func(d *FileDownload) error {
+		if d.Err != nil {
+			return d.Err
+		}
+		bundle.Files[d.Index] = &BundleFile{File: d.File, Data: d.Response.RawFile}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// ImportTask creates a copy of the bundled task in the target form, uploading its files again.
+// Fields are matched by codes and then by names, fields missing in the target form and computed ones
+// (step, status, author, etc.) are skipped, choices of multiple choice fields are matched by names.
+// Comments, participants and approvals are not copied. Use zero targetFormID to create a simple task from the text.
+func (c *Client) ImportTask(bundle *TaskBundle, targetFormID int) (*TaskResponse, error) {
+	if bundle == nil || bundle.Task == nil || bundle.Task.Task == nil {
+		return nil, errors.New("bundle doesn't contain a task")
+	}
+
+	uploaded := make(map[int]string, len(bundle.Files))
+	for _, f := range bundle.Files {
+		if f == nil || f.File == nil {
+			continue
+		}
+		upload, err := c.UploadFile(f.File.Name, bytes.NewReader(f.Data))
+		if err != nil {
+			return nil, err
+		}
+		uploaded[f.File.ID] = upload.GUID
+	}
+
+	req := &TaskRequest{}
+	for _, f := range bundle.Task.Attachments {
+		if guid, ok := uploaded[f.ID]; ok {
+			req.Attachments = append(req.Attachments, &Attachment{GUID: guid})
+		}
+	}
+
+	if targetFormID == 0 {
+		req.Text = bundle.Task.Text
+		req.Subject = bundle.Task.Subject
+		if req.Text == "" {
+			req.Text = bundle.Task.Subject
+		}
+		return c.CreateTask(req)
+	}
+
+	target, err := c.Form(targetFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &fieldMapper{source: bundle.Form, uploaded: uploaded, columns: make(map[int]struct{})}
+	for _, table := range definitionFields(target.Fields, func(f *FormField) bool { return f.Type == FieldTypeTable }) {
+		for _, column := range definitionFields(table.Info.Columns, func(*FormField) bool { return true }) {
+			m.columns[column.ID] = struct{}{}
+		}
+	}
+	req.FormID = targetFormID
+	req.Fields = m.mapFields(target.Fields, bundle.Task.Fields, false)
+
+	return c.CreateTask(req)
+}
+
+// fieldMapper converts task fields of the source form into request fields of the target form.
+type fieldMapper struct {
+	source   *FormResponse
+	uploaded map[int]string
+	// columns are ids of table columns of the target form, which can't be matched outside of tables
+	columns map[int]struct{}
+}
+
+// mapFields maps the fields into definitions, nested fields of titles and choices are returned flat.
+func (m *fieldMapper) mapFields(defs, fields []*FormField, inTable bool) []*FormField {
+	var mapped []*FormField
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+
+		value, err := f.DecodedValue()
+		if err != nil || value == nil {
+			continue
+		}
+		if title, ok := value.(*Title); ok {
+			mapped = append(mapped, m.mapFields(defs, title.Fields, inTable)...)
+			continue
+		}
+		if _, ok := importSkippedTypes[f.Type]; ok {
+			continue
+		}
+
+		def := m.definition(defs, f, inTable)
+		if def == nil || f.Type != "" && def.Type != f.Type {
+			continue
+		}
+
+		switch v := value.(type) {
+		case *MultipleChoice:
+			mapped = append(mapped, m.mapFields(defs, v.Fields, inTable)...)
+			if value = m.mapChoice(def, v); value == nil {
+				continue
+			}
+		case Table:
+			if value = m.mapTable(def, v); value == nil {
+				continue
+			}
+		case []*File:
+			var files []*NewFile
+			for _, file := range v {
+				if guid, ok := m.uploaded[file.ID]; ok {
+					files = append(files, &NewFile{GUID: guid})
+				}
+			}
+			if len(files) == 0 {
+				continue
+			}
+			value = files
+		case *Person:
+			if v.Email != "" {
+				value = &Person{Email: v.Email}
+			} else {
+				value = &Person{ID: v.ID}
+			}
+		case *CatalogItem:
+			value = &CatalogItem{ItemID: v.ItemID, ItemIDs: v.ItemIDs}
+		case *FormLink:
+			value = &FormLink{TaskIDs: v.TaskIDs}
+		}
+
+		mapped = append(mapped, &FormField{ID: def.ID, Type: def.Type, Value: value})
+	}
+
+	return mapped
+}
+
+// definition finds the target definition of the field among defs by code and then by unique name.
+func (m *fieldMapper) definition(defs []*FormField, f *FormField, inTable bool) *FormField {
+	code, name := "", f.Name
+	if f.Info != nil {
+		code = f.Info.Code
+	}
+	if m.source != nil && f.ID != 0 {
+		if found := definitionFields(m.source.Fields, func(def *FormField) bool { return def.ID == f.ID }); len(found) > 0 {
+			if found[0].Info != nil && found[0].Info.Code != "" {
+				code = found[0].Info.Code
+			}
+			if name == "" {
+				name = found[0].Name
+			}
+		}
+	}
+
+	candidate := func(def *FormField) bool {
+		_, column := m.columns[def.ID]
+		return inTable || !column
+	}
+	if code != "" {
+		found := definitionFields(defs, func(def *FormField) bool {
+			return candidate(def) && def.Info != nil && def.Info.Code == code
+		})
+		if len(found) > 0 {
+			return found[0]
+		}
+	}
+	if name != "" {
+		found := definitionFields(defs, func(def *FormField) bool { return candidate(def) && def.Name == name })
+		if len(found) == 1 {
+			return found[0]
+		}
+	}
+
+	return nil
+}
+
+// mapChoice maps selected choices by their names.
+func (m *fieldMapper) mapChoice(def *FormField, v *MultipleChoice) *MultipleChoice {
+	if def.Info == nil {
+		return nil
+	}
+
+	var ids []int
+	for _, name := range v.ChoiceNames {
+		for _, option := range def.Info.Options {
+			if option != nil && !option.Deleted && option.ChoiceValue == name {
+				ids = append(ids, option.ChoiceID)
+				break
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return &MultipleChoice{ChoiceIDs: ids}
+}
+
+// mapTable maps cells of the table rows into columns of the target table.
+func (m *fieldMapper) mapTable(def *FormField, v Table) Table {
+	if def.Info == nil {
+		return nil
+	}
+
+	var table Table
+	for i, row := range v {
+		if row == nil {
+			continue
+		}
+		cells := m.mapFields(def.Info.Columns, row.Cells, true)
+		if len(cells) == 0 {
+			continue
+		}
+		table = append(table, &TableRow{RowID: i, Cells: cells})
+	}
+
+	return table
+}
+
+// WriteTo writes the bundle as a zip archive containing task.json and files/ID/NAME entries.
+func (b *TaskBundle) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	zw := zip.NewWriter(cw)
+
+	fw, err := zw.Create("task.json")
+	if err != nil {
+		return cw.n, err
+	}
+	if err := json.NewEncoder(fw).Encode(b); err != nil {
+		return cw.n, err
+	}
+
+	for _, f := range b.Files {
+		if f == nil || f.File == nil {
+			continue
+		}
+		fw, err := zw.Create("files/" + strconv.Itoa(f.File.ID) + "/" + archiveNames([]*File{f.File})[0])
+		if err != nil {
+			return cw.n, err
+		}
+		if _, err := fw.Write(f.Data); err != nil {
+			return cw.n, err
+		}
+	}
+
+	err = zw.Close()
+	return cw.n, err
+}
+
+// ReadTaskBundle reads the bundle written by WriteTo.
+func ReadTaskBundle(r io.ReaderAt, size int64) (*TaskBundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bundle TaskBundle
+		data   = make(map[int][]byte)
+	)
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close() //nolint:errcheck
+		if err != nil {
+			return nil, err
+		}
+
+		if zf.Name == "task.json" {
+			if err := json.Unmarshal(b, &bundle); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		parts := strings.SplitN(zf.Name, "/", 3)
+		if len(parts) != 3 || parts[0] != "files" {
+			continue
+		}
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		data[id] = b
+	}
+	if bundle.Task == nil {
+		return nil, errors.New("bundle doesn't contain task.json")
+	}
+
+	for _, f := range TaskAttachments(bundle.Task) {
+		if b, ok := data[f.ID]; ok {
+			bundle.Files = append(bundle.Files, &BundleFile{File: f, Data: b})
+		}
+	}
+
+	return &bundle, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
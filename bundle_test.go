@@ -0,0 +1,100 @@
+package pyrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ImportTask(t *testing.T) {
+	var created map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/tasks/1":
+			w.Write([]byte(`{"task":{"id":1,"form_id":10,"attachments":[{"id":31,"name":"act.pdf"}],"fields":[` + //nolint:errcheck
+				`{"id":1,"type":"number","name":"Сумма","value":1500},` +
+				`{"id":7,"type":"title","name":"Детали","value":{"fields":[{"id":6,"type":"text","name":"Комментарий","value":"Срочно"}]}},` +
+				`{"id":2,"type":"multiple_choice","name":"Оплачено","value":{"choice_ids":[1],"choice_names":["Да"]}},` +
+				`{"id":3,"type":"file","name":"Счёт","value":[{"id":30,"name":"invoice.pdf"}]},` +
+				`{"id":8,"type":"step","name":"Этап","value":2},` +
+				`{"id":9,"type":"text","name":"Лишнее","value":"x"},` +
+				`{"id":4,"type":"table","name":"Товары","value":[{"row_id":0,"cells":[{"id":5,"type":"text","name":"Товар","value":"Бумага"}]}]}` +
+				`]}}`))
+		case "/forms/10":
+			w.Write([]byte(`{"id":10,"fields":[{"id":1,"type":"number","name":"Сумма","info":{"code":"amount"}}]}`)) //nolint:errcheck
+		case "/forms/20":
+			w.Write([]byte(`{"id":20,"fields":[` + //nolint:errcheck
+				`{"id":101,"type":"number","name":"Сумма заявки","info":{"code":"amount"}},` +
+				`{"id":102,"type":"multiple_choice","name":"Оплачено","info":{"options":[{"choice_id":5,"choice_value":"Нет"},{"choice_id":6,"choice_value":"Да"}]}},` +
+				`{"id":103,"type":"file","name":"Счёт"},` +
+				`{"id":104,"type":"table","name":"Товары","info":{"columns":[{"id":105,"type":"text","name":"Товар"}]}},` +
+				`{"id":106,"type":"text","name":"Комментарий"}` +
+				`]}`))
+		case "/files/download/30", "/files/download/31":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `attachment; filename="file.pdf"`)
+			w.Write([]byte("pdf " + r.URL.Path[len("/files/download/"):])) //nolint:errcheck
+		case "/files/upload":
+			mr, err := r.MultipartReader()
+			require.NoError(t, err)
+			part, err := mr.NextPart()
+			require.NoError(t, err)
+			b, err := ioutil.ReadAll(part)
+			require.NoError(t, err)
+			// guid ends with the file id
+			w.Write([]byte(`{"guid":"5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0` + string(b[len(b)-2:]) + `"}`)) //nolint:errcheck
+		case "/tasks":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.Write([]byte(`{"task":{"id":2,"form_id":20}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	bundle, err := c.ExportTask(1)
+	require.NoError(t, err)
+	require.NotNil(t, bundle.Form)
+	require.Len(t, bundle.Files, 2)
+
+	var buf bytes.Buffer
+	n, err := bundle.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+
+	bundle, err = ReadTaskBundle(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, bundle.Files, 2)
+	assert.Equal(t, "pdf 31", string(bundle.Files[0].Data))
+
+	task, err := c.ImportTask(bundle, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 2, task.Task.ID)
+
+	expected := `{
+		"form_id": 20,
+		"attachments": [{"guid": "5d8dc3d6-27e7-4cd4-a057-2b4f4d74e031"}],
+		"fields": [
+			{"id": 101, "type": "number", "value": 1500},
+			{"id": 106, "type": "text", "value": "Срочно"},
+			{"id": 102, "type": "multiple_choice", "value": {"choice_ids": [6]}},
+			{"id": 103, "type": "file", "value": [{"guid": "5d8dc3d6-27e7-4cd4-a057-2b4f4d74e030"}]},
+			{"id": 104, "type": "table", "value": [{"row_id": 0, "cells": [{"id": 105, "type": "text", "value": "Бумага"}]}]}
+		]
+	}`
+	b, err := json.Marshal(created)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(b))
+}
@@ -0,0 +1,111 @@
+package pyrus
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response body stored by ResponseCache together with its validators.
+type CachedResponse struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	Expires      time.Time
+}
+
+// ResponseCache stores responses of GET requests keyed by URL.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+	Delete(key string)
+}
+
+// MemoryResponseCache is an in-memory ResponseCache.
+type MemoryResponseCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CachedResponse
+}
+
+// NewMemoryResponseCache returns an empty MemoryResponseCache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{
+		entries: make(map[string]*CachedResponse),
+	}
+}
+
+// Get returns the cached response.
+func (c *MemoryResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resp, ok := c.entries[key]
+	return resp, ok
+}
+
+// Set stores the response.
+func (c *MemoryResponseCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	c.entries[key] = resp
+	c.mu.Unlock()
+}
+
+// Delete removes the response.
+func (c *MemoryResponseCache) Delete(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// WithResponseCache enables caching of form and catalog definitions.
+// Within ttl cached responses are returned without any request, after that they are revalidated
+// with If-None-Match and If-Modified-Since headers if Pyrus provided ETag or Last-Modified
+// and downloaded again otherwise. Use WithCacheTTL to cache other endpoints or to override ttl per class.
+func WithResponseCache(cache ResponseCache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithCacheTTL overrides ttl of the endpoint class, e.g. to cache contacts and roles which are not cached by default.
+// Zero ttl makes every request revalidate the cached response, negative ttl disables caching of the class.
+// It has no effect without WithResponseCache.
+func WithCacheTTL(class CacheClass, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = make(map[CacheClass]time.Duration)
+		}
+		c.cacheTTLs[class] = ttl
+	}
+}
+
+// cacheTTLFor returns ttl of the path responses and reports whether they are cached.
+func (c *Client) cacheTTLFor(path string) (time.Duration, bool) {
+	class, ok := cacheClassOf(path)
+	if c.cache == nil || !ok {
+		return 0, false
+	}
+	if ttl, ok := c.cacheTTLs[class]; ok {
+		return ttl, ttl >= 0
+	}
+
+	return c.cacheTTL, class == CacheClassForms || class == CacheClassCatalogs
+}
+
+// cacheClassOf returns the class of the list or single entity path.
+func cacheClassOf(path string) (CacheClass, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > 2 {
+		return "", false
+	}
+
+	switch class := CacheClass(parts[0]); class {
+	case CacheClassForms, CacheClassCatalogs, CacheClassMembers, CacheClassRoles:
+		return class, true
+	case CacheClassContacts:
+		return class, len(parts) == 1
+	default:
+		return "", false
+	}
+}
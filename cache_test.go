@@ -0,0 +1,122 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithResponseCache(t *testing.T) {
+	var downloads, revalidations int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidations++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			downloads++
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"forms":[{"id":1,"name":"Форма"}]}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryResponseCache()
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithResponseCache(cache, time.Hour))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		forms, err := c.Forms()
+		require.NoError(t, err)
+		require.Len(t, forms.Forms, 1)
+		assert.Equal(t, "Форма", forms.Forms[0].Name)
+	}
+	assert.Equal(t, 1, downloads)
+	assert.Equal(t, 0, revalidations)
+
+	// expire the entry to force revalidation
+	cached, ok := cache.Get(ts.URL + "/forms")
+	require.True(t, ok)
+	cached.Expires = time.Now().Add(-time.Second)
+
+	forms, err := c.Forms()
+	require.NoError(t, err)
+	require.Len(t, forms.Forms, 1)
+	assert.Equal(t, 1, downloads)
+	assert.Equal(t, 1, revalidations)
+}
+
+func TestCacheClassOf(t *testing.T) {
+	for path, class := range map[string]CacheClass{
+		"/forms":            CacheClassForms,
+		"/forms/1":          CacheClassForms,
+		"/catalogs/1":       CacheClassCatalogs,
+		"/roles":            CacheClassRoles,
+		"/contacts":         CacheClassContacts,
+		"/forms/1/register": "",
+		"/tasks/1":          "",
+		"/contacts/1":       "",
+	} {
+		got, ok := cacheClassOf(path)
+		assert.Equal(t, class != "", ok, path)
+		if ok {
+			assert.Equal(t, class, got, path)
+		}
+	}
+}
+
+func TestWithCacheTTL(t *testing.T) {
+	downloads := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			downloads["forms"]++
+			w.Write([]byte(`{"forms":[]}`)) //nolint:errcheck
+		case "/roles":
+			downloads["roles"]++
+			w.Write([]byte(`{"roles":[{"id":1,"name":"Роль"}]}`)) //nolint:errcheck
+		case "/roles/1":
+			w.Write([]byte(`{"id":1,"name":"Роль"}`)) //nolint:errcheck
+		case "/contacts":
+			downloads["contacts"]++
+			w.Write([]byte(`{"organizations":[]}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL),
+		WithResponseCache(NewMemoryResponseCache(), time.Hour),
+		WithCacheTTL(CacheClassRoles, time.Hour),
+		WithCacheTTL(CacheClassForms, -1),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = c.Forms()
+		require.NoError(t, err)
+		_, err = c.Roles()
+		require.NoError(t, err)
+		_, err = c.Contacts(false)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int{"forms": 2, "roles": 1, "contacts": 2}, downloads)
+
+	// role update invalidates the list of roles
+	_, err = c.UpdateRole(1, "Роль", nil, nil, false)
+	require.NoError(t, err)
+	_, err = c.Roles()
+	require.NoError(t, err)
+	assert.Equal(t, 2, downloads["roles"])
+}
@@ -0,0 +1,44 @@
+package pyrus
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// callRecordingFormats are the audio formats accepted by Pyrus as call recordings.
+var callRecordingFormats = map[string]struct{}{
+	".mp3": {},
+	".wav": {},
+	".ogg": {},
+	".m4a": {},
+}
+
+// AttachCallRecording uploads the call recording and attaches it to the call by call_guid.
+// Recordings with unsupported extension are rejected without uploading with ErrUnsupportedAttachmentFormat.
+func (c *Client) AttachCallRecording(callGUID, filename string, file io.Reader) error {
+	if err := checkCallRecordingFormat(filename); err != nil {
+		return err
+	}
+
+	upload, err := c.UploadFile(filename, file)
+	if err != nil {
+		return err
+	}
+
+	return c.AddCallDetails(callGUID, &AddCallDetailsRequest{
+		FileGUID: upload.GUID,
+	})
+}
+
+func checkCallRecordingFormat(filename string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if _, ok := callRecordingFormats[ext]; ok {
+		return nil
+	}
+
+	return Error{
+		Code:        ErrUnsupportedAttachmentFormat,
+		Description: "unsupported call recording format: " + filename,
+	}
+}
@@ -0,0 +1,208 @@
+package pyrus
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// CatalogKeyMap maps keys of an external system to catalog items using the designated key column,
+// so sync jobs update existing items instead of matching them by display values which could change.
+// Pyrus matches items of SyncCatalog by the first column, so the key column should be the first one
+// for updated items to keep their ids.
+type CatalogKeyMap struct {
+	headers []string
+	column  int
+	keys    []string
+	items   map[string]*CatalogItem
+	byID    map[int]string
+}
+
+// NewCatalogKeyMap indexes items of the catalog by values of the key column.
+// It fails if the column doesn't exist or some keys are empty or duplicated.
+func NewCatalogKeyMap(catalog *CatalogResponse, keyColumn string) (*CatalogKeyMap, error) {
+	if catalog == nil {
+		return nil, errors.New("catalog cannot be nil")
+	}
+
+	m := &CatalogKeyMap{
+		column: -1,
+		items:  make(map[string]*CatalogItem, len(catalog.Items)),
+		byID:   make(map[int]string, len(catalog.Items)),
+	}
+	// Values of items correspond to non-nil headers
+	for _, h := range catalog.CatalogHeaders {
+		if h == nil {
+			continue
+		}
+		if h.Name == keyColumn {
+			m.column = len(m.headers)
+		}
+		m.headers = append(m.headers, h.Name)
+	}
+	if m.column < 0 {
+		return nil, fmt.Errorf("catalog doesn't have %q column", keyColumn)
+	}
+
+	for i, item := range catalog.Items {
+		if item == nil || m.column >= len(item.Values) {
+			return nil, fmt.Errorf("item %d doesn't have %q column", i, keyColumn)
+		}
+		key := item.Values[m.column]
+		if key == "" {
+			return nil, fmt.Errorf("item %d has empty key", item.ItemID)
+		}
+		if _, ok := m.items[key]; ok {
+			return nil, fmt.Errorf("key %q is duplicated", key)
+		}
+
+		m.keys = append(m.keys, key)
+		m.items[key] = &CatalogItem{
+			ItemID: item.ItemID,
+			Values: append([]string(nil), item.Values...),
+		}
+		if item.ItemID != 0 {
+			m.byID[item.ItemID] = key
+		}
+	}
+
+	return m, nil
+}
+
+// Headers returns the catalog headers.
+func (m *CatalogKeyMap) Headers() []string {
+	return m.headers
+}
+
+// ItemID returns id of the item with the key, it's zero for items added since the last sync.
+func (m *CatalogKeyMap) ItemID(key string) (int, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return 0, false
+	}
+
+	return item.ItemID, true
+}
+
+// Key returns the key of the item.
+func (m *CatalogKeyMap) Key(itemID int) (string, bool) {
+	key, ok := m.byID[itemID]
+	return key, ok
+}
+
+// Item returns a copy of the item with the key.
+func (m *CatalogKeyMap) Item(key string) (*CatalogItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	return &CatalogItem{
+		ItemID: item.ItemID,
+		Values: append([]string(nil), item.Values...),
+	}, true
+}
+
+// Upsert sets values of the item with the key by header names, adding the item if there is no such key.
+// Columns missing in values keep their current values, the key column can't be changed.
+func (m *CatalogKeyMap) Upsert(key string, values map[string]string) error {
+	if key == "" {
+		return errors.New("key cannot be empty")
+	}
+
+	item, ok := m.items[key]
+	if !ok {
+		item = &CatalogItem{Values: make([]string, len(m.headers))}
+		item.Values[m.column] = key
+	}
+
+	updated := append([]string(nil), item.Values...)
+	for name, value := range values {
+		i := m.headerIndex(name)
+		if i < 0 {
+			return fmt.Errorf("catalog doesn't have %q column", name)
+		}
+		if i == m.column && value != key {
+			return errors.New("key column can't be changed, got " + strconv.Quote(value) + " for " + strconv.Quote(key))
+		}
+		updated[i] = value
+	}
+	item.Values = updated
+
+	if !ok {
+		m.keys = append(m.keys, key)
+		m.items[key] = item
+	}
+
+	return nil
+}
+
+// Delete removes the item with the key, so it's deleted by the next sync.
+func (m *CatalogKeyMap) Delete(key string) {
+	item, ok := m.items[key]
+	if !ok {
+		return
+	}
+
+	delete(m.items, key)
+	delete(m.byID, item.ItemID)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Items returns items to pass to SyncCatalog in the order of the catalog followed by the added items.
+func (m *CatalogKeyMap) Items() []*CatalogItem {
+	items := make([]*CatalogItem, 0, len(m.keys))
+	for _, key := range m.keys {
+		items = append(items, &CatalogItem{Values: append([]string(nil), m.items[key].Values...)})
+	}
+
+	return items
+}
+
+// Apply updates ids of items added or deleted by the sync.
+func (m *CatalogKeyMap) Apply(resp *SyncCatalogResponse) {
+	if resp == nil {
+		return
+	}
+
+	for _, item := range append(resp.Added, resp.Updated...) {
+		if item == nil || m.column >= len(item.Values) || item.ItemID == 0 {
+			continue
+		}
+		if current, ok := m.items[item.Values[m.column]]; ok {
+			current.ItemID = item.ItemID
+			m.byID[item.ItemID] = item.Values[m.column]
+		}
+	}
+	for _, item := range resp.Deleted {
+		if item != nil {
+			delete(m.byID, item.ItemID)
+		}
+	}
+}
+
+// SyncCatalogKeyMap syncs the catalog with items of the map and updates ids of the added items.
+func (c *Client) SyncCatalogKeyMap(catalogID int, m *CatalogKeyMap) (*SyncCatalogResponse, error) {
+	resp, err := c.SyncCatalog(catalogID, true, m.Headers(), m.Items())
+	if err != nil {
+		return nil, err
+	}
+	m.Apply(resp)
+
+	return resp, nil
+}
+
+func (m *CatalogKeyMap) headerIndex(name string) int {
+	for i, h := range m.headers {
+		if h == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,104 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogKeyMap(t *testing.T) {
+	catalog := &CatalogResponse{
+		CatalogHeaders: []*CatalogHeader{{Name: "Code"}, {Name: "Name"}, {Name: "City"}},
+		Items: []*CatalogItem{
+			{ItemID: 10, Values: []string{"C-1", "Acme", "Moscow"}},
+			{ItemID: 11, Values: []string{"C-2", "Globex", "Kazan"}},
+		},
+	}
+
+	m, err := NewCatalogKeyMap(catalog, "Code")
+	require.NoError(t, err)
+
+	id, ok := m.ItemID("C-2")
+	assert.True(t, ok)
+	assert.Equal(t, 11, id)
+	key, ok := m.Key(10)
+	assert.True(t, ok)
+	assert.Equal(t, "C-1", key)
+
+	// Renamed client is still matched by the key
+	require.NoError(t, m.Upsert("C-1", map[string]string{"Name": "Acme Corp"}))
+	require.NoError(t, m.Upsert("C-3", map[string]string{"Name": "Initech", "City": "Tver"}))
+	m.Delete("C-2")
+
+	assert.Error(t, m.Upsert("C-1", map[string]string{"Phone": "1"}))
+	assert.Error(t, m.Upsert("C-1", map[string]string{"Code": "C-9"}))
+	assert.Error(t, m.Upsert("", nil))
+
+	item, ok := m.Item("C-1")
+	require.True(t, ok)
+	assert.Equal(t, []string{"C-1", "Acme Corp", "Moscow"}, item.Values)
+	assert.Equal(t, 10, item.ItemID)
+
+	// Original catalog isn't modified
+	assert.Equal(t, "Acme", catalog.Items[0].Values[1])
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/catalogs/1":
+			var req syncCatalogRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.Apply)
+			assert.Equal(t, []string{"Code", "Name", "City"}, req.CatalogHeaders)
+			require.Len(t, req.Items, 2)
+			assert.Equal(t, []string{"C-1", "Acme Corp", "Moscow"}, req.Items[0].Values)
+			assert.Equal(t, []string{"C-3", "Initech", "Tver"}, req.Items[1].Values)
+
+			w.Write([]byte(`{"apply":true,"added":[{"item_id":12,"values":["C-3","Initech","Tver"]}],` + //nolint:errcheck
+				`"updated":[{"item_id":10,"values":["C-1","Acme Corp","Moscow"]}],` +
+				`"deleted":[{"item_id":11,"values":["C-2","Globex","Kazan"]}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	_, err = c.SyncCatalogKeyMap(1, m)
+	require.NoError(t, err)
+
+	id, ok = m.ItemID("C-3")
+	assert.True(t, ok)
+	assert.Equal(t, 12, id)
+	_, ok = m.Key(11)
+	assert.False(t, ok)
+
+	t.Run("nil headers", func(t *testing.T) {
+		m, err := NewCatalogKeyMap(&CatalogResponse{
+			CatalogHeaders: []*CatalogHeader{nil, {Name: "Name"}, {Name: "Code"}},
+			Items:          []*CatalogItem{{ItemID: 1, Values: []string{"Acme", "C-1"}}},
+		}, "Code")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Name", "Code"}, m.Headers())
+		id, ok := m.ItemID("C-1")
+		assert.True(t, ok)
+		assert.Equal(t, 1, id)
+	})
+
+	t.Run("invalid catalogs", func(t *testing.T) {
+		_, err := NewCatalogKeyMap(catalog, "Phone")
+		assert.Error(t, err)
+
+		_, err = NewCatalogKeyMap(&CatalogResponse{
+			CatalogHeaders: []*CatalogHeader{{Name: "Code"}},
+			Items:          []*CatalogItem{{ItemID: 1, Values: []string{"C-1"}}, {ItemID: 2, Values: []string{"C-1"}}},
+		}, "Code")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package pyrus
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Catalog limits documented by Pyrus.
+const (
+	MaxCatalogItems      = 15000
+	MaxCatalogItemLength = 500
+)
+
+// ValidateCatalog checks catalog headers and items against the limits checked by Pyrus.
+// Errors are Error values with the same codes as returned by the API, e.g. ErrCatalogDuplicateRows.
+// CreateCatalog and SyncCatalog call it before sending.
+func ValidateCatalog(headers []string, items []*CatalogItem) error {
+	if len(headers) == 0 {
+		return localError(ErrEmptyCatalogHeaders, "catalog headers are empty")
+	}
+
+	seenHeaders := make(map[string]struct{}, len(headers))
+	for i, h := range headers {
+		if strings.TrimSpace(h) == "" {
+			return localError(ErrEmptyCatalogHeaders, "header "+strconv.Itoa(i)+" is empty")
+		}
+		if _, ok := seenHeaders[h]; ok {
+			return localError(ErrCatalogDuplicateHeaders, "header "+strconv.Quote(h)+" is duplicated")
+		}
+		seenHeaders[h] = struct{}{}
+	}
+
+	if len(items) > MaxCatalogItems {
+		return localError(ErrTooManyCatalogItems, strconv.Itoa(len(items))+" items exceed the limit of "+strconv.Itoa(MaxCatalogItems))
+	}
+
+	seenRows := make(map[string]int, len(items))
+	for i, item := range items {
+		if item == nil {
+			return localError(ErrCatalogHeadersItemsMismatch, "item "+strconv.Itoa(i)+" is nil")
+		}
+		if len(item.Values) != len(headers) {
+			return localError(ErrCatalogHeadersItemsMismatch, "item "+strconv.Itoa(i)+" has "+strconv.Itoa(len(item.Values))+
+				" values for "+strconv.Itoa(len(headers))+" headers")
+		}
+		for j, v := range item.Values {
+			if utf8.RuneCountInString(v) > MaxCatalogItemLength {
+				return localError(ErrCatalogItemMaxLengthExceeded, "value of item "+strconv.Itoa(i)+" in column "+
+					strconv.Quote(headers[j])+" is longer than "+strconv.Itoa(MaxCatalogItemLength)+" characters")
+			}
+		}
+
+		key := strings.Join(item.Values, "\x00")
+		if first, ok := seenRows[key]; ok {
+			return localError(ErrCatalogDuplicateRows, "item "+strconv.Itoa(i)+" duplicates item "+strconv.Itoa(first))
+		}
+		seenRows[key] = i
+	}
+
+	return nil
+}
+
+// Validate allows to validate request before sending.
+func (r catalogRequest) Validate() error {
+	return ValidateCatalog(r.CatalogHeaders, r.Items)
+}
+
+// Validate allows to validate request before sending.
+func (r syncCatalogRequest) Validate() error {
+	return ValidateCatalog(r.CatalogHeaders, r.Items)
+}
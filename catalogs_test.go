@@ -0,0 +1,56 @@
+package pyrus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCatalog(t *testing.T) {
+	headers := []string{"Имя", "Адрес"}
+	item := func(values ...string) *CatalogItem {
+		return &CatalogItem{Values: values}
+	}
+
+	tests := []struct {
+		name    string
+		headers []string
+		items   []*CatalogItem
+		code    ErrorCode
+	}{
+		{"valid", headers, []*CatalogItem{item("Иван", "Эсперанто 34"), item("Василий", "Островского 5")}, ""},
+		{"no headers", nil, nil, ErrEmptyCatalogHeaders},
+		{"empty header", []string{"Имя", " "}, nil, ErrEmptyCatalogHeaders},
+		{"duplicate headers", []string{"Имя", "Имя"}, nil, ErrCatalogDuplicateHeaders},
+		{"values mismatch", headers, []*CatalogItem{item("Иван")}, ErrCatalogHeadersItemsMismatch},
+		{"too long", headers, []*CatalogItem{item("Иван", strings.Repeat("я", MaxCatalogItemLength+1))}, ErrCatalogItemMaxLengthExceeded},
+		{"duplicate rows", headers, []*CatalogItem{item("Иван", "Эсперанто 34"), item("Иван", "Эсперанто 34")}, ErrCatalogDuplicateRows},
+		{"too many items", headers, make([]*CatalogItem, MaxCatalogItems+1), ErrTooManyCatalogItems},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCatalog(tt.headers, tt.items)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var apiErr Error
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.code, apiErr.Code)
+		})
+	}
+}
+
+func TestClient_CreateCatalog_validation(t *testing.T) {
+	c, err := NewClient("login", "key", WithBaseURL("http://127.0.0.1:0"))
+	require.NoError(t, err)
+
+	_, err = c.CreateCatalog("Test", []string{"Имя"}, []*CatalogItem{{Values: []string{"a"}}, {Values: []string{"a"}}})
+	var apiErr Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrCatalogDuplicateRows, apiErr.Code)
+}
@@ -0,0 +1,241 @@
+package pyrus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CatalogTree is a hierarchy of a multi-level catalog, where every level is stored in its own column
+// and items of upper levels have the columns of lower levels empty.
+type CatalogTree struct {
+	headers []string
+	levels  []int
+	roots   []*CatalogNode
+	byID    map[int]*CatalogNode
+}
+
+// CatalogNode is a node of CatalogTree.
+type CatalogNode struct {
+	Name string
+	// Path contains names of the node and its ancestors starting from the root.
+	Path     []string
+	Parent   *CatalogNode
+	Children []*CatalogNode
+	// Item is the catalog item of the node, nil if the node exists only as a part of paths of its children.
+	Item *CatalogItem
+}
+
+// NewCatalogTree builds the hierarchy of the catalog from the level columns ordered from the top level.
+// All columns are levels if none are passed.
+func NewCatalogTree(catalog *CatalogResponse, levels ...string) (*CatalogTree, error) {
+	if catalog == nil {
+		return nil, errors.New("catalog cannot be nil")
+	}
+
+	headers := make([]string, 0, len(catalog.CatalogHeaders))
+	for _, h := range catalog.CatalogHeaders {
+		if h != nil {
+			headers = append(headers, h.Name)
+		}
+	}
+	if len(levels) == 0 {
+		levels = headers
+	}
+
+	t, err := newCatalogTree(headers, levels)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range catalog.Items {
+		if item == nil {
+			continue
+		}
+		if len(item.Values) != len(headers) {
+			return nil, fmt.Errorf("item %d has %d values for %d headers", i, len(item.Values), len(headers))
+		}
+
+		path := make([]string, 0, len(t.levels))
+		for _, column := range t.levels {
+			if item.Values[column] == "" {
+				break
+			}
+			path = append(path, item.Values[column])
+		}
+		if len(path) == 0 {
+			return nil, fmt.Errorf("item %d has empty top level", item.ItemID)
+		}
+
+		n, err := t.Add(path...)
+		if err != nil {
+			return nil, err
+		}
+		if n.Item != nil {
+			return nil, fmt.Errorf("items %d and %d have the same path %q", n.Item.ItemID, item.ItemID, strings.Join(path, " / "))
+		}
+		n.Item = item
+		if item.ItemID != 0 {
+			t.byID[item.ItemID] = n
+		}
+	}
+
+	return t, nil
+}
+
+// NewEmptyCatalogTree returns a tree of a new catalog with the level columns, see Add and Items.
+func NewEmptyCatalogTree(levels ...string) (*CatalogTree, error) {
+	return newCatalogTree(levels, levels)
+}
+
+func newCatalogTree(headers, levels []string) (*CatalogTree, error) {
+	t := &CatalogTree{
+		headers: headers,
+		byID:    make(map[int]*CatalogNode),
+	}
+	for _, level := range levels {
+		column := -1
+		for i, h := range headers {
+			if h == level {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			return nil, fmt.Errorf("catalog doesn't have %q column", level)
+		}
+		t.levels = append(t.levels, column)
+	}
+	if len(t.levels) == 0 {
+		return nil, errors.New("catalog levels are empty")
+	}
+
+	return t, nil
+}
+
+// Roots returns nodes of the top level.
+func (t *CatalogTree) Roots() []*CatalogNode {
+	return t.roots
+}
+
+// Add returns the node with the path adding it and its missing ancestors.
+// It fails if the path is empty or deeper than the number of levels.
+func (t *CatalogTree) Add(path ...string) (*CatalogNode, error) {
+	if len(path) == 0 || len(path) > len(t.levels) {
+		return nil, fmt.Errorf("path must have from 1 to %d names, got %d", len(t.levels), len(path))
+	}
+
+	var parent *CatalogNode
+	nodes := &t.roots
+	for depth, name := range path {
+		var n *CatalogNode
+		for _, child := range *nodes {
+			if child.Name == name {
+				n = child
+				break
+			}
+		}
+		if n == nil {
+			n = &CatalogNode{
+				Name:   name,
+				Path:   append([]string(nil), path[:depth+1]...),
+				Parent: parent,
+			}
+			*nodes = append(*nodes, n)
+		}
+
+		parent = n
+		nodes = &n.Children
+	}
+
+	return parent, nil
+}
+
+// Find returns the node with the path or nil if there is no such node.
+func (t *CatalogTree) Find(path ...string) *CatalogNode {
+	var n *CatalogNode
+	nodes := t.roots
+	for _, name := range path {
+		n = nil
+		for _, child := range nodes {
+			if child.Name == name {
+				n = child
+				break
+			}
+		}
+		if n == nil {
+			return nil
+		}
+		nodes = n.Children
+	}
+
+	return n
+}
+
+// Node returns the node of the item or nil if the tree doesn't contain it.
+func (t *CatalogTree) Node(itemID int) *CatalogNode {
+	return t.byID[itemID]
+}
+
+// Path returns the full path of the item, e.g. ["Russia", "Tatarstan", "Kazan"].
+func (t *CatalogTree) Path(itemID int) []string {
+	n := t.Node(itemID)
+	if n == nil {
+		return nil
+	}
+
+	return append([]string(nil), n.Path...)
+}
+
+// Select returns the value of a catalog field for the item with the path to fill the form field of TaskRequest.
+func (t *CatalogTree) Select(path ...string) (*CatalogItem, error) {
+	n := t.Find(path...)
+	if n == nil {
+		return nil, fmt.Errorf("catalog doesn't have %q", strings.Join(path, " / "))
+	}
+	if n.Item == nil || n.Item.ItemID == 0 {
+		return nil, fmt.Errorf("%q isn't a catalog item", strings.Join(path, " / "))
+	}
+
+	return &CatalogItem{ItemID: n.Item.ItemID}, nil
+}
+
+// Headers returns the catalog headers.
+func (t *CatalogTree) Headers() []string {
+	return t.headers
+}
+
+// Items returns items of the tree to pass to CreateCatalog or SyncCatalog: items of the nodes which have them
+// and rows with level columns filled for the leaves added with Add.
+func (t *CatalogTree) Items() []*CatalogItem {
+	var items []*CatalogItem
+	t.Walk(func(n *CatalogNode) bool {
+		switch {
+		case n.Item != nil:
+			items = append(items, &CatalogItem{Values: append([]string(nil), n.Item.Values...)})
+		case len(n.Children) == 0:
+			values := make([]string, len(t.headers))
+			for depth, name := range n.Path {
+				values[t.levels[depth]] = name
+			}
+			items = append(items, &CatalogItem{Values: values})
+		}
+		return true
+	})
+
+	return items
+}
+
+// Walk calls fn for every node in depth-first order, parents before children.
+// Children of the node are skipped if fn returns false.
+func (t *CatalogTree) Walk(fn func(n *CatalogNode) bool) {
+	var walk func(nodes []*CatalogNode)
+	walk = func(nodes []*CatalogNode) {
+		for _, n := range nodes {
+			if fn(n) {
+				walk(n.Children)
+			}
+		}
+	}
+	walk(t.roots)
+}
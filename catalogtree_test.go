@@ -0,0 +1,93 @@
+package pyrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogTree(t *testing.T) {
+	catalog := &CatalogResponse{
+		CatalogHeaders: []*CatalogHeader{{Name: "Country"}, {Name: "Region"}, {Name: "City"}, {Name: "Code"}},
+		Items: []*CatalogItem{
+			{ItemID: 1, Values: []string{"Russia", "", "", "RU"}},
+			{ItemID: 2, Values: []string{"Russia", "Tatarstan", "Kazan", "KZN"}},
+			{ItemID: 3, Values: []string{"Russia", "Tatarstan", "Naberezhnye Chelny", "NBC"}},
+			{ItemID: 4, Values: []string{"Russia", "Moscow Oblast", "Khimki", "KHM"}},
+			{ItemID: 5, Values: []string{"Kazakhstan", "Almaty Region", "Almaty", "ALA"}},
+		},
+	}
+
+	tree, err := NewCatalogTree(catalog, "Country", "Region", "City")
+	require.NoError(t, err)
+
+	roots := tree.Roots()
+	require.Len(t, roots, 2)
+	assert.Equal(t, "Russia", roots[0].Name)
+	assert.Equal(t, 1, roots[0].Item.ItemID)
+	require.Len(t, roots[0].Children, 2)
+	assert.Nil(t, roots[0].Children[0].Item)
+
+	assert.Equal(t, []string{"Russia", "Tatarstan", "Kazan"}, tree.Path(2))
+	tree.Path(2)[0] = "Changed"
+	assert.Equal(t, "Russia", tree.Node(2).Path[0])
+	assert.Nil(t, tree.Path(100))
+	assert.Same(t, roots[0], tree.Node(3).Parent.Parent)
+
+	item, err := tree.Select("Russia", "Tatarstan", "Naberezhnye Chelny")
+	require.NoError(t, err)
+	assert.Equal(t, &CatalogItem{ItemID: 3}, item)
+
+	_, err = tree.Select("Russia", "Tatarstan")
+	assert.Error(t, err)
+	_, err = tree.Select("Russia", "Bashkortostan")
+	assert.Error(t, err)
+
+	var names []string
+	tree.Walk(func(n *CatalogNode) bool {
+		names = append(names, n.Name)
+		return n.Name != "Kazakhstan"
+	})
+	assert.Equal(t, []string{"Russia", "Tatarstan", "Kazan", "Naberezhnye Chelny", "Moscow Oblast", "Khimki", "Kazakhstan"}, names)
+
+	t.Run("items keep the catalog rows", func(t *testing.T) {
+		_, err := tree.Add("Russia", "Tatarstan", "Yelabuga")
+		require.NoError(t, err)
+
+		items := tree.Items()
+		require.Len(t, items, 6)
+		assert.Equal(t, []string{"Russia", "", "", "RU"}, items[0].Values)
+		assert.Equal(t, []string{"Russia", "Tatarstan", "Yelabuga", ""}, items[3].Values)
+		assert.NoError(t, ValidateCatalog(tree.Headers(), items))
+	})
+
+	t.Run("new catalog", func(t *testing.T) {
+		tree, err := NewEmptyCatalogTree("Department", "Team")
+		require.NoError(t, err)
+		for _, path := range [][]string{{"Sales", "B2B"}, {"Sales", "B2C"}, {"Support"}} {
+			_, err := tree.Add(path...)
+			require.NoError(t, err)
+		}
+
+		items := tree.Items()
+		require.Len(t, items, 3)
+		assert.Equal(t, []string{"Sales", "B2B"}, items[0].Values)
+		assert.Equal(t, []string{"Support", ""}, items[2].Values)
+		_, err = tree.Add("Sales", "B2B", "Moscow")
+		assert.EqualError(t, err, "path must have from 1 to 2 names, got 3")
+		_, err = tree.Add()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid catalogs", func(t *testing.T) {
+		_, err := NewCatalogTree(catalog, "Street")
+		assert.Error(t, err)
+
+		_, err = NewCatalogTree(&CatalogResponse{
+			CatalogHeaders: []*CatalogHeader{{Name: "Country"}},
+			Items:          []*CatalogItem{{ItemID: 1, Values: []string{"Russia"}}, {ItemID: 2, Values: []string{"Russia"}}},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package pyrus
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// CommentViaEmail comments a task and sends the comment as an outbound email to the specified address.
+func (c *Client) CommentViaEmail(taskID int, toEmail, text string, attachments []*Attachment) (*TaskResponse, error) {
+	if err := validation.Validate(toEmail, validation.Required, is.EmailFormat); err != nil {
+		return nil, err
+	}
+
+	return c.CommentTask(taskID, &TaskCommentRequest{
+		Text:        text,
+		Attachments: attachments,
+		Channel: &Channel{
+			Type: ChannelTypeEmail,
+			To: &ChannelUser{
+				Email: toEmail,
+			},
+		},
+	})
+}
+
+// LastClientChannel returns the channel of the most recent comment received from an external channel
+// (email, messenger, web widget, etc.) or nil if the task has no such comments.
+// Pass channel types to look only for comments from these channels.
+func (t *TaskWithComments) LastClientChannel(types ...ChannelType) *Channel {
+	for i := len(t.Comments) - 1; i >= 0; i-- {
+		ch := t.Comments[i].Channel
+		if ch == nil || ch.From == nil {
+			continue
+		}
+		if len(types) == 0 {
+			return ch
+		}
+		for _, typ := range types {
+			if ch.Type == typ {
+				return ch
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReplyToClient comments a task and sends the comment back to the channel the client used most recently.
+func (c *Client) ReplyToClient(taskID int, text string, attachments []*Attachment) (*TaskResponse, error) {
+	return c.replyViaChannel(taskID, "", text, attachments)
+}
+
+// CommentViaTelegram comments a task and sends the comment to the Telegram chat attached to the task.
+func (c *Client) CommentViaTelegram(taskID int, text string, attachments []*Attachment) (*TaskResponse, error) {
+	return c.replyViaChannel(taskID, ChannelTypeTelegram, text, attachments)
+}
+
+// CommentViaVK comments a task and sends the comment to the VK chat attached to the task.
+func (c *Client) CommentViaVK(taskID int, text string, attachments []*Attachment) (*TaskResponse, error) {
+	return c.replyViaChannel(taskID, ChannelTypeVK, text, attachments)
+}
+
+// CommentViaViber comments a task and sends the comment to the Viber chat attached to the task.
+func (c *Client) CommentViaViber(taskID int, text string, attachments []*Attachment) (*TaskResponse, error) {
+	return c.replyViaChannel(taskID, ChannelTypeViber, text, attachments)
+}
+
+// CommentViaWebWidget comments a task and sends the comment to the web widget chat attached to the task.
+func (c *Client) CommentViaWebWidget(taskID int, text string, attachments []*Attachment) (*TaskResponse, error) {
+	return c.replyViaChannel(taskID, ChannelTypeWebWidget, text, attachments)
+}
+
+// replyViaChannel finds the latest inbound comment of the specified channel type (any type if empty)
+// and mirrors its channel in the new comment.
+func (c *Client) replyViaChannel(taskID int, channelType ChannelType, text string, attachments []*Attachment) (*TaskResponse, error) {
+	task, err := c.Task(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inbound *Channel
+	if task.Task != nil {
+		if channelType == "" {
+			inbound = task.Task.LastClientChannel()
+		} else {
+			inbound = task.Task.LastClientChannel(channelType)
+		}
+	}
+	if inbound == nil {
+		if channelType == "" {
+			return nil, errors.New("task has no comments from external channels")
+		}
+		return nil, errors.New("task has no comments from " + string(channelType) + " channel")
+	}
+
+	return c.CommentTask(taskID, &TaskCommentRequest{
+		Text:        text,
+		Attachments: attachments,
+		Channel: &Channel{
+			Type: inbound.Type,
+			To:   inbound.From,
+		},
+	})
+}
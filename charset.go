@@ -0,0 +1,106 @@
+package pyrus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type responseCharsetKey struct{}
+
+// windows1251 maps bytes 0x80-0xBF of windows-1251 to runes, bytes 0xC0-0xFF are А-я.
+var windows1251 = [64]rune{
+	0x0402, 0x0403, 0x201A, 0x0453, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x20AC, 0x2030, 0x0409, 0x2039, 0x040A, 0x040C, 0x040B, 0x040F,
+	0x0452, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0xFFFD, 0x2122, 0x0459, 0x203A, 0x045A, 0x045C, 0x045B, 0x045F,
+	0x00A0, 0x040E, 0x045E, 0x0408, 0x00A4, 0x0490, 0x00A6, 0x00A7,
+	0x0401, 0x00A9, 0x0404, 0x00AB, 0x00AC, 0x00AD, 0x00AE, 0x0407,
+	0x00B0, 0x00B1, 0x0406, 0x0456, 0x0491, 0x00B5, 0x00B6, 0x00B7,
+	0x0451, 0x2116, 0x0454, 0x00BB, 0x0458, 0x0405, 0x0455, 0x0457,
+}
+
+// DecodeCSV converts CSV registry in the encoding passed to RegistryRequest to UTF-8.
+// Registry does it automatically, so it's only needed for CSV obtained elsewhere.
+func DecodeCSV(data []byte, encoding RegistryEncoding) (string, error) {
+	if err := validateCSVEncoding(encoding); err != nil {
+		return "", err
+	}
+	if !isWindows1251(encoding) {
+		return string(data), nil
+	}
+
+	b, err := io.ReadAll(&windows1251Reader{r: bytes.NewReader(data)})
+	return string(b), err
+}
+
+func validateCSVEncoding(encoding RegistryEncoding) error {
+	switch RegistryEncoding(strings.ToLower(string(encoding))) {
+	case "", RegistryEncodingUTF8, RegistryEncodingWindows1251:
+		return nil
+	default:
+		return fmt.Errorf("unsupported encoding %q, use %s or %s", encoding, RegistryEncodingUTF8, RegistryEncodingWindows1251)
+	}
+}
+
+func isWindows1251(encoding RegistryEncoding) bool {
+	return strings.EqualFold(string(encoding), string(RegistryEncodingWindows1251))
+}
+
+// contextWithResponseCharset makes doRequest transcode the response body from the encoding to UTF-8.
+func contextWithResponseCharset(ctx context.Context, encoding RegistryEncoding) context.Context {
+	if !isWindows1251(encoding) {
+		return ctx
+	}
+
+	return context.WithValue(ctx, responseCharsetKey{}, encoding)
+}
+
+// responseCharsetReader wraps the body into the decoder of the context charset.
+func responseCharsetReader(ctx context.Context, body io.Reader) io.Reader {
+	if encoding, ok := ctx.Value(responseCharsetKey{}).(RegistryEncoding); ok && isWindows1251(encoding) {
+		return &windows1251Reader{r: body}
+	}
+
+	return body
+}
+
+// windows1251Reader decodes windows-1251 text into UTF-8. ASCII is kept as is, so JSON syntax survives decoding.
+type windows1251Reader struct {
+	r       io.Reader
+	pending []byte
+	err     error
+}
+
+func (r *windows1251Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		buf := make([]byte, len(p)/utf8.UTFMax+1)
+		n, err := r.r.Read(buf)
+		r.err = err
+		var encoded [utf8.UTFMax]byte
+		for _, b := range buf[:n] {
+			switch {
+			case b < 0x80:
+				r.pending = append(r.pending, b)
+			case b < 0xC0:
+				size := utf8.EncodeRune(encoded[:], windows1251[b-0x80])
+				r.pending = append(r.pending, encoded[:size]...)
+			default:
+				size := utf8.EncodeRune(encoded[:], rune(b-0xC0)+0x0410)
+				r.pending = append(r.pending, encoded[:size]...)
+			}
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
@@ -0,0 +1,49 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cp1251Text is "Ёж №1;«Привет»" in windows-1251.
+var cp1251Text = []byte{0xA8, 0xE6, 0x20, 0xB9, 0x31, 0x3B, 0xAB, 0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2, 0xBB}
+
+func TestDecodeCSV(t *testing.T) {
+	s, err := DecodeCSV(cp1251Text, "Windows-1251")
+	require.NoError(t, err)
+	assert.Equal(t, "Ёж №1;«Привет»", s)
+
+	s, err = DecodeCSV([]byte("Ёж"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "Ёж", s)
+
+	_, err = DecodeCSV(cp1251Text, "koi8-r")
+	assert.Error(t, err)
+}
+
+func TestClient_Registry_windows1251(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/auth") {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+		w.Write(append(append([]byte(`{"csv":"`), cp1251Text...), `"}`...)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	resp, err := c.Registry(1, &RegistryRequest{Format: RegistryFormatCSV, Encoding: RegistryEncodingWindows1251})
+	require.NoError(t, err)
+	assert.Equal(t, "Ёж №1;«Привет»", resp.CSV)
+
+	_, err = c.Registry(1, &RegistryRequest{Format: RegistryFormatCSV, Encoding: "cp866"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package pyrus
+
+import "time"
+
+// Clock is a source of time used by the client for cache expiration, quota timestamps, request durations,
+// webhook waiting and SLA checks. Replace it with WithClock to control time in tests instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithClock allows to override the system clock, nil is rejected by NewClient.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clientClock returns the clock of the client if it supports it and the system clock otherwise.
+func clientClock(client IClient) Clock {
+	if c, ok := client.(*Client); ok && c.clock != nil {
+		return c.clock
+	}
+
+	return systemClock{}
+}
@@ -0,0 +1,78 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestWithClock(t *testing.T) {
+	var revalidations int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidations++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"forms":[]}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	clock := &fakeClock{now: time.Date(2021, 7, 21, 12, 0, 0, 0, time.UTC)}
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithClock(clock),
+		WithResponseCache(NewMemoryResponseCache(), time.Hour))
+	require.NoError(t, err)
+
+	_, err = c.Forms()
+	require.NoError(t, err)
+	_, err = c.Forms()
+	require.NoError(t, err)
+	assert.Equal(t, 0, revalidations)
+
+	clock.Advance(2 * time.Hour)
+	_, err = c.Forms()
+	require.NoError(t, err)
+	assert.Equal(t, 1, revalidations)
+
+	assert.Equal(t, clock.Now(), NewSLAMonitor(c).now())
+}
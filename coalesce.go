@@ -0,0 +1,91 @@
+package pyrus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// WithRequestCoalescing allows to coalesce concurrent identical GET requests, e.g. of the same form
+// or catalog during a burst of webhooks, into a single HTTP call. Every caller gets its own copy
+// of the decoded response. Callers share the result of the first request, including an error
+// caused by cancellation of its context.
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.inflight = &requestGroup{}
+	}
+}
+
+// requestGroup tracks GET requests in flight by the method, URL and query.
+type requestGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightRequest
+}
+
+type inflightRequest struct {
+	done    chan struct{}
+	body    json.RawMessage
+	err     error
+	waiters int
+}
+
+// do calls fn unless the request with the key is already in flight and waits for its result otherwise.
+func (g *requestGroup) do(ctx context.Context, key string, fn func() (json.RawMessage, error)) (json.RawMessage, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.waiters++
+		g.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.body, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call := &inflightRequest{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = make(map[string]*inflightRequest)
+	}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.body, call.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+
+	return call.body, call.err
+}
+
+// coalescable reports whether the request could share the response with identical concurrent requests.
+// Downloads are excluded, since they are written to the caller's writer.
+func (c *Client) coalescable(method string, reqBody, respBody interface{}) bool {
+	return c.inflight != nil && method == http.MethodGet && reqBody == nil && respBody != nil
+}
+
+// performCoalesced performs GET request once for all concurrent callers and decodes the response for each of them.
+func (c *Client) performCoalesced(ctx context.Context, method, path string, q *url.Values, respBody interface{}) error {
+	key := method + " " + c.requestBaseURLFor(ctx, path) + path
+	if q != nil {
+		key += "?" + q.Encode()
+	}
+
+	body, err := c.inflight.do(ctx, key, func() (json.RawMessage, error) {
+		var body json.RawMessage
+		if err := c.performAttempt(ctx, method, path, q, nil, &body, 1); err != nil {
+			return nil, err
+		}
+		return body, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.decodeCached(body, respBody)
+}
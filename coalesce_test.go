@@ -0,0 +1,81 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestCoalescing(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms/42":
+			atomic.AddInt32(&requests, 1)
+			<-release
+			w.Write([]byte(`{"id":42,"name":"Form"}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithRequestCoalescing())
+	require.NoError(t, err)
+	// Token is fetched in advance, so callers don't race for it
+	_, err = c.Lists()
+	require.Error(t, err)
+
+	const callers = 5
+	var (
+		wg    sync.WaitGroup
+		forms = make([]*FormResponse, callers)
+		errs  = make([]error, callers)
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			forms[i], errs[i] = c.Form(42)
+		}(i)
+	}
+
+	// Wait until the rest of callers join the first request
+	for {
+		c.inflight.mu.Lock()
+		call := c.inflight.calls["GET "+ts.URL+"/forms/42"]
+		joined := call != nil && call.waiters == callers-1
+		c.inflight.mu.Unlock()
+		if joined {
+			break
+		}
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, 42, forms[i].ID)
+		assert.Equal(t, "Form", forms[i].Name)
+	}
+	// Every caller gets its own copy
+	assert.NotSame(t, forms[0], forms[1])
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	t.Run("errors are returned", func(t *testing.T) {
+		_, err := c.Task(1)
+		assert.Error(t, err)
+	})
+}
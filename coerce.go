@@ -0,0 +1,124 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoerceFieldValue converts natural Go values to the format accepted by API for the field type.
+// It's applied automatically when FormField with Type is encoded, e.g. in FieldUpdates:
+//
+//	date, due_date, creation_date  time.Time, *time.Time or "2006-01-02" string
+//	time                           time.Time, *time.Time or "15:04" string
+//	due_date_time                  time.Time or *time.Time, encoded in UTC
+//	number, money                  any integer or float type or numeric string
+//	checkmark                      bool or CheckmarkType
+//	person, author                 Person, *Person, int id or email string
+//	catalog                        CatalogItem, *CatalogItem, int item id or []int item ids
+//
+// Values of other types and fields are returned as is, as well as raw values kept by lazy decoding.
+func CoerceFieldValue(fieldType FieldType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// Raw value came from the API, so it's already in its format
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw, nil
+	}
+
+	switch fieldType {
+	case FieldTypeDate, FieldTypeDueDate, FieldTypeCreationDate:
+		return coerceTime(fieldType, v, "2006-01-02")
+	case FieldTypeTime:
+		return coerceTime(fieldType, v, "15:04")
+	case FieldTypeDueDateTime:
+		return coerceTime(fieldType, v, time.RFC3339)
+	case FieldTypeNumber, FieldTypeMoney:
+		return coerceNumber(v)
+	case FieldTypeCheckmark:
+		switch c := v.(type) {
+		case bool:
+			if c {
+				return CheckmarkTypeChecked, nil
+			}
+			return CheckmarkTypeUnchecked, nil
+		case CheckmarkType:
+			return coerceCheckmark(string(c))
+		case string:
+			return coerceCheckmark(c)
+		}
+	case FieldTypePerson, FieldTypeAuthor:
+		switch p := v.(type) {
+		case Person:
+			return &p, nil
+		case int:
+			return &Person{ID: p}, nil
+		case string:
+			if !strings.Contains(p, "@") {
+				return nil, fmt.Errorf("person email was expected, got %q", p)
+			}
+			return &Person{Email: p}, nil
+		}
+	case FieldTypeCatalog:
+		switch c := v.(type) {
+		case CatalogItem:
+			return &c, nil
+		case int:
+			return &CatalogItem{ItemID: c}, nil
+		case []int:
+			return &CatalogItem{ItemIDs: c}, nil
+		}
+	}
+
+	return v, nil
+}
+
+func coerceTime(fieldType FieldType, v interface{}, layout string) (interface{}, error) {
+	switch t := v.(type) {
+	case time.Time:
+		if fieldType == FieldTypeDueDateTime {
+			t = t.UTC()
+		}
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil {
+			return nil, nil
+		}
+		return coerceTime(fieldType, *t, layout)
+	case string:
+		if _, err := time.Parse(layout, t); err != nil {
+			return nil, fmt.Errorf("value %q doesn't match the layout %q", t, layout)
+		}
+		return t, nil
+	}
+
+	return v, nil
+}
+
+func coerceNumber(v interface{}) (interface{}, error) {
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number was expected, got %q", n)
+		}
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("number was expected, got %T", v)
+}
+
+func coerceCheckmark(s string) (interface{}, error) {
+	switch CheckmarkType(s) {
+	case CheckmarkTypeChecked, CheckmarkTypeUnchecked:
+		return CheckmarkType(s), nil
+	}
+
+	return nil, errors.New("checkmark must be checked or unchecked")
+}
@@ -0,0 +1,61 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceFieldValue(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	due := time.Date(2021, 8, 1, 21, 20, 0, 0, msk)
+
+	b, err := json.Marshal([]*FormField{
+		{ID: 1, Type: FieldTypeDate, Value: due},
+		{ID: 2, Type: FieldTypeDueDateTime, Value: &due},
+		{ID: 3, Type: FieldTypeTime, Value: due},
+		{ID: 4, Type: FieldTypeNumber, Value: "12.5"},
+		{ID: 5, Type: FieldTypeMoney, Value: 100},
+		{ID: 6, Type: FieldTypeCheckmark, Value: true},
+		{ID: 7, Type: FieldTypePerson, Value: 123456},
+		{ID: 8, Type: FieldTypePerson, Value: "ivanov@example.org"},
+		{ID: 9, Type: FieldTypeCatalog, Value: []int{1, 2}},
+		{ID: 10, Type: FieldTypeText, Value: "Пример"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"id":1,"type":"date","value":"2021-08-01"},
+		{"id":2,"type":"due_date_time","value":"2021-08-01T18:20:00Z"},
+		{"id":3,"type":"time","value":"21:20"},
+		{"id":4,"type":"number","value":12.5},
+		{"id":5,"type":"money","value":100},
+		{"id":6,"type":"checkmark","value":"checked"},
+		{"id":7,"type":"person","value":{"id":123456}},
+		{"id":8,"type":"person","value":{"email":"ivanov@example.org"}},
+		{"id":9,"type":"catalog","value":{"item_ids":[1,2]}},
+		{"id":10,"type":"text","value":"Пример"}
+	]`, string(b))
+
+	// Empty checkmark returned by API is encoded back as is, but isn't valid for requests
+	var fields []*FormField
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id":1,"type":"checkmark","value":""},
+		{"id":2,"type":"checkmark","value":null}
+	]`), &fields))
+	b, err = json.Marshal(fields)
+	require.NoError(t, err)
+	var decoded []*FormField
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, fields, decoded)
+	assert.Error(t, fields[0].Validate())
+
+	assert.Error(t, FormField{ID: 1, Type: FieldTypeNumber, Value: "много"}.Validate())
+	assert.Error(t, FormField{ID: 1, Type: FieldTypeDate, Value: "01.08.2021"}.Validate())
+	assert.Error(t, FormField{ID: 1, Type: FieldTypeCheckmark, Value: "yes"}.Validate())
+	assert.Error(t, FormField{ID: 1, Type: FieldTypePerson, Value: "Иванов"}.Validate())
+	assert.NoError(t, FormField{ID: 1, Type: FieldTypeDate, Value: "2021-08-01"}.Validate())
+	assert.NoError(t, FormField{ID: 1, Value: "без типа"}.Validate())
+}
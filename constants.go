@@ -100,6 +100,26 @@ const (
 	StatusTypeClosed StatusType = "closed"
 )
 
+// SLAAlertType is a type of alert emitted by SLAMonitor.
+type SLAAlertType string
+
+const (
+	SLAAlertTypeDueSoon SLAAlertType = "due_soon"
+	SLAAlertTypeOverdue SLAAlertType = "overdue"
+	SLAAlertTypeStuck   SLAAlertType = "stuck"
+)
+
+// PingStatus is a result of Ping classified for health checks.
+type PingStatus string
+
+const (
+	PingStatusOK             PingStatus = "ok"
+	PingStatusAuthFailure    PingStatus = "auth_failure"
+	PingStatusNetworkFailure PingStatus = "network_failure"
+	PingStatusRateLimited    PingStatus = "rate_limited"
+	PingStatusFailure        PingStatus = "failure"
+)
+
 // CatalogHeaderType is a type of CatalogHeader
 type CatalogHeaderType string
 
@@ -130,8 +150,119 @@ const (
 )
 
 // CallEventType is a type of call event. Only relevant for calls API.
+// Integrations could send their own event types simply converting them: CallEventType("custom").
 type CallEventType string
 
 const (
+	// CallEventTypeShow shows the call card to the user with the specified extension.
 	CallEventTypeShow CallEventType = "show"
+	// CallEventTypeHide hides the call card from the user with the specified extension.
+	CallEventTypeHide CallEventType = "hide"
+	// CallEventTypeRinging means that the phone of the user with the specified extension is ringing.
+	CallEventTypeRinging CallEventType = "ringing"
+	// CallEventTypeAnswered means that the user with the specified extension answered the call.
+	CallEventTypeAnswered CallEventType = "answered"
+	// CallEventTypeFinished means that the call has been finished.
+	CallEventTypeFinished CallEventType = "finished"
+)
+
+// EventSubject is a subject of webhook Event.
+type EventSubject string
+
+const (
+	EventSubjectTask         EventSubject = "task"
+	EventSubjectAnnouncement EventSubject = "announcement"
+)
+
+// TransportProfile is a preset of HTTP transport settings used by WithTransportProfile.
+type TransportProfile string
+
+const (
+	// TransportProfileHighThroughput keeps a large pool of idle connections for bulk jobs doing many parallel requests.
+	TransportProfileHighThroughput TransportProfile = "high-throughput"
+	// TransportProfileLowLatency uses short dial and response timeouts for interactive bots, so hung connections fail fast.
+	TransportProfileLowLatency TransportProfile = "low-latency"
+)
+
+// EventDropReason explains why a webhook Event has been dropped.
+type EventDropReason string
+
+const (
+	// EventDropReasonBufferFull means that Event chan was full and the consumer didn't catch up in time.
+	EventDropReasonBufferFull EventDropReason = "buffer_full"
+	// EventDropReasonClosed means that the event has been received after Client.Close.
+	EventDropReasonClosed EventDropReason = "closed"
+)
+
+// ValidationMode controls client-side validation of requests, see WithValidation.
+type ValidationMode string
+
+const (
+	// ValidationEnabled validates requests before sending them. It's the default mode.
+	ValidationEnabled ValidationMode = "enabled"
+	// ValidationDisabled sends requests as is and lets Pyrus validate them.
+	ValidationDisabled ValidationMode = "disabled"
+)
+
+// RegistryFormat is a format of registry returned by Registry method. JSON is used by default.
+type RegistryFormat string
+
+const (
+	RegistryFormatCSV RegistryFormat = "csv"
+)
+
+// RegistryDelimiter is a delimiter of CSV registry. Comma is used by default.
+type RegistryDelimiter string
+
+const (
+	RegistryDelimiterComma     RegistryDelimiter = ","
+	RegistryDelimiterSemicolon RegistryDelimiter = ";"
+	RegistryDelimiterTab       RegistryDelimiter = "\t"
+)
+
+// RegistryEncoding is an encoding of CSV registry. UTF-8 is used by default.
+type RegistryEncoding string
+
+const (
+	RegistryEncodingUTF8        RegistryEncoding = "utf-8"
+	RegistryEncodingWindows1251 RegistryEncoding = "windows-1251"
+)
+
+// CacheClass is a class of reference data endpoints which responses could be cached.
+type CacheClass string
+
+const (
+	CacheClassForms    CacheClass = "forms"
+	CacheClassCatalogs CacheClass = "catalogs"
+	CacheClassContacts CacheClass = "contacts"
+	CacheClassMembers  CacheClass = "members"
+	CacheClassRoles    CacheClass = "roles"
+)
+
+// WebhookSchemaMode is a mode of webhook payload validation, see WithWebhookSchemaValidation.
+type WebhookSchemaMode string
+
+const (
+	// WebhookSchemaOff disables validation, it's the default.
+	WebhookSchemaOff WebhookSchemaMode = "off"
+	// WebhookSchemaFlag passes invalid events to handlers with SchemaError set.
+	WebhookSchemaFlag WebhookSchemaMode = "flag"
+	// WebhookSchemaReject rejects invalid events with 400.
+	WebhookSchemaReject WebhookSchemaMode = "reject"
+)
+
+// RegistryWindowField is a date used by RegistryExporter to split the registry into time windows.
+type RegistryWindowField string
+
+const (
+	RegistryWindowCreated  RegistryWindowField = "created"
+	RegistryWindowModified RegistryWindowField = "modified"
+)
+
+// AttachmentUse is a purpose of the uploaded file checked by DetectAttachment.
+type AttachmentUse string
+
+const (
+	AttachmentUseTask          AttachmentUse = "task"
+	AttachmentUseCallRecording AttachmentUse = "call_recording"
 )
@@ -0,0 +1,169 @@
+package pyrus
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RegistryRecord is a row of the registry requested in CSV format.
+type RegistryRecord struct {
+	TaskID int
+	// Values contains typed values of the form fields by field id: float64 for numbers and money,
+	// time.Time for dates and times, CheckmarkType for checkmarks and strings for other fields.
+	Values map[int]interface{}
+	// Extra contains raw values of columns which don't match any form field by header.
+	Extra map[string]string
+}
+
+// Value returns a value of the form field found by id, name or code.
+func (r *RegistryRecord) Value(form *FormResponse, key string) (interface{}, bool) {
+	f := form.findDefinitionField(key, nil)
+	if f == nil {
+		return nil, false
+	}
+
+	v, ok := r.Values[f.ID]
+	return v, ok
+}
+
+// taskIDHeaders are headers of the task id column in CSV registry, compared case-insensitively.
+var taskIDHeaders = map[string]struct{}{
+	"id":           {},
+	"task_id":      {},
+	"task id":      {},
+	"id задачи":    {},
+	"номер задачи": {},
+}
+
+// ParseCSV parses CSV registry returned for RegistryRequest with "csv" format.
+// Columns are mapped to the form fields by header, which is a field name or code, and values are converted
+// according to field types. Delimiter is the one passed in RegistryRequest, comma is used if it's empty.
+func (r *FormRegisterResponse) ParseCSV(form *FormResponse, delimiter RegistryDelimiter) ([]*RegistryRecord, error) {
+	return ParseRegistryCSV(r.CSV, form, delimiter)
+}
+
+// ParseRegistryCSV parses CSV registry, see FormRegisterResponse.ParseCSV.
+func ParseRegistryCSV(data string, form *FormResponse, delimiter RegistryDelimiter) ([]*RegistryRecord, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if delimiter != "" {
+		d, size := utf8.DecodeRuneInString(string(delimiter))
+		if size != len(delimiter) {
+			return nil, fmt.Errorf("invalid delimiter %q", delimiter)
+		}
+		reader.Comma = d
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	taskIDColumn := -1
+	fields := make([]*FormField, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if _, ok := taskIDHeaders[strings.ToLower(h)]; ok && taskIDColumn < 0 {
+			taskIDColumn = i
+			continue
+		}
+		fields[i] = form.findDefinitionField(h, nil)
+	}
+
+	records := make([]*RegistryRecord, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		record := &RegistryRecord{
+			Values: make(map[int]interface{}),
+			Extra:  make(map[string]string),
+		}
+
+		for i, raw := range row {
+			if i >= len(header) {
+				break
+			}
+
+			switch {
+			case i == taskIDColumn:
+				id, err := strconv.Atoi(strings.TrimSpace(raw))
+				if err != nil {
+					return nil, fmt.Errorf("row %d: invalid task id %q", n+1, raw)
+				}
+				record.TaskID = id
+			case fields[i] != nil:
+				v, err := parseCSVValue(fields[i].Type, raw)
+				if err != nil {
+					return nil, fmt.Errorf("row %d, column %q: %w", n+1, header[i], err)
+				}
+				if v != nil {
+					record.Values[fields[i].ID] = v
+				}
+			default:
+				record.Extra[header[i]] = raw
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+var (
+	csvDateLayouts     = []string{"2006-01-02", "02.01.2006", "2006-01-02T15:04:05Z07:00"}
+	csvDateTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "02.01.2006 15:04:05", "02.01.2006 15:04"}
+	csvTimeLayouts     = []string{"15:04", "15:04:05"}
+)
+
+// parseCSVValue converts raw CSV value according to the field type. Empty values are returned as nil.
+func parseCSVValue(fieldType FieldType, raw string) (interface{}, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return nil, nil
+	}
+
+	switch fieldType {
+	case FieldTypeNumber, FieldTypeMoney:
+		s = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(s)
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("number was expected, got %q", raw)
+		}
+		return f, nil
+	case FieldTypeDate, FieldTypeDueDate, FieldTypeCreationDate:
+		return parseCSVTime(s, csvDateLayouts)
+	case FieldTypeDueDateTime:
+		return parseCSVTime(s, csvDateTimeLayouts)
+	case FieldTypeTime:
+		return parseCSVTime(s, csvTimeLayouts)
+	case FieldTypeCheckmark:
+		if b, err := strconv.ParseBool(s); err == nil {
+			if b {
+				return CheckmarkTypeChecked, nil
+			}
+			return CheckmarkTypeUnchecked, nil
+		}
+		return coerceCheckmark(strings.ToLower(s))
+	default:
+		return raw, nil
+	}
+}
+
+func parseCSVTime(s string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("unsupported date format " + strconv.Quote(s))
+}
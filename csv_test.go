@@ -0,0 +1,48 @@
+package pyrus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormRegisterResponse_ParseCSV(t *testing.T) {
+	form := &FormResponse{
+		Fields: []*FormField{
+			{ID: 1, Type: FieldTypeText, Name: "Описание"},
+			{ID: 2, Type: FieldTypeMoney, Name: "Сумма", Info: &FormFieldInfo{Code: "amount"}},
+			{ID: 3, Type: FieldTypeDate, Name: "Срок"},
+			{ID: 4, Type: FieldTypeCheckmark, Name: "Срочно"},
+		},
+	}
+
+	resp := &FormRegisterResponse{
+		CSV: "\ufeffID задачи;Описание;amount;Срок;Срочно;Автор\n" +
+			"10;\"Бумага; ручки\";1 234,50;21.07.2021;checked;Иванов\n" +
+			"11;;;2021-07-22;unchecked;Петров\n",
+	}
+
+	records, err := resp.ParseCSV(form, ";")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 10, records[0].TaskID)
+	assert.Equal(t, "Бумага; ручки", records[0].Values[1])
+	assert.Equal(t, 1234.5, records[0].Values[2])
+	assert.Equal(t, time.Date(2021, 7, 21, 0, 0, 0, 0, time.UTC), records[0].Values[3])
+	assert.Equal(t, CheckmarkTypeChecked, records[0].Values[4])
+	assert.Equal(t, map[string]string{"Автор": "Иванов"}, records[0].Extra)
+
+	v, ok := records[0].Value(form, "Сумма")
+	assert.True(t, ok)
+	assert.Equal(t, 1234.5, v)
+
+	_, ok = records[1].Value(form, "amount")
+	assert.False(t, ok)
+	assert.Equal(t, time.Date(2021, 7, 22, 0, 0, 0, 0, time.UTC), records[1].Values[3])
+
+	_, err = ParseRegistryCSV("Сумма\nмного\n", form, "")
+	assert.Error(t, err)
+}
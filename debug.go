@@ -0,0 +1,63 @@
+package pyrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync/atomic"
+)
+
+// WithDebugDump allows to write full HTTP requests and responses to w for troubleshooting.
+// Authorization header, security key, access tokens and webhook signatures are redacted.
+// Dumping could be toggled at runtime with SetDebugDump.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.debugDump = w
+		atomic.StoreInt32(&c.debugEnabled, 1)
+	}
+}
+
+// SetDebugDump enables or disables dumping of requests and responses configured by WithDebugDump.
+func (c *Client) SetDebugDump(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&c.debugEnabled, v)
+}
+
+func (c *Client) debugging() bool {
+	return c.debugDump != nil && atomic.LoadInt32(&c.debugEnabled) == 1
+}
+
+// dumpRequest writes the request to the debug writer. Body of the request is preserved.
+// Streamed bodies like file uploads are not dumped, since it would require reading them into memory.
+func (c *Client) dumpRequest(req *http.Request) {
+	b, err := httputil.DumpRequestOut(req, req.Body == nil || req.GetBody != nil)
+	if err != nil {
+		c.logger.Error("Error while dumping a request!", err)
+		return
+	}
+
+	c.writeDump(b)
+}
+
+// dumpResponse writes the response to the debug writer. Body of the response is preserved.
+func (c *Client) dumpResponse(resp *http.Response) {
+	b, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		c.logger.Error("Error while dumping a response!", err)
+		return
+	}
+
+	c.writeDump(b)
+}
+
+func (c *Client) writeDump(b []byte) {
+	c.debugMu.Lock()
+	defer c.debugMu.Unlock()
+
+	if _, err := io.WriteString(c.debugDump, c.redact(string(b))+"\n\n"); err != nil {
+		c.logger.Error("Error while writing a dump!", err)
+	}
+}
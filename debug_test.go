@@ -0,0 +1,45 @@
+package pyrus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDebugDump(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"secret-token"}`)) //nolint:errcheck
+		case "/forms/42":
+			w.Write([]byte(`{"id":42,"name":"Заявки"}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	dump := bytes.NewBuffer(nil)
+	c, err := NewClient("login", "secret-key", WithBaseURL(ts.URL), WithDebugDump(dump))
+	require.NoError(t, err)
+
+	form, err := c.Form(42)
+	require.NoError(t, err)
+	assert.Equal(t, "Заявки", form.Name)
+
+	out := dump.String()
+	assert.Contains(t, out, "GET /forms/42")
+	assert.Contains(t, out, `"name":"Заявки"`)
+	assert.Contains(t, out, "Authorization: [REDACTED]")
+	assert.NotContains(t, out, "secret-key")
+	assert.NotContains(t, out, "secret-token")
+
+	dump.Reset()
+	c.SetDebugDump(false)
+	_, err = c.Form(42)
+	require.NoError(t, err)
+	assert.Empty(t, dump.String())
+}
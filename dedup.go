@@ -0,0 +1,104 @@
+package pyrus
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// UploadIndex maps MD5 hashes and sizes of uploaded files to their GUIDs, see WithUploadIndex.
+// Implementations may expire entries, e.g. if GUIDs are kept by Pyrus for a limited time.
+type UploadIndex interface {
+	Get(key string) (guid string, ok bool)
+	Put(key, guid string)
+}
+
+// MemoryUploadIndex is an in-memory UploadIndex.
+type MemoryUploadIndex struct {
+	mu    sync.RWMutex
+	guids map[string]string
+}
+
+// NewMemoryUploadIndex returns an empty MemoryUploadIndex.
+func NewMemoryUploadIndex() *MemoryUploadIndex {
+	return &MemoryUploadIndex{
+		guids: make(map[string]string),
+	}
+}
+
+// Get returns GUID of the file.
+func (i *MemoryUploadIndex) Get(key string) (string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	guid, ok := i.guids[key]
+	return guid, ok
+}
+
+// Put saves GUID of the file.
+func (i *MemoryUploadIndex) Put(key, guid string) {
+	i.mu.Lock()
+	i.guids[key] = guid
+	i.mu.Unlock()
+}
+
+// WithUploadIndex allows to skip uploads of files which were already uploaded: UploadFile returns the GUID
+// saved in the index for the file with the same MD5 hash and size instead of sending it again.
+// Files are looked up only if the reader is io.ReadSeeker, since the hash has to be computed before the upload,
+// other files are hashed while they are sent and added to the index for the next uploads.
+func WithUploadIndex(index UploadIndex) Option {
+	return func(c *Client) {
+		c.uploadIndex = index
+	}
+}
+
+// uploadKey returns the key of the file in UploadIndex.
+func uploadKey(md5Hash string, size int64) string {
+	return strings.ToLower(md5Hash) + ":" + strconv.FormatInt(size, 10)
+}
+
+// hashSeeker computes MD5 hash and size of the rest of the reader and seeks back.
+func hashSeeker(r io.ReadSeeker) (string, int64, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+
+	h := md5.New() //nolint:gosec
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// hashingReader computes MD5 hash and size of the data read through it.
+type hashingReader struct {
+	r io.Reader
+	h hash.Hash
+	n int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, h: md5.New()} //nolint:gosec
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n]) //nolint:errcheck
+	r.n += int64(n)
+
+	return n, err
+}
+
+func (r *hashingReader) key() string {
+	return uploadKey(hex.EncodeToString(r.h.Sum(nil)), r.n)
+}
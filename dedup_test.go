@@ -0,0 +1,85 @@
+package pyrus
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithUploadIndex(t *testing.T) {
+	var uploads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		n := atomic.AddInt32(&uploads, 1)
+		f, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		b, err := io.ReadAll(f)
+		require.NoError(t, err)
+
+		sum := md5.Sum(b) //nolint:gosec
+		md5Hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		w.Write([]byte(`{"guid":"guid-` + strconv.Itoa(int(n)) + `","md5_hash":"` + md5Hash + `"}`)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	index := NewMemoryUploadIndex()
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithUploadIndex(index))
+	require.NoError(t, err)
+
+	t.Run("identical seekable file isn't sent again", func(t *testing.T) {
+		upload, err := c.UploadFile("a.txt", strings.NewReader("contract"))
+		require.NoError(t, err)
+		assert.Equal(t, "guid-1", upload.GUID)
+		assert.False(t, upload.Deduplicated)
+
+		upload, err = c.UploadFile("b.txt", strings.NewReader("contract"))
+		require.NoError(t, err)
+		assert.Equal(t, "guid-1", upload.GUID)
+		assert.True(t, upload.Deduplicated)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&uploads))
+	})
+
+	t.Run("other content is sent", func(t *testing.T) {
+		upload, err := c.UploadFile("a.txt", strings.NewReader("invoice"))
+		require.NoError(t, err)
+		assert.Equal(t, "guid-2", upload.GUID)
+	})
+
+	t.Run("streams are indexed while sent", func(t *testing.T) {
+		// bytes.Buffer isn't a seeker, so it's always sent
+		upload, err := c.UploadFile("c.txt", bytes.NewBufferString("report"))
+		require.NoError(t, err)
+		assert.Equal(t, "guid-3", upload.GUID)
+
+		upload, err = c.UploadFile("c.txt", bytes.NewReader([]byte("report")))
+		require.NoError(t, err)
+		assert.Equal(t, "guid-3", upload.GUID)
+		assert.True(t, upload.Deduplicated)
+	})
+
+	t.Run("seek position is kept", func(t *testing.T) {
+		r := strings.NewReader("xxcontract")
+		_, err := r.Seek(2, io.SeekStart)
+		require.NoError(t, err)
+
+		upload, err := c.UploadFile("a.txt", r)
+		require.NoError(t, err)
+		assert.Equal(t, "guid-1", upload.GUID)
+		assert.Equal(t, 8, r.Len())
+	})
+}
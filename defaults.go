@@ -0,0 +1,77 @@
+package pyrus
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FormDefaults contains default values of form fields by field id, name or code.
+// Form definitions returned by the API don't include default values configured in the form template,
+// so they have to be mirrored here to preview the result of TaskRequest.FillDefaults locally,
+// e.g. to show the final task in approval-preview UIs before its creation.
+type FormDefaults map[string]interface{}
+
+// Preview returns fields which would be filled with defaults for the request, i.e. fields without values in it.
+// Fields are returned in the order of the form definition with values coerced according to field types.
+// The request is not modified.
+func (d FormDefaults) Preview(form *FormResponse, req *TaskRequest) ([]*FormField, error) {
+	order := make(map[int]int)
+	for i, f := range definitionFields(form.Fields, func(*FormField) bool { return true }) {
+		if _, ok := order[f.ID]; !ok {
+			order[f.ID] = i
+		}
+	}
+
+	var fields []*FormField
+	for key, value := range d {
+		def := form.findDefinitionField(key, nil)
+		if def == nil {
+			return nil, fmt.Errorf("field %q doesn't exist in the form", key)
+		}
+		if requestHasValue(req, def) {
+			continue
+		}
+
+		v, err := CoerceFieldValue(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("default value of field %q: %w", key, err)
+		}
+
+		fields = append(fields, &FormField{
+			ID:    def.ID,
+			Type:  def.Type,
+			Name:  def.Name,
+			Value: v,
+		})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return order[fields[i].ID] < order[fields[j].ID]
+	})
+
+	return fields, nil
+}
+
+// Apply adds fields returned by Preview to the request and returns them.
+func (d FormDefaults) Apply(form *FormResponse, req *TaskRequest) ([]*FormField, error) {
+	fields, err := d.Preview(form, req)
+	if err != nil {
+		return nil, err
+	}
+	req.Fields = append(req.Fields, fields...)
+
+	return fields, nil
+}
+
+// requestHasValue reports whether the request sets a value of the field.
+func requestHasValue(req *TaskRequest, def *FormField) bool {
+	for _, f := range req.Fields {
+		if f == nil || f.Value == nil {
+			continue
+		}
+		if f.ID == def.ID || (f.ID == 0 && f.Name != "" && f.Name == def.Name) {
+			return true
+		}
+	}
+
+	return false
+}
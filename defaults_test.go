@@ -0,0 +1,42 @@
+package pyrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDefaults(t *testing.T) {
+	form := &FormResponse{
+		ID: 1,
+		Fields: []*FormField{
+			{ID: 1, Type: FieldTypeText, Name: "Описание"},
+			{ID: 2, Type: FieldTypeMoney, Name: "Сумма", Info: &FormFieldInfo{Code: "amount"}},
+			{ID: 3, Type: FieldTypeCheckmark, Name: "Срочно"},
+		},
+	}
+	defaults := FormDefaults{
+		"Срочно":   false,
+		"amount":   "100",
+		"Описание": "Без описания",
+	}
+
+	req := &TaskRequest{FormID: 1, Fields: []*FormField{{Name: "Описание", Value: "Бумага"}}}
+	fields, err := defaults.Preview(form, req)
+	require.NoError(t, err)
+	assert.Equal(t, []*FormField{
+		{ID: 2, Type: FieldTypeMoney, Name: "Сумма", Value: 100.0},
+		{ID: 3, Type: FieldTypeCheckmark, Name: "Срочно", Value: CheckmarkTypeUnchecked},
+	}, fields)
+	assert.Len(t, req.Fields, 1)
+
+	_, err = defaults.Apply(form, req)
+	require.NoError(t, err)
+	assert.Len(t, req.Fields, 3)
+
+	_, err = FormDefaults{"Нет": 1}.Preview(form, req)
+	assert.Error(t, err)
+	_, err = FormDefaults{"amount": "много"}.Preview(form, &TaskRequest{})
+	assert.Error(t, err)
+}
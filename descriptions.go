@@ -0,0 +1,140 @@
+package pyrus
+
+// Language is a language of error descriptions.
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageRussian Language = "ru"
+)
+
+// WithErrorLanguage allows to replace descriptions of API errors with descriptions in the language,
+// since Pyrus returns them in the language of the account. Original description is kept in Error.OriginalDescription.
+// Errors with unknown codes are left as is.
+func WithErrorLanguage(lang Language) Option {
+	return func(c *Client) {
+		c.errorLanguage = lang
+	}
+}
+
+// Description returns a description of the error code in the language or empty string for unknown codes.
+func (c ErrorCode) Description(lang Language) string {
+	d, ok := errorDescriptions[c]
+	if !ok {
+		return ""
+	}
+
+	switch lang {
+	case LanguageRussian:
+		return d.ru
+	default:
+		return d.en
+	}
+}
+
+// LocalizedDescription returns a description of the error in the language falling back to the description returned by API.
+func (e Error) LocalizedDescription(lang Language) string {
+	if d := e.Code.Description(lang); d != "" {
+		return d
+	}
+
+	return e.Description
+}
+
+// localize replaces the description with its translation.
+func (e *Error) localize(lang Language) {
+	d := e.Code.Description(lang)
+	if d == "" || d == e.Description {
+		return
+	}
+
+	e.OriginalDescription = e.Description
+	e.Description = d
+}
+
+type errorDescription struct {
+	en string
+	ru string
+}
+
+var errorDescriptions = map[ErrorCode]errorDescription{
+	ErrServerError:                       {"Internal server error", "Внутренняя ошибка сервера"},
+	ErrInvalidCredentials:                {"Invalid login or security key", "Неверный логин или секретный ключ"},
+	ErrTokenNotSpecified:                 {"Access token is not specified", "Не указан токен доступа"},
+	ErrRevokedToken:                      {"Access token has been revoked", "Токен доступа отозван"},
+	ErrExpiredToken:                      {"Access token has expired", "Срок действия токена доступа истёк"},
+	ErrInvalidToken:                      {"Access token is invalid", "Неверный токен доступа"},
+	ErrAuthorizationError:                {"Authorization error", "Ошибка авторизации"},
+	ErrAccountBlocked:                    {"User account is blocked", "Учётная запись пользователя заблокирована"},
+	ErrInvalidFieldID:                    {"Field with the specified id does not exist in the form", "Поле с указанным идентификатором не существует в форме"},
+	ErrDeletedField:                      {"Field with the specified id has been deleted from the form", "Поле с указанным идентификатором удалено из формы"},
+	ErrInvalidFieldName:                  {"Field with the specified name does not exist in the form", "Поле с указанным названием не существует в форме"},
+	ErrInvalidFieldIDName:                {"Field with the specified id and name does not exist or has been deleted", "Поле с указанными идентификатором и названием не существует или удалено"},
+	ErrNonUniqueName:                     {"Field name is not unique within the form", "Название поля не уникально в форме"},
+	ErrFieldIdentityMissing:              {"Field id or name is not specified", "Не указан идентификатор или название поля"},
+	ErrDuplicateField:                    {"The same field is modified multiple times", "Одно и то же поле изменяется несколько раз"},
+	ErrInvalidCatalogID:                  {"Catalog with the specified id does not exist", "Справочник с указанным идентификатором не существует"},
+	ErrInvalidCatalogItemName:            {"Catalog item with the specified name does not exist", "Элемент справочника с указанным названием не существует"},
+	ErrNonUniqueCatalogItemName:          {"There are multiple catalog items with the specified name", "В справочнике несколько элементов с указанным названием"},
+	ErrInvalidCatalogItemID:              {"Catalog item with the specified id does not exist", "Элемент справочника с указанным идентификатором не существует"},
+	ErrCatalogItemIDNameMismatch:         {"Catalog item with the specified id has another value", "Элемент справочника с указанным идентификатором имеет другое значение"},
+	ErrInvalidEmail:                      {"Person with the specified email does not exist", "Пользователь с указанным email не существует"},
+	ErrNonUniqueEmail:                    {"There are multiple persons with the specified email", "Несколько пользователей с указанным email"},
+	ErrInvalidPersonID:                   {"Person with the specified id was not found", "Пользователь с указанным идентификатором не найден"},
+	ErrInvalidPersonIDEmail:              {"Person with the specified id has another email", "У пользователя с указанным идентификатором другой email"},
+	ErrFormHasNoTask:                     {"Form has no task with the specified id", "В форме нет задачи с указанным идентификатором"},
+	ErrUnrecognizedAttachmentID:          {"Attachment with the specified id does not exist", "Вложение с указанным идентификатором не существует"},
+	ErrRequiredFieldMissing:              {"Required form field is missing", "Не заполнено обязательное поле формы"},
+	ErrTypeIsNotSupported:                {"Field type does not support writing of values", "Тип поля не поддерживает запись значений"},
+	ErrCatalogIdentityMissing:            {"Catalog item id is not specified", "Не указан идентификатор элемента справочника"},
+	ErrIncorrectParametersCount:          {"Incorrect parameters count for the filter operator", "Неверное количество параметров для оператора фильтра"},
+	ErrFilterTypeIsNotSupported:          {"Field type is not supported as a filter", "Тип поля не поддерживается в фильтре"},
+	ErrStepFieldDoesNotExists:            {"Form has no step fields", "В форме нет полей этапа"},
+	ErrCatalogItemIDMissing:              {"Catalog item id is not specified", "Не указан идентификатор элемента справочника"},
+	ErrPersonIdentityMissing:             {"Person id or email is not specified", "Не указан идентификатор или email пользователя"},
+	ErrEitherDueDateOrDueCanBeSet:        {"Either due_date or due can be set", "Можно указать только due_date или due"},
+	ErrNegativeDuration:                  {"Duration can not be negative", "Длительность не может быть отрицательной"},
+	ErrDurationIsTooLong:                 {"Duration can not be longer than a year", "Длительность не может превышать год"},
+	ErrDueMissing:                        {"Duration is specified without due", "Длительность указана без срока"},
+	ErrScheduledDateInPast:               {"Task can not be scheduled in the past", "Нельзя отложить задачу на дату в прошлом"},
+	ErrCannotAddFormProject:              {"Task can not be added to a form project", "Задачу нельзя добавить в проект формы"},
+	ErrFormTemplateCantBeRemovedFromTask: {"Form template list can not be removed from the task", "Список шаблона формы нельзя удалить из задачи"},
+	ErrNoFileInRequest:                   {"There are no files in the request", "В запросе нет файлов"},
+	ErrTooLargeRequestLength:             {"File exceeds the maximum allowed size", "Файл превышает максимально допустимый размер"},
+	ErrRequiredParameterMissing:          {"Required request parameter is missing", "Не указан обязательный параметр запроса"},
+	ErrTooManyTaskSteps:                  {"Maximum number of task steps exceeded", "Превышено максимальное количество этапов задачи"},
+	ErrInvalidValueFormat:                {"Value can not be converted to the field type", "Значение не может быть преобразовано к типу поля"},
+	ErrTooManyComments:                   {"Maximum number of task comments exceeded", "Превышено максимальное количество комментариев задачи"},
+	ErrInvalidStepNumber:                 {"Step number must be positive", "Номер этапа должен быть положительным"},
+	ErrTaskLimitExceeded:                 {"Maximum number of tasks for the organization exceeded", "Превышено максимальное количество задач организации"},
+	ErrFieldIsInTable:                    {"Field is a part of the table", "Поле является частью таблицы"},
+	ErrRequiredTableFieldMissing:         {"Required table field is missing", "Не заполнено обязательное поле таблицы"},
+	ErrDepartmentCatalogCanNotBeModified: {"Department catalog can not be modified", "Справочник подразделений нельзя изменить"},
+	ErrCatalogDuplicateRows:              {"Catalog contains duplicate rows", "Справочник содержит повторяющиеся строки"},
+	ErrEmptyCatalogHeaders:               {"Catalog headers can not be empty", "Заголовки справочника не могут быть пустыми"},
+	ErrCanNotModifyDeletedCatalog:        {"Deleted catalog can not be modified", "Удалённый справочник нельзя изменить"},
+	ErrCanNotModifyFirstColumn:           {"First column of the catalog can not be modified", "Первый столбец справочника нельзя изменить"},
+	ErrCatalogHeadersItemsMismatch:       {"Catalog headers and values mismatch", "Заголовки и значения справочника не совпадают"},
+	ErrTooManyCatalogItems:               {"Maximum number of catalog items exceeded", "Превышено максимальное количество элементов справочника"},
+	ErrCatalogItemMaxLengthExceeded:      {"Maximum length of catalog item exceeded", "Превышена максимальная длина элемента справочника"},
+	ErrCatalogDuplicateHeaders:           {"Catalog contains duplicate headers", "Справочник содержит повторяющиеся заголовки"},
+	ErrFormIDMissing:                     {"Form id is not specified", "Не указан идентификатор формы"},
+	ErrTextMissing:                       {"Text is not specified", "Не указан текст"},
+	ErrInvalidJSON:                       {"Request body is not a valid JSON", "Тело запроса не является корректным JSON"},
+	ErrEmptyBody:                         {"Request body can not be empty", "Тело запроса не может быть пустым"},
+	ErrAccessDeniedProject:               {"Access to the project is denied", "Доступ к проекту запрещён"},
+	ErrAccessDeniedTask:                  {"Access to the task is denied", "Доступ к задаче запрещён"},
+	ErrAccessDeniedCloseTask:             {"Not enough permissions to close the task", "Недостаточно прав для закрытия задачи"},
+	ErrAccessDeniedReopenTask:            {"Not enough permissions to reopen the task", "Недостаточно прав для возобновления задачи"},
+	ErrAccessDeniedCatalog:               {"Access to the catalog is denied", "Доступ к справочнику запрещён"},
+	ErrAccessDeniedForm:                  {"Access to the form is denied", "Доступ к форме запрещён"},
+	ErrAccessDeniedPerson:                {"Collaboration with the person is not allowed", "Нет доступа к взаимодействию с пользователем"},
+	ErrTooManyRequests:                   {"Requests limit reached, try again later", "Превышен лимит запросов, повторите позже"},
+	ErrEmptyFile:                         {"File is empty", "Файл пуст"},
+	ErrBadMultipartContent:               {"Bad multipart request body", "Некорректное multipart-тело запроса"},
+	ErrInvalidTableRow:                   {"Table row has been deleted or has not been created", "Строка таблицы удалена или не создана"},
+	ErrCannotAddExternalUser:             {"User from another organization can not be added", "Нельзя добавить пользователя из другой организации"},
+	ErrUnrecognizedIntegrationGUID:       {"Integration with the specified id does not exist", "Интеграция с указанным идентификатором не существует"},
+	ErrUnrecognizedCallGUID:              {"Call with the specified id does not exist", "Звонок с указанным идентификатором не существует"},
+	ErrUnsupportedAttachmentFormat:       {"Unsupported attachment format", "Неподдерживаемый формат вложения"},
+}
@@ -0,0 +1,174 @@
+package pyrus
+
+import "strings"
+
+// DirectoryEntry is a person of the contact directory together with the organization it belongs to.
+type DirectoryEntry struct {
+	Person           *Person
+	OrganizationID   int
+	OrganizationName string
+}
+
+// DirectoryRole is a role of the contact directory together with the organization it belongs to.
+type DirectoryRole struct {
+	Role           *Role
+	OrganizationID int
+}
+
+// ContactDirectory merges contacts of all organizations available to the account into a single directory,
+// so bots working for several organizations can look up persons and roles by id, email or name.
+type ContactDirectory struct {
+	organizations []*Organization
+	entries       []*DirectoryEntry
+	roles         []*DirectoryRole
+	names         map[string]int
+}
+
+// NewContactDirectory builds ContactDirectory from Contacts response.
+func NewContactDirectory(contacts *ContactsResponse) *ContactDirectory {
+	d := &ContactDirectory{
+		names: make(map[string]int),
+	}
+	if contacts == nil {
+		return d
+	}
+
+	for _, org := range contacts.Organizations {
+		if org == nil {
+			continue
+		}
+		d.organizations = append(d.organizations, org)
+
+		for _, p := range org.Persons {
+			if p == nil {
+				continue
+			}
+			d.entries = append(d.entries, &DirectoryEntry{
+				Person:           p,
+				OrganizationID:   org.ID,
+				OrganizationName: org.Name,
+			})
+		}
+		for _, r := range org.Roles {
+			if r == nil {
+				continue
+			}
+			d.roles = append(d.roles, &DirectoryRole{Role: r, OrganizationID: org.ID})
+		}
+	}
+
+	orgsByName := make(map[string]map[int]struct{})
+	for _, e := range d.entries {
+		name := strings.ToLower(mentionName(e.Person))
+		if orgsByName[name] == nil {
+			orgsByName[name] = make(map[int]struct{})
+		}
+		orgsByName[name][e.OrganizationID] = struct{}{}
+	}
+	for name, orgs := range orgsByName {
+		d.names[name] = len(orgs)
+	}
+
+	return d
+}
+
+// Directory fetches contacts and returns them as ContactDirectory.
+func (c *Client) Directory(includeInactive bool) (*ContactDirectory, error) {
+	contacts, err := c.Contacts(includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewContactDirectory(contacts), nil
+}
+
+// Organizations returns organizations of the directory.
+func (d *ContactDirectory) Organizations() []*Organization {
+	return d.organizations
+}
+
+// Entries returns persons of all organizations.
+func (d *ContactDirectory) Entries() []*DirectoryEntry {
+	return d.entries
+}
+
+// Organization returns the directory limited to the organization, all lookups of it are scoped by the organization.
+func (d *ContactDirectory) Organization(orgID int) *ContactDirectory {
+	scoped := &ContactDirectory{names: d.names}
+	for _, org := range d.organizations {
+		if org.ID == orgID {
+			scoped.organizations = append(scoped.organizations, org)
+		}
+	}
+	for _, e := range d.entries {
+		if e.OrganizationID == orgID {
+			scoped.entries = append(scoped.entries, e)
+		}
+	}
+	for _, r := range d.roles {
+		if r.OrganizationID == orgID {
+			scoped.roles = append(scoped.roles, r)
+		}
+	}
+
+	return scoped
+}
+
+// Person returns the person by id.
+func (d *ContactDirectory) Person(id int) (*DirectoryEntry, bool) {
+	for _, e := range d.entries {
+		if e.Person.ID == id {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+// Role returns the role by id.
+func (d *ContactDirectory) Role(id int) (*DirectoryRole, bool) {
+	for _, r := range d.roles {
+		if r.Role.ID == id {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// ByEmail returns persons with the email, case-insensitive. The same email could be used in several organizations.
+func (d *ContactDirectory) ByEmail(email string) []*DirectoryEntry {
+	var found []*DirectoryEntry
+	for _, e := range d.entries {
+		if e.Person.Email != "" && strings.EqualFold(e.Person.Email, email) {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
+
+// ByName returns persons with the full name "First Last", case-insensitive.
+func (d *ContactDirectory) ByName(name string) []*DirectoryEntry {
+	name = strings.TrimSpace(name)
+
+	var found []*DirectoryEntry
+	for _, e := range d.entries {
+		if strings.EqualFold(mentionName(e.Person), name) {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
+
+// DisplayName returns the full name of the person, followed by the organization name in parentheses
+// if persons with the same name exist in other organizations.
+func (d *ContactDirectory) DisplayName(e *DirectoryEntry) string {
+	name := mentionName(e.Person)
+	if d.names[strings.ToLower(name)] > 1 && e.OrganizationName != "" {
+		return name + " (" + e.OrganizationName + ")"
+	}
+
+	return name
+}
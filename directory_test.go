@@ -0,0 +1,62 @@
+package pyrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactDirectory(t *testing.T) {
+	d := NewContactDirectory(&ContactsResponse{Organizations: []*Organization{
+		{
+			ID:   1,
+			Name: "Головной офис",
+			Persons: []*Person{
+				{ID: 10, FirstName: "Иван", LastName: "Иванов", Email: "ivanov@example.org"},
+				{ID: 11, FirstName: "Пётр", LastName: "Петров", Email: "petrov@example.org"},
+			},
+			Roles: []*Role{{ID: 100, Name: "Бухгалтерия"}},
+		},
+		{
+			ID:   2,
+			Name: "Филиал",
+			Persons: []*Person{
+				{ID: 20, FirstName: "Иван", LastName: "Иванов", Email: "IVANOV@example.org"},
+			},
+			Roles: []*Role{{ID: 200, Name: "Бухгалтерия"}},
+		},
+	}})
+
+	assert.Len(t, d.Organizations(), 2)
+	assert.Len(t, d.Entries(), 3)
+
+	e, ok := d.Person(20)
+	require.True(t, ok)
+	assert.Equal(t, "Филиал", e.OrganizationName)
+	assert.Equal(t, "Иван Иванов (Филиал)", d.DisplayName(e))
+
+	e, ok = d.Person(11)
+	require.True(t, ok)
+	assert.Equal(t, "Пётр Петров", d.DisplayName(e))
+
+	assert.Len(t, d.ByEmail("ivanov@example.org"), 2)
+	assert.Len(t, d.ByName("иван иванов"), 2)
+
+	branch := d.Organization(2)
+	assert.Len(t, branch.Organizations(), 1)
+	found := branch.ByEmail("ivanov@example.org")
+	require.Len(t, found, 1)
+	assert.Equal(t, 20, found[0].Person.ID)
+	assert.Equal(t, "Иван Иванов (Филиал)", branch.DisplayName(found[0]))
+	_, ok = branch.Person(10)
+	assert.False(t, ok)
+
+	r, ok := branch.Role(200)
+	require.True(t, ok)
+	assert.Equal(t, 2, r.OrganizationID)
+	_, ok = branch.Role(100)
+	assert.False(t, ok)
+
+	assert.Empty(t, NewContactDirectory(nil).Entries())
+}
@@ -0,0 +1,70 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrEventBufferFull is returned to Pyrus when the event can't be put into the full Event chan.
+var ErrEventBufferFull = errors.New("event buffer is full")
+
+// DroppedEvents contains counters of webhook events dropped since the client was created.
+type DroppedEvents struct {
+	BufferFull uint64
+	Closed     uint64
+}
+
+// Total returns the number of dropped events.
+func (d DroppedEvents) Total() uint64 {
+	return d.BufferFull + d.Closed
+}
+
+// WithEventSendTimeout allows webhook handlers to drop events when Event chan stays full longer than d.
+// By default handlers wait for the consumer until the request is canceled.
+func WithEventSendTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.eventSendTimeout = d
+	}
+}
+
+// WithEventDroppedHandler allows to get notified about webhook events lost because of full Event chan or closed client.
+// Pyrus gets 503 for such events, so the handler is a good place to alert or to save them for manual replay.
+func WithEventDroppedHandler(fn func(e Event, reason EventDropReason)) Option {
+	return func(c *Client) {
+		c.onEventDropped = fn
+	}
+}
+
+// DroppedEvents returns counters of dropped webhook events.
+func (c *Client) DroppedEvents() DroppedEvents {
+	c.droppedMu.Lock()
+	defer c.droppedMu.Unlock()
+
+	return c.dropped
+}
+
+// dropEvent counts the dropped event and passes it to the handler.
+func (c *Client) dropEvent(event Event, reason EventDropReason) {
+	c.droppedMu.Lock()
+	switch reason {
+	case EventDropReasonBufferFull:
+		c.dropped.BufferFull++
+	case EventDropReasonClosed:
+		c.dropped.Closed++
+	}
+	c.droppedMu.Unlock()
+
+	if c.onEventDropped != nil {
+		c.onEventDropped(event, reason)
+	}
+}
+
+// eventSendContext limits waiting for a free slot in Event chan with WithEventSendTimeout.
+func (c *Client) eventSendContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.eventSendTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.eventSendTimeout)
+}
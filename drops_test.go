@@ -0,0 +1,47 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DroppedEvents(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	var reasons []EventDropReason
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey,
+		WithEventBufferSize(0),
+		WithEventSendTimeout(10*time.Millisecond),
+		WithEventDroppedHandler(func(e Event, reason EventDropReason) {
+			assert.NotZero(t, e.TaskID)
+			reasons = append(reasons, reason)
+		}),
+	)
+	require.NoError(t, err)
+
+	handler, _ := c.WebhookHandler()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	require.NoError(t, c.Close())
+	resp, err = http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Equal(t, []EventDropReason{EventDropReasonBufferFull, EventDropReasonClosed}, reasons)
+	assert.Equal(t, DroppedEvents{BufferFull: 1, Closed: 1}, c.DroppedEvents())
+	assert.EqualValues(t, 2, c.DroppedEvents().Total())
+}
@@ -1,8 +1,11 @@
 package pyrus
 
 import (
+	"bytes"
 	"encoding/json"
+	"strconv"
 	"time"
+	"unicode/utf8"
 )
 
 // FormField is a Form field. Forms consist of fields.
@@ -51,6 +54,11 @@ type ChoiceOption struct {
 
 // UnmarshalJSON is a custom unmarshaler to create a tree of form fields.
 func (f *FormField) UnmarshalJSON(b []byte) error {
+	return f.unmarshalJSON(b, false)
+}
+
+// unmarshalJSON decodes the field keeping its value as json.RawMessage if lazy is set.
+func (f *FormField) unmarshalJSON(b []byte, lazy bool) error {
 	type RawFormField FormField
 	raw := &struct {
 		Value json.RawMessage `json:"value"`
@@ -66,147 +74,217 @@ func (f *FormField) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	var err error
-	switch raw.Type {
+	if lazy {
+		f.Value = raw.Value
+		return nil
+	}
+
+	v, err := decodeFieldValue(raw.Type, raw.Value)
+	if err != nil {
+		return err
+	}
+	f.Value = v
+
+	return nil
+}
+
+// DecodedValue returns the field value decoding it first if it was kept as json.RawMessage by lazy decoding mode.
+// The field isn't modified, so it's safe for concurrent use, but the raw value is decoded on every call.
+func (f *FormField) DecodedValue() (interface{}, error) {
+	raw, ok := f.Value.(json.RawMessage)
+	if !ok {
+		return f.Value, nil
+	}
+
+	return decodeFieldValue(f.Type, raw)
+}
+
+// decodeFieldValue decodes the raw value according to the field type.
+func decodeFieldValue(fieldType FieldType, b json.RawMessage) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch fieldType {
 	case FieldTypeText:
-		var text string
-		err = json.Unmarshal(raw.Value, &text)
-		f.Value = text
+		v, err = decodeString(b)
 	case FieldTypeMoney:
-		var money float64
-		err = json.Unmarshal(raw.Value, &money)
-		f.Value = money
+		v, err = decodeFloat(b)
 	case FieldTypeNumber:
-		var number float64
-		err = json.Unmarshal(raw.Value, &number)
-		f.Value = number
+		v, err = decodeFloat(b)
 	case FieldTypeDate:
-		var dateStr string
-		if err := json.Unmarshal(raw.Value, &dateStr); err != nil {
-			return err
+		str, err := decodeString(b)
+		if err != nil {
+			return nil, err
 		}
 
-		date, err := time.Parse("2006-01-02", dateStr)
+		t, err := parseFieldTime(fieldType, str)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		f.Value = date
+		v = t
 	case FieldTypeTime:
-		var timeStr string
-		if err := json.Unmarshal(raw.Value, &timeStr); err != nil {
-			return err
+		str, err := decodeString(b)
+		if err != nil {
+			return nil, err
 		}
 
-		t, err := time.Parse("15:04", timeStr)
+		t, err := parseFieldTime(fieldType, str)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		f.Value = t
+		v = t
 	case FieldTypeCheckmark:
-		var checkmark CheckmarkType
-		err = json.Unmarshal(raw.Value, &checkmark)
-		f.Value = checkmark
+		var str string
+		str, err = decodeString(b)
+		v = CheckmarkType(str)
 	case FieldTypeDueDate:
-		var dateStr string
-		if err := json.Unmarshal(raw.Value, &dateStr); err != nil {
-			return err
+		str, err := decodeString(b)
+		if err != nil {
+			return nil, err
 		}
 
-		date, err := time.Parse("2006-01-02", dateStr)
+		t, err := parseFieldTime(fieldType, str)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		f.Value = date
+		v = t
 	case FieldTypeDueDateTime:
-		var dateStr string
-		if err := json.Unmarshal(raw.Value, &dateStr); err != nil {
-			return err
+		str, err := decodeString(b)
+		if err != nil {
+			return nil, err
 		}
 
-		date, err := time.Parse(time.RFC3339, dateStr)
+		t, err := parseFieldTime(fieldType, str)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		f.Value = date
+		v = t
 	case FieldTypeEmail:
-		var email string
-		err = json.Unmarshal(raw.Value, &email)
-		f.Value = email
+		v, err = decodeString(b)
 	case FieldTypePhone:
-		var phone string
-		err = json.Unmarshal(raw.Value, &phone)
-		f.Value = phone
+		v, err = decodeString(b)
 	case FieldTypeFlag:
-		var flg FlagType
-		err = json.Unmarshal(raw.Value, &flg)
-		f.Value = flg
+		var str string
+		str, err = decodeString(b)
+		v = FlagType(str)
 	case FieldTypeStep:
-		var step int
-		err = json.Unmarshal(raw.Value, &step)
-		f.Value = step
+		v, err = decodeInt(b)
 	case FieldTypeStatus:
-		var status StatusType
-		err = json.Unmarshal(raw.Value, &status)
-		f.Value = status
+		var str string
+		str, err = decodeString(b)
+		v = StatusType(str)
 	case FieldTypeCreationDate:
-		var dateStr string
-		if err := json.Unmarshal(raw.Value, &dateStr); err != nil {
-			return err
+		str, err := decodeString(b)
+		if err != nil {
+			return nil, err
 		}
 
-		date, err := time.Parse("2006-01-02", dateStr)
+		t, err := parseFieldTime(fieldType, str)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		f.Value = date
+		v = t
 	case FieldTypeNote:
-		var note string
-		err = json.Unmarshal(raw.Value, &note)
-		f.Value = note
+		v, err = decodeString(b)
 	case FieldTypeCatalog:
 		var catalogItem CatalogItem
-		err = json.Unmarshal(raw.Value, &catalogItem)
-		f.Value = &catalogItem
+		err = json.Unmarshal(b, &catalogItem)
+		v = &catalogItem
 	case FieldTypeFile:
 		var files []*File
-		err = json.Unmarshal(raw.Value, &files)
-		f.Value = files
+		err = json.Unmarshal(b, &files)
+		v = files
 	case FieldTypePerson:
 		var person Person
-		err = json.Unmarshal(raw.Value, &person)
-		f.Value = &person
+		err = json.Unmarshal(b, &person)
+		v = &person
 	case FieldTypeAuthor:
 		var author Person
-		err = json.Unmarshal(raw.Value, &author)
-		f.Value = &author
+		err = json.Unmarshal(b, &author)
+		v = &author
 	case FieldTypeTable:
 		var table Table
-		err = json.Unmarshal(raw.Value, &table)
-		f.Value = table
+		err = json.Unmarshal(b, &table)
+		v = table
 	case FieldTypeMultipleChoice:
 		var mc MultipleChoice
-		err = json.Unmarshal(raw.Value, &mc)
-		f.Value = &mc
+		err = json.Unmarshal(b, &mc)
+		v = &mc
 	case FieldTypeTitle:
 		var title Title
-		err = json.Unmarshal(raw.Value, &title)
-		f.Value = &title
+		err = json.Unmarshal(b, &title)
+		v = &title
 	case FieldTypeFormLink:
 		var formLink FormLink
-		err = json.Unmarshal(raw.Value, &formLink)
-		f.Value = &formLink
+		err = json.Unmarshal(b, &formLink)
+		v = &formLink
+	case FieldTypeProject:
+		var project Project
+		err = json.Unmarshal(b, &project)
+		v = &project
 	default:
 		var i interface{}
-		err = json.Unmarshal(raw.Value, &i)
-		f.Value = i
+		err = json.Unmarshal(b, &i)
+		v = i
+	}
+
+	return v, err
+}
+
+// decodeString decodes JSON string avoiding reflection for strings without escape sequences.
+func decodeString(b []byte) (string, error) {
+	if n := len(b); n >= 2 && b[0] == '"' && b[n-1] == '"' {
+		inner := b[1 : n-1]
+		if bytes.IndexByte(inner, '\\') < 0 && bytes.IndexByte(inner, '"') < 0 && utf8.Valid(inner) {
+			return string(inner), nil
+		}
+	}
+
+	var str string
+	err := json.Unmarshal(b, &str)
+	return str, err
+}
+
+// decodeFloat decodes JSON number avoiding reflection.
+func decodeFloat(b []byte) (float64, error) {
+	if n, err := strconv.ParseFloat(string(b), 64); err == nil {
+		return n, nil
 	}
 
-	return err
+	var n float64
+	err := json.Unmarshal(b, &n)
+	return n, err
+}
+
+// decodeInt decodes JSON integer avoiding reflection.
+func decodeInt(b []byte) (int, error) {
+	if n, err := strconv.Atoi(string(b)); err == nil {
+		return n, nil
+	}
+
+	var n int
+	err := json.Unmarshal(b, &n)
+	return n, err
+}
+
+// MarshalJSON is a custom marshaler which encodes values in the format used by API, see CoerceFieldValue.
+// Values which can't be converted are encoded as is, since fetched tasks must be encoded back without loss,
+// use Validate to check them before sending.
+func (f FormField) MarshalJSON() ([]byte, error) {
+	type RawFormField FormField
+	raw := RawFormField(f)
+
+	if v, err := CoerceFieldValue(f.Type, f.Value); err == nil {
+		raw.Value = v
+	}
+
+	return json.Marshal(raw)
 }
 
 // TaskHeader represents only basic information about a task.
@@ -304,6 +382,7 @@ type Subscriber struct {
 type TaskComment struct {
 	ID                     int        `json:"id"`
 	Text                   string     `json:"text"`
+	FormattedText          string     `json:"formatted_text"`
 	Mentions               []int      `json:"mentions"`
 	CreateDate             time.Time  `json:"create_date"`
 	Author                 *Person    `json:"author"`
@@ -341,6 +420,7 @@ type TaskComment struct {
 	Channel              *Channel      `json:"channel"`
 }
 
+// AnnouncementComment represents a comment from announcement.
 type AnnouncementComment struct {
 	ID          int       `json:"id"`
 	Text        string    `json:"text"`
@@ -412,6 +492,17 @@ type FormLink struct {
 	Subject string `json:"subject"`
 }
 
+// Project represents a value of project field, i.e. the folders which the task belongs to.
+type Project struct {
+	Projects []*ProjectItem `json:"projects"`
+}
+
+// ProjectItem represents a single folder of project field.
+type ProjectItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
 // Channel represents an external channel of comments. It allows to mark there to send or from there it was sent.
 type Channel struct {
 	Type ChannelType  `json:"type"`
@@ -460,3 +551,34 @@ type NewFile struct {
 	// Name is link name (optional)
 	Name string `json:"name,omitempty"`
 }
+
+// NumericID is an identifier that Pyrus sometimes encodes as a JSON string instead of a number.
+// It accepts both while decoding and is always encoded as a number.
+type NumericID int64
+
+// UnmarshalJSON is a custom unmarshaler accepting both numbers and numeric strings.
+func (id *NumericID) UnmarshalJSON(b []byte) error {
+	b = bytes.Trim(b, `"`)
+	if len(b) == 0 || string(b) == "null" {
+		*id = 0
+		return nil
+	}
+
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*id = NumericID(n)
+	return nil
+}
+
+// Int returns id as int, the type used for identifiers everywhere else in the library.
+func (id NumericID) Int() int {
+	return int(id)
+}
+
+// String returns id as a decimal string.
+func (id NumericID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
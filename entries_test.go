@@ -0,0 +1,85 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func BenchmarkFormField_UnmarshalJSON(b *testing.B) {
+	data := []byte(`[
+		{"id":1,"type":"text","name":"Текст","value":"Пример текста"},
+		{"id":2,"type":"money","name":"Сумма","value":1234.5},
+		{"id":3,"type":"date","name":"Дата","value":"2021-08-01"},
+		{"id":4,"type":"due_date_time","name":"Срок","value":"2021-08-01T21:20:00Z"},
+		{"id":5,"type":"email","name":"Email","value":"ivanov@example.org"},
+		{"id":6,"type":"step","name":"Этап","value":2},
+		{"id":7,"type":"person","name":"Ответственный","value":{"id":123456,"first_name":"Иван","last_name":"Иванов"}}
+	]`)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var fields []*FormField
+		if err := json.Unmarshal(data, &fields); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRegistryResponse_UnmarshalJSON(b *testing.B) {
+	benchmarkRegistryResponseUnmarshal(b, json.Unmarshal)
+}
+
+func benchmarkRegistryResponseUnmarshal(b *testing.B, unmarshal func(data []byte, v interface{}) error) {
+	data, err := os.ReadFile("testdata/registry.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		var resp FormRegisterResponse
+		if err := unmarshal(data, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFormField_UnmarshalJSON_fastPaths(t *testing.T) {
+	var fields []*FormField
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id":1,"type":"text","value":"простой текст"},
+		{"id":2,"type":"text","value":"с \"кавычками\"\nи переносом"},
+		{"id":3,"type":"text","value":null},
+		{"id":4,"type":"number","value":-1.5e3},
+		{"id":5,"type":"step","value":3},
+		{"id":6,"type":"checkmark","value":"checked"}
+	]`), &fields))
+
+	assert.Equal(t, "простой текст", fields[0].Value)
+	assert.Equal(t, "с \"кавычками\"\nи переносом", fields[1].Value)
+	assert.Equal(t, "", fields[2].Value)
+	assert.Equal(t, -1500.0, fields[3].Value)
+	assert.Equal(t, 3, fields[4].Value)
+	assert.Equal(t, CheckmarkType("checked"), fields[5].Value)
+
+	var f FormField
+	assert.Error(t, json.Unmarshal([]byte(`{"id":1,"type":"number","value":"12"}`), &f))
+}
+
+func TestFormField_UnmarshalJSON_project(t *testing.T) {
+	var f FormField
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"type":"project","value":{"projects":[
+		{"id":10,"name":"Продажи"},{"id":11,"name":"Москва"}
+	]}}`), &f))
+
+	project, ok := f.Value.(*Project)
+	require.True(t, ok)
+	require.Len(t, project.Projects, 2)
+	assert.Equal(t, 10, project.Projects[0].ID)
+	assert.Equal(t, "Продажи, Москва", FormatFieldValue(&f))
+}
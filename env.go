@@ -0,0 +1,114 @@
+package pyrus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// NewClientFromEnv returns an instance of Client configured from the environment:
+//
+//	PYRUS_LOGIN, PYRUS_SECURITY_KEY  credentials, required
+//	PYRUS_BASE_URL                   API base URL, e.g. of on-premise installation
+//	PYRUS_AUTH_BASE_URL              base URL of the auth endpoint, see WithAuthBaseURL
+//	PYRUS_FILE_BASE_URL              base URL of file uploads and downloads, see WithFileBaseURL
+//	PYRUS_API_VERSION                API version, see WithAPIVersion
+//	PYRUS_TIMEOUT                    request timeout like 30s, see WithTimeout
+//	PYRUS_TRANSPORT_PROFILE          high-throughput or low-latency, see WithTransportProfile
+//	PYRUS_ERROR_LANGUAGE             en or ru, see WithErrorLanguage
+//	PYRUS_CA_CERT_FILE               PEM bundle of trusted CA certificates, see WithCACertFile
+//	PYRUS_INSECURE_SKIP_VERIFY       true to disable certificate verification, see WithInsecureSkipVerify
+//	PYRUS_CLIENT_CERT_FILE           PEM client certificate, see WithClientCertificateFile
+//	PYRUS_CLIENT_KEY_FILE            PEM key of the client certificate, required with PYRUS_CLIENT_CERT_FILE
+//
+// Options passed explicitly are applied after the environment ones, so they take precedence.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	return newClientFromEnv(os.LookupEnv, opts...)
+}
+
+func newClientFromEnv(lookup func(key string) (string, bool), opts ...Option) (*Client, error) {
+	login, _ := lookup("PYRUS_LOGIN")
+	if login == "" {
+		return nil, errors.New("PYRUS_LOGIN environment variable is required")
+	}
+	securityKey, _ := lookup("PYRUS_SECURITY_KEY")
+	if securityKey == "" {
+		return nil, errors.New("PYRUS_SECURITY_KEY environment variable is required")
+	}
+
+	var envOpts []Option
+	for _, key := range []string{"PYRUS_BASE_URL", "PYRUS_AUTH_BASE_URL", "PYRUS_FILE_BASE_URL"} {
+		v, ok := lookup(key)
+		if !ok || v == "" {
+			continue
+		}
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%s environment variable must be http or https URL, got %q", key, v)
+		}
+
+		switch key {
+		case "PYRUS_BASE_URL":
+			envOpts = append(envOpts, WithBaseURL(v))
+		case "PYRUS_AUTH_BASE_URL":
+			envOpts = append(envOpts, WithAuthBaseURL(v))
+		case "PYRUS_FILE_BASE_URL":
+			envOpts = append(envOpts, WithFileBaseURL(v))
+		}
+	}
+
+	if v, ok := lookup("PYRUS_API_VERSION"); ok && v != "" {
+		if !apiVersionRe.MatchString(v) {
+			return nil, fmt.Errorf("PYRUS_API_VERSION environment variable must look like v4, got %q", v)
+		}
+		envOpts = append(envOpts, WithAPIVersion(v))
+	}
+	if v, ok := lookup("PYRUS_TIMEOUT"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("PYRUS_TIMEOUT environment variable must be a positive duration, got %q", v)
+		}
+		envOpts = append(envOpts, WithTimeout(d))
+	}
+	if v, ok := lookup("PYRUS_TRANSPORT_PROFILE"); ok && v != "" {
+		p := TransportProfile(v)
+		if p != TransportProfileHighThroughput && p != TransportProfileLowLatency {
+			return nil, fmt.Errorf("PYRUS_TRANSPORT_PROFILE environment variable must be %s or %s, got %q",
+				TransportProfileHighThroughput, TransportProfileLowLatency, v)
+		}
+		envOpts = append(envOpts, WithTransportProfile(p))
+	}
+	if v, ok := lookup("PYRUS_ERROR_LANGUAGE"); ok && v != "" {
+		lang := Language(v)
+		if lang != LanguageEnglish && lang != LanguageRussian {
+			return nil, fmt.Errorf("PYRUS_ERROR_LANGUAGE environment variable must be %s or %s, got %q",
+				LanguageEnglish, LanguageRussian, v)
+		}
+		envOpts = append(envOpts, WithErrorLanguage(lang))
+	}
+	if v, ok := lookup("PYRUS_CA_CERT_FILE"); ok && v != "" {
+		envOpts = append(envOpts, WithCACertFile(v))
+	}
+	if v, ok := lookup("PYRUS_INSECURE_SKIP_VERIFY"); ok && v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("PYRUS_INSECURE_SKIP_VERIFY environment variable must be a boolean, got %q", v)
+		}
+		if insecure {
+			envOpts = append(envOpts, WithInsecureSkipVerify())
+		}
+	}
+	certFile, _ := lookup("PYRUS_CLIENT_CERT_FILE")
+	keyFile, _ := lookup("PYRUS_CLIENT_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("PYRUS_CLIENT_CERT_FILE and PYRUS_CLIENT_KEY_FILE environment variables must be set together")
+	}
+	if certFile != "" {
+		envOpts = append(envOpts, WithClientCertificateFile(certFile, keyFile))
+	}
+
+	return NewClient(login, securityKey, append(envOpts, opts...)...)
+}
@@ -0,0 +1,58 @@
+package pyrus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	env := map[string]string{
+		"PYRUS_LOGIN":             "bot@example.org",
+		"PYRUS_SECURITY_KEY":      "key",
+		"PYRUS_BASE_URL":          "https://pyrus.example.org/api/v4",
+		"PYRUS_TIMEOUT":           "15s",
+		"PYRUS_TRANSPORT_PROFILE": "low-latency",
+		"PYRUS_ERROR_LANGUAGE":    "ru",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	c, err := newClientFromEnv(lookup, WithTimeout(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "bot@example.org", c.login)
+	assert.Equal(t, "https://pyrus.example.org/api/v4", c.baseURL)
+	assert.Equal(t, time.Minute, c.timeout)
+	assert.Equal(t, LanguageRussian, c.errorLanguage)
+
+	for key, value := range map[string]string{
+		"PYRUS_BASE_URL":             "pyrus.example.org",
+		"PYRUS_TIMEOUT":              "soon",
+		"PYRUS_TRANSPORT_PROFILE":    "fast",
+		"PYRUS_ERROR_LANGUAGE":       "de",
+		"PYRUS_API_VERSION":          "latest",
+		"PYRUS_INSECURE_SKIP_VERIFY": "maybe",
+		"PYRUS_CLIENT_CERT_FILE":     "cert.pem",
+		"PYRUS_SECURITY_KEY":         "",
+	} {
+		original, exists := env[key]
+		env[key] = value
+
+		_, err := newClientFromEnv(lookup)
+		assert.Error(t, err, key)
+
+		if exists {
+			env[key] = original
+		} else {
+			delete(env, key)
+		}
+	}
+
+	env["PYRUS_TIMEOUT"] = "0s"
+	_, err = newClientFromEnv(lookup)
+	assert.EqualError(t, err, `PYRUS_TIMEOUT environment variable must be a positive duration, got "0s"`)
+}
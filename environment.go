@@ -0,0 +1,47 @@
+package pyrus
+
+import "strings"
+
+// Environment is a set of base URLs of a Pyrus installation, see WithEnvironment.
+// Empty AuthBaseURL and FileBaseURL mean that BaseURL serves authorization and files too.
+type Environment struct {
+	Name        string
+	BaseURL     string
+	AuthBaseURL string
+	FileBaseURL string
+}
+
+// EnvironmentProduction is the Pyrus cloud, it's used by default.
+var EnvironmentProduction = Environment{
+	Name:    "production",
+	BaseURL: baseURL,
+}
+
+// OnPremiseEnvironment returns the environment of on-premise installation served by the host,
+// e.g. "pyrus.example.com". API, authorization and files are served by https://HOST/api/v4.
+// The host could start with http:// or https:// scheme, the first one is kept for installations without TLS.
+func OnPremiseEnvironment(host string) Environment {
+	scheme := "https"
+	for _, s := range []string{"https", "http"} {
+		if strings.HasPrefix(host, s+"://") {
+			scheme, host = s, strings.TrimPrefix(host, s+"://")
+			break
+		}
+	}
+	host = strings.TrimSuffix(host, "/")
+
+	return Environment{
+		Name:    "on-premise",
+		BaseURL: scheme + "://" + host + "/api/v4",
+	}
+}
+
+// WithEnvironment allows to target the environment instead of passing raw URLs to WithBaseURL,
+// WithAuthBaseURL and WithFileBaseURL. Options passed after it override its URLs.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.baseURL = env.BaseURL
+		c.authBaseURL = env.AuthBaseURL
+		c.fileBaseURL = env.FileBaseURL
+	}
+}
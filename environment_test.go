@@ -0,0 +1,65 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnvironment(t *testing.T) {
+	t.Run("on-premise", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/api/v4/auth":
+				w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			case "/api/v4/profile":
+				w.Write([]byte(`{"person_id":1}`)) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer ts.Close()
+
+		env := OnPremiseEnvironment(strings.TrimPrefix(ts.URL, "https://") + "/")
+		assert.Equal(t, ts.URL+"/api/v4", env.BaseURL)
+
+		c, err := NewClient("login", "key", WithEnvironment(env), WithInsecureSkipVerify())
+		require.NoError(t, err)
+
+		profile, err := c.Profile()
+		require.NoError(t, err)
+		assert.Equal(t, 1, profile.PersonID)
+	})
+
+	t.Run("replaces auth and file URLs", func(t *testing.T) {
+		c, err := NewClient("login", "key",
+			WithAuthBaseURL("https://auth.example.com"),
+			WithFileBaseURL("https://files.example.com"),
+			WithEnvironment(EnvironmentProduction),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, baseURL, c.baseURL)
+		assert.Empty(t, c.authBaseURL)
+		assert.Empty(t, c.fileBaseURL)
+	})
+
+	t.Run("later options take precedence", func(t *testing.T) {
+		c, err := NewClient("login", "key",
+			WithEnvironment(OnPremiseEnvironment("pyrus.example.com")),
+			WithFileBaseURL("https://files.example.com"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "https://pyrus.example.com/api/v4", c.baseURL)
+		assert.Equal(t, "https://files.example.com", c.fileBaseURL)
+	})
+
+	t.Run("scheme is kept", func(t *testing.T) {
+		assert.Equal(t, "http://pyrus.local/api/v4", OnPremiseEnvironment("http://pyrus.local/").BaseURL)
+		assert.Equal(t, "https://pyrus.local/api/v4", OnPremiseEnvironment("https://pyrus.local").BaseURL)
+	})
+}
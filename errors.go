@@ -1,5 +1,14 @@
 package pyrus
 
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
 // ErrorCode is an "enum" for error codes.
 // More about errors at:
 // https://pyrus.com/en/help/api/errors-and-limits
@@ -198,9 +207,234 @@ type Error struct {
 
 	// Returns in case of 404
 	Message string `json:"Message"`
+
+	// StatusCode is HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// OriginalDescription is the description returned by API if it was replaced according to WithErrorLanguage.
+	OriginalDescription string `json:"-"`
 }
 
 // Error returns error as a human readable string
 func (e Error) Error() string {
 	return "API error: " + e.Description + " (" + string(e.Code) + ")"
 }
+
+// Sentinel errors matched by API errors with errors.Is.
+var (
+	// ErrNotFound matches errors of requests to non-existent entities.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches errors of ErrorCategoryAuth.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited matches errors of ErrorCategoryQuota.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation matches errors of ErrorCategoryValidation.
+	ErrValidation = errors.New("validation failed")
+)
+
+// Is allows to match the error with sentinel errors: errors.Is(err, pyrus.ErrNotFound).
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.Code.Category() == ErrorCategoryAuth ||
+			e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.Code.Category() == ErrorCategoryQuota || e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.Code.Category() == ErrorCategoryValidation
+	}
+
+	return false
+}
+
+// localError returns Error detected by the client before sending a request.
+func localError(code ErrorCode, description string) error {
+	return Error{
+		Code:        code,
+		Description: description,
+	}
+}
+
+// RequestError wraps any error returned by the API request with its context.
+// Use errors.As to get underlying Error.
+type RequestError struct {
+	Method  string
+	Path    string
+	Attempt int
+	Err     error
+}
+
+// Error returns error as a human readable string
+func (e *RequestError) Error() string {
+	return e.Method + " " + e.Path + " (attempt " + strconv.Itoa(e.Attempt) + "): " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCategory groups error codes by the way they should be handled.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryAuth means invalid credentials, blocked account or lack of access rights.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryQuota means that request or task limits are exceeded, request could be retried later.
+	ErrorCategoryQuota ErrorCategory = "quota"
+	// ErrorCategoryValidation means invalid request, it must be fixed before retrying.
+	ErrorCategoryValidation ErrorCategory = "validation"
+	// ErrorCategoryTransient means temporary server or network problem, request could be retried.
+	ErrorCategoryTransient ErrorCategory = "transient"
+	// ErrorCategoryUnknown is returned for empty or unknown codes.
+	ErrorCategoryUnknown ErrorCategory = "unknown"
+)
+
+// Category returns the category of the error code.
+func (c ErrorCode) Category() ErrorCategory {
+	switch c {
+	case "":
+		return ErrorCategoryUnknown
+	case ErrServerError:
+		return ErrorCategoryTransient
+	case ErrTooManyRequests, ErrTaskLimitExceeded:
+		return ErrorCategoryQuota
+	case ErrInvalidCredentials,
+		ErrTokenNotSpecified,
+		ErrRevokedToken,
+		ErrExpiredToken,
+		ErrInvalidToken,
+		ErrAuthorizationError,
+		ErrAccountBlocked:
+		return ErrorCategoryAuth
+	}
+
+	if strings.HasPrefix(string(c), "access_denied") {
+		return ErrorCategoryAuth
+	}
+	if _, ok := errorCodes[c]; ok {
+		return ErrorCategoryValidation
+	}
+
+	return ErrorCategoryUnknown
+}
+
+// IsRetryable reports whether the request failed with the error could be retried as is:
+// transient and quota API errors, timeouts and broken connections.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pe Error
+	if errors.As(err, &pe) {
+		category := pe.Code.Category()
+		return category == ErrorCategoryTransient || category == ErrorCategoryQuota
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// ConflictError is returned by CommentTask when the task has been commented
+// after the note passed in TaskCommentRequest.ExpectedLastNoteID.
+// Refetch the task, reapply your changes and retry.
+type ConflictError struct {
+	TaskID             int
+	ExpectedLastNoteID int
+	LastNoteID         int
+}
+
+// Error returns error as a human readable string
+func (e *ConflictError) Error() string {
+	return "conflict: task " + strconv.Itoa(e.TaskID) + " has last note " + strconv.Itoa(e.LastNoteID) +
+		", expected " + strconv.Itoa(e.ExpectedLastNoteID)
+}
+
+// errorCodes contains all known error codes.
+var errorCodes = map[ErrorCode]struct{}{
+	ErrServerError:                       {},
+	ErrInvalidCredentials:                {},
+	ErrTokenNotSpecified:                 {},
+	ErrRevokedToken:                      {},
+	ErrExpiredToken:                      {},
+	ErrInvalidToken:                      {},
+	ErrAuthorizationError:                {},
+	ErrAccountBlocked:                    {},
+	ErrInvalidFieldID:                    {},
+	ErrDeletedField:                      {},
+	ErrInvalidFieldName:                  {},
+	ErrInvalidFieldIDName:                {},
+	ErrNonUniqueName:                     {},
+	ErrFieldIdentityMissing:              {},
+	ErrDuplicateField:                    {},
+	ErrInvalidCatalogID:                  {},
+	ErrInvalidCatalogItemName:            {},
+	ErrNonUniqueCatalogItemName:          {},
+	ErrInvalidCatalogItemID:              {},
+	ErrCatalogItemIDNameMismatch:         {},
+	ErrInvalidEmail:                      {},
+	ErrNonUniqueEmail:                    {},
+	ErrInvalidPersonID:                   {},
+	ErrInvalidPersonIDEmail:              {},
+	ErrFormHasNoTask:                     {},
+	ErrUnrecognizedAttachmentID:          {},
+	ErrRequiredFieldMissing:              {},
+	ErrTypeIsNotSupported:                {},
+	ErrCatalogIdentityMissing:            {},
+	ErrIncorrectParametersCount:          {},
+	ErrFilterTypeIsNotSupported:          {},
+	ErrStepFieldDoesNotExists:            {},
+	ErrCatalogItemIDMissing:              {},
+	ErrPersonIdentityMissing:             {},
+	ErrEitherDueDateOrDueCanBeSet:        {},
+	ErrNegativeDuration:                  {},
+	ErrDurationIsTooLong:                 {},
+	ErrDueMissing:                        {},
+	ErrScheduledDateInPast:               {},
+	ErrCannotAddFormProject:              {},
+	ErrFormTemplateCantBeRemovedFromTask: {},
+	ErrNoFileInRequest:                   {},
+	ErrTooLargeRequestLength:             {},
+	ErrRequiredParameterMissing:          {},
+	ErrTooManyTaskSteps:                  {},
+	ErrInvalidValueFormat:                {},
+	ErrTooManyComments:                   {},
+	ErrInvalidStepNumber:                 {},
+	ErrTaskLimitExceeded:                 {},
+	ErrFieldIsInTable:                    {},
+	ErrRequiredTableFieldMissing:         {},
+	ErrDepartmentCatalogCanNotBeModified: {},
+	ErrCatalogDuplicateRows:              {},
+	ErrEmptyCatalogHeaders:               {},
+	ErrCanNotModifyDeletedCatalog:        {},
+	ErrCanNotModifyFirstColumn:           {},
+	ErrCatalogHeadersItemsMismatch:       {},
+	ErrTooManyCatalogItems:               {},
+	ErrCatalogItemMaxLengthExceeded:      {},
+	ErrCatalogDuplicateHeaders:           {},
+	ErrFormIDMissing:                     {},
+	ErrTextMissing:                       {},
+	ErrInvalidJSON:                       {},
+	ErrEmptyBody:                         {},
+	ErrAccessDeniedProject:               {},
+	ErrAccessDeniedTask:                  {},
+	ErrAccessDeniedCloseTask:             {},
+	ErrAccessDeniedReopenTask:            {},
+	ErrAccessDeniedCatalog:               {},
+	ErrAccessDeniedForm:                  {},
+	ErrAccessDeniedPerson:                {},
+	ErrTooManyRequests:                   {},
+	ErrEmptyFile:                         {},
+	ErrBadMultipartContent:               {},
+	ErrInvalidTableRow:                   {},
+	ErrCannotAddExternalUser:             {},
+	ErrUnrecognizedIntegrationGUID:       {},
+	ErrUnrecognizedCallGUID:              {},
+	ErrUnsupportedAttachmentFormat:       {},
+}
@@ -0,0 +1,44 @@
+package pyrus
+
+import "errors"
+
+// ErrNoEventAccessToken is returned by Event.Client if the event doesn't contain access token,
+// e.g. if it was emitted by PollingEventSource.
+var ErrNoEventAccessToken = errors.New("event doesn't have access token")
+
+// Client returns a client acting with the access token of the event, so the handler has exactly
+// the permissions Pyrus granted for the event. The event must be received by webhook handlers of Client.
+func (e Event) Client() (*Client, error) {
+	if e.client == nil {
+		return nil, errors.New("event wasn't received by client")
+	}
+
+	return e.client.EventClient(e)
+}
+
+// EventClient returns a client acting with the access token of the event.
+// It shares HTTP client and settings with c, but not the response cache, since permissions differ.
+// The token is never refreshed with credentials of c: requests fail after it expires.
+func (c *Client) EventClient(e Event) (*Client, error) {
+	if e.AccessToken == "" {
+		return nil, ErrNoEventAccessToken
+	}
+
+	// Settings are copied as a whole, so new ones are shared without listing them here
+	ec := &Client{
+		clientConfig: c.clientConfig,
+
+		accessToken: e.AccessToken,
+		staticToken: true,
+
+		// Closing of the event client doesn't affect c
+		closed: make(chan struct{}),
+	}
+	ec.logger = &redactingLogger{next: c.logger, redact: ec.redact}
+	// Responses depend on the token, so they are coalesced only among requests of the event client
+	if c.inflight != nil {
+		ec.inflight = &requestGroup{}
+	}
+
+	return ec, nil
+}
@@ -0,0 +1,62 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvent_Client(t *testing.T) {
+	var auths int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/auth":
+			auths++
+			w.Write([]byte(`{"access_token":"bot-token"}`)) //nolint:errcheck
+		case r.Header.Get("Authorization") == "Bearer event-token" && r.URL.Path == "/profile":
+			w.Write([]byte(`{"person_id":2}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code":"token_not_specified","error":"Token not specified"}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithBaseURL(ts.URL), WithTimeout(time.Minute), WithRequestCoalescing(),
+		WithWebhookConcurrencyLimit(1, time.Second), WithResponseCache(NewMemoryResponseCache(), time.Minute))
+	require.NoError(t, err)
+
+	body := []byte(`{"event":"comment","access_token":"event-token","task_id":1,"user_id":2}`)
+	event, err := c.ParseWebhook(body, signedWebhookRequest(t, ts.URL, body).Header.Get(WebhookSignatureHeader))
+	require.NoError(t, err)
+
+	ec, err := event.Client()
+	require.NoError(t, err)
+	// settings are shared, while token, locks and in-flight requests are not
+	assert.Equal(t, time.Minute, ec.timeout)
+	assert.Equal(t, 1, ec.webhookLimit)
+	assert.Nil(t, ec.webhookSem)
+	assert.Nil(t, ec.cache)
+	assert.NotSame(t, c.inflight, ec.inflight)
+	assert.Empty(t, ec.login)
+	assert.Empty(t, ec.securityKey)
+
+	profile, err := ec.Profile()
+	require.NoError(t, err)
+	assert.Equal(t, 2, profile.PersonID)
+
+	// expired event token is not replaced with the bot one
+	_, err = ec.Lists()
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Zero(t, auths)
+
+	_, err = Event{}.Client()
+	assert.Error(t, err)
+	_, err = c.EventClient(Event{TaskID: 1})
+	assert.ErrorIs(t, err, ErrNoEventAccessToken)
+}
@@ -0,0 +1,167 @@
+package pyrus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventSource delivers events to fn until the context is done, so bots could be implemented
+// independently of the transport: webhooks or polling.
+type EventSource interface {
+	Run(ctx context.Context, fn func(Event)) error
+}
+
+// WebhookEventSource is an EventSource receiving events with RunWebhookServer.
+type WebhookEventSource struct {
+	client *Client
+	addr   string
+	opts   []WebhookServerOption
+}
+
+// NewWebhookEventSource returns an instance of WebhookEventSource listening on addr.
+func NewWebhookEventSource(client *Client, addr string, opts ...WebhookServerOption) *WebhookEventSource {
+	return &WebhookEventSource{
+		client: client,
+		addr:   addr,
+		opts:   opts,
+	}
+}
+
+// Run starts webhook server and blocks until the context is done.
+func (s *WebhookEventSource) Run(ctx context.Context, fn func(Event)) error {
+	return s.client.RunWebhookServer(ctx, s.addr, fn, s.opts...)
+}
+
+// PollingEventSource is an EventSource for environments without inbound connectivity.
+// It polls the inbox and emits "comment" events with the full task for every task modified since the previous poll.
+// Unlike webhooks events don't contain AccessToken and UserID.
+// The first poll only remembers current state of the inbox, so old tasks are not emitted on start.
+type PollingEventSource struct {
+	client    IClient
+	interval  time.Duration
+	itemCount int
+	onError   func(err error)
+
+	mu          sync.Mutex
+	seen        map[int]time.Time
+	initialized bool
+}
+
+// PollingOption helps to create an option for PollingEventSource.
+type PollingOption func(*PollingEventSource)
+
+// WithPollingInterval allows to override default polling interval of 30 seconds.
+func WithPollingInterval(d time.Duration) PollingOption {
+	return func(s *PollingEventSource) {
+		s.interval = d
+	}
+}
+
+// WithPollingItemCount allows to override default number of inbox tasks requested on every poll.
+func WithPollingItemCount(n int) PollingOption {
+	return func(s *PollingEventSource) {
+		s.itemCount = n
+	}
+}
+
+// WithPollingErrorHandler allows Run to continue after failed polls, passing errors to the handler.
+// Without it Run stops on the first error.
+func WithPollingErrorHandler(fn func(err error)) PollingOption {
+	return func(s *PollingEventSource) {
+		s.onError = fn
+	}
+}
+
+// NewPollingEventSource returns an instance of PollingEventSource.
+func NewPollingEventSource(client IClient, opts ...PollingOption) *PollingEventSource {
+	s := &PollingEventSource{
+		client:    client,
+		interval:  30 * time.Second,
+		itemCount: defaultTaskListPageSize,
+		seen:      make(map[int]time.Time),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Poll checks the inbox once and passes events of modified tasks to fn.
+func (s *PollingEventSource) Poll(fn func(Event)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inbox, err := s.client.Inbox(s.itemCount)
+	if err != nil {
+		return err
+	}
+
+	for _, header := range inbox.Tasks {
+		if header == nil {
+			continue
+		}
+
+		modified := header.CreateDate
+		if header.LastModifiedDate != nil {
+			modified = *header.LastModifiedDate
+		}
+
+		seenModified, seen := s.seen[header.ID]
+		if seen && !modified.After(seenModified) {
+			continue
+		}
+		if !s.initialized {
+			s.seen[header.ID] = modified
+			continue
+		}
+
+		task, err := s.client.Task(header.ID)
+		if err != nil {
+			return err
+		}
+
+		event := Event{
+			Event:  "comment",
+			TaskID: header.ID,
+			Task:   task.Task,
+		}
+		event.setLastComments()
+		fn(event)
+		s.seen[header.ID] = modified
+	}
+	s.initialized = true
+
+	return nil
+}
+
+// Run polls the inbox every interval until the context is done.
+func (s *PollingEventSource) Run(ctx context.Context, fn func(Event)) error {
+	if s.interval <= 0 {
+		return fmt.Errorf("polling interval must be positive, got %v", s.interval)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	closed := clientDone(s.client)
+	for {
+		if err := s.Poll(fn); err != nil {
+			if s.onError == nil {
+				return err
+			}
+			s.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-closed:
+			return ErrClientClosed
+		case <-ticker.C:
+		}
+	}
+}
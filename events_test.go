@@ -0,0 +1,97 @@
+package pyrus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inboxClient struct {
+	IClient
+	tasks []*TaskHeader
+}
+
+func (c *inboxClient) Inbox(int) (*TaskListResponse, error) {
+	return &TaskListResponse{Tasks: c.tasks}, nil
+}
+
+func (c *inboxClient) Task(taskID int) (*TaskResponse, error) {
+	return &TaskResponse{Task: &TaskWithComments{Task: &Task{TaskHeader: &TaskHeader{ID: taskID}}}}, nil
+}
+
+func TestPollingEventSource_Poll(t *testing.T) {
+	created := time.Date(2021, 7, 31, 0, 0, 0, 0, time.UTC)
+	client := &inboxClient{tasks: []*TaskHeader{{ID: 1, CreateDate: created}}}
+	source := NewPollingEventSource(client)
+
+	var events []Event
+	collect := func(e Event) { events = append(events, e) }
+
+	// first poll only remembers existing tasks
+	require.NoError(t, source.Poll(collect))
+	assert.Empty(t, events)
+
+	modified := created.Add(time.Hour)
+	client.tasks = []*TaskHeader{
+		{ID: 1, CreateDate: created, LastModifiedDate: &modified},
+		{ID: 2, CreateDate: created},
+	}
+	require.NoError(t, source.Poll(collect))
+	require.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].TaskID)
+	assert.Equal(t, "comment", events[0].Event)
+	assert.Equal(t, 2, events[1].Task.ID)
+
+	require.NoError(t, source.Poll(collect))
+	assert.Len(t, events, 2)
+}
+
+func TestPollingEventSource_Run_invalidInterval(t *testing.T) {
+	source := NewPollingEventSource(&inboxClient{}, WithPollingInterval(0))
+	assert.EqualError(t, source.Run(context.Background(), func(Event) {}), "polling interval must be positive, got 0s")
+}
+
+type registryClient struct {
+	IClient
+	tasks []*Task
+}
+
+func (c *registryClient) Registry(int, *RegistryRequest) (*FormRegisterResponse, error) {
+	return &FormRegisterResponse{Tasks: c.tasks}, nil
+}
+
+func (c *registryClient) Task(taskID int) (*TaskResponse, error) {
+	for _, t := range c.tasks {
+		if t.ID == taskID {
+			return &TaskResponse{Task: &TaskWithComments{Task: t}}, nil
+		}
+	}
+
+	return nil, Error{Code: ErrFormHasNoTask}
+}
+
+func TestReplay(t *testing.T) {
+	client := &registryClient{tasks: []*Task{
+		{TaskHeader: &TaskHeader{ID: 1}, LastNoteID: 10},
+		{TaskHeader: &TaskHeader{ID: 2}, LastNoteID: 20},
+	}}
+	store := NewMemoryEventStore()
+
+	// first task has been delivered by webhook
+	require.NoError(t, MarkEventProcessed(store, Event{TaskID: 1, Task: &TaskWithComments{Task: client.tasks[0]}}))
+
+	var replayed []int
+	fn := func(e Event) error {
+		replayed = append(replayed, e.TaskID)
+		return nil
+	}
+	to := time.Now()
+	require.NoError(t, Replay(client, store, to.Add(-time.Hour), to, fn, 1))
+	assert.Equal(t, []int{2}, replayed)
+
+	require.NoError(t, Replay(client, store, to.Add(-time.Hour), to, fn, 1))
+	assert.Equal(t, []int{2}, replayed)
+}
@@ -0,0 +1,234 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// RegistryExporter fetches registries of forms with long history which can't be fetched by a single
+// Registry call in time. The period is split into time windows by creation or modification date,
+// windows are fetched in parallel and merged in chronological order.
+type RegistryExporter struct {
+	client      IClient
+	field       RegistryWindowField
+	window      time.Duration
+	concurrency int
+	request     RegistryRequest
+}
+
+// RegistryExportOption helps to create an option for RegistryExporter.
+type RegistryExportOption func(*RegistryExporter)
+
+// WithExportWindow allows to override default window of 30 days.
+func WithExportWindow(d time.Duration) RegistryExportOption {
+	return func(e *RegistryExporter) {
+		e.window = d
+	}
+}
+
+// WithExportWindowField allows to split the registry by modification date instead of creation date.
+func WithExportWindowField(field RegistryWindowField) RegistryExportOption {
+	return func(e *RegistryExporter) {
+		e.field = field
+	}
+}
+
+// WithExportConcurrency allows to override default limit of 4 windows fetched at once.
+func WithExportConcurrency(n int) RegistryExportOption {
+	return func(e *RegistryExporter) {
+		e.concurrency = n
+	}
+}
+
+// WithExportRegistryRequest allows to pass base registry request, e.g. with FieldIDs or field filters.
+// Date filters of the window field are managed by RegistryExporter and always overridden.
+func WithExportRegistryRequest(req RegistryRequest) RegistryExportOption {
+	return func(e *RegistryExporter) {
+		e.request = req
+	}
+}
+
+// NewRegistryExporter returns an instance of RegistryExporter.
+func NewRegistryExporter(client IClient, opts ...RegistryExportOption) *RegistryExporter {
+	e := &RegistryExporter{
+		client:      client,
+		field:       RegistryWindowCreated,
+		window:      30 * 24 * time.Hour,
+		concurrency: 4,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// RegistryWindow is a part of the registry fetched by RegistryExporter.
+type RegistryWindow struct {
+	// Index is the number of the window starting from 0.
+	Index int
+	From  time.Time
+	To    time.Time
+	Tasks []*Task
+}
+
+// Export fetches tasks of the form created or modified between from and to and returns them merged
+// in the order of windows. Tasks returned by adjacent windows are deduplicated.
+func (e *RegistryExporter) Export(ctx context.Context, formID int, from, to time.Time) ([]*Task, error) {
+	var tasks []*Task
+	seen := make(map[int]struct{})
+	err := e.ExportFunc(ctx, formID, from, to, func(w *RegistryWindow) error {
+		for _, t := range w.Tasks {
+			if t == nil || t.TaskHeader == nil {
+				continue
+			}
+			if _, ok := seen[t.ID]; ok {
+				continue
+			}
+			seen[t.ID] = struct{}{}
+			tasks = append(tasks, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ExportFunc fetches windows between from and to and passes them to fn in chronological order,
+// so the whole registry isn't kept in memory. Fetching stops on the first error of the API or fn.
+func (e *RegistryExporter) ExportFunc(ctx context.Context, formID int, from, to time.Time, fn func(w *RegistryWindow) error) error {
+	if e.request.Format == RegistryFormatCSV {
+		return errors.New("csv registry can't be exported by windows")
+	}
+	if e.field != RegistryWindowCreated && e.field != RegistryWindowModified {
+		return errors.New("unsupported window field " + string(e.field))
+	}
+	if e.window <= 0 {
+		return errors.New("window must be positive")
+	}
+	if !to.After(from) {
+		return errors.New("to must be after from")
+	}
+
+	windows := e.windows(from, to)
+	concurrency := e.concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan int)
+		results = make(chan registryWindowResult)
+		stop    = make(chan struct{})
+		closed  = clientDone(e.client)
+	)
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := e.client.Registry(formID, e.windowRequest(windows[i]))
+				if err == nil {
+					windows[i].Tasks = resp.Tasks
+				}
+
+				select {
+				case results <- registryWindowResult{index: i, err: err}:
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range windows {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-closed:
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	defer close(stop)
+
+	// Windows complete in any order, so completed ones wait until all previous windows are passed to fn
+	done := make(map[int]bool, concurrency)
+	next := 0
+	for r := range results {
+		if r.err != nil {
+			return r.err
+		}
+		done[r.index] = true
+		for done[next] {
+			delete(done, next)
+			if err := fn(windows[next]); err != nil {
+				return err
+			}
+			windows[next] = nil
+			next++
+		}
+	}
+
+	if next < len(windows) {
+		select {
+		case <-closed:
+			return ErrClientClosed
+		default:
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+type registryWindowResult struct {
+	index int
+	err   error
+}
+
+func (e *RegistryExporter) windows(from, to time.Time) []*RegistryWindow {
+	var windows []*RegistryWindow
+	for start := from; start.Before(to); start = start.Add(e.window) {
+		end := start.Add(e.window)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, &RegistryWindow{
+			Index: len(windows),
+			From:  start,
+			To:    end,
+		})
+	}
+
+	return windows
+}
+
+func (e *RegistryExporter) windowRequest(w *RegistryWindow) *RegistryRequest {
+	req := e.request
+	from, to := w.From, w.To
+	switch e.field {
+	case RegistryWindowCreated:
+		req.CreatedAfter = &from
+		req.CreatedBefore = &to
+	case RegistryWindowModified:
+		req.ModifiedAfter = &from
+		req.ModifiedBefore = &to
+	}
+
+	return &req
+}
@@ -0,0 +1,107 @@
+package pyrus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryExporter(t *testing.T) {
+	var calls, inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		var req struct {
+			CreatedAfter  *time.Time `json:"created_after"`
+			CreatedBefore *time.Time `json:"created_before"`
+			FieldIDs      []int      `json:"field_ids"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.NotNil(t, req.CreatedAfter)
+		require.NotNil(t, req.CreatedBefore)
+		assert.Equal(t, []int{1}, req.FieldIDs)
+
+		// Earlier windows respond later, so results have to be reordered
+		day := req.CreatedAfter.Day()
+		time.Sleep(time.Duration(10-day) * 5 * time.Millisecond)
+
+		// Task 100 is returned by the first two windows, like when the boundary is inclusive
+		tasks := `{"id":` + strconv.Itoa(day) + `}`
+		if day <= 2 {
+			tasks += `,{"id":100}`
+		}
+		w.Write([]byte(`{"tasks":[` + tasks + `]}`)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7*24*time.Hour - time.Hour)
+
+	t.Run("windows are merged in order", func(t *testing.T) {
+		e := NewRegistryExporter(c, WithExportWindow(24*time.Hour), WithExportConcurrency(3),
+			WithExportRegistryRequest(RegistryRequest{FieldIDs: []int{1}}))
+
+		tasks, err := e.Export(context.Background(), 1, from, to)
+		require.NoError(t, err)
+
+		ids := make([]int, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		assert.Equal(t, []int{1, 100, 2, 3, 4, 5, 6, 7}, ids)
+		assert.EqualValues(t, 7, calls)
+		assert.LessOrEqual(t, maxInFlight, int32(3))
+	})
+
+	t.Run("stops on handler error", func(t *testing.T) {
+		testErr := errors.New("test")
+		e := NewRegistryExporter(c, WithExportWindow(24*time.Hour), WithExportConcurrency(2),
+			WithExportRegistryRequest(RegistryRequest{FieldIDs: []int{1}}))
+
+		var windows []*RegistryWindow
+		err := e.ExportFunc(context.Background(), 1, from, to, func(w *RegistryWindow) error {
+			windows = append(windows, w)
+			if w.Index == 1 {
+				return testErr
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, testErr)
+		require.Len(t, windows, 2)
+		assert.Equal(t, from, windows[0].From)
+		assert.Equal(t, from.Add(24*time.Hour), windows[0].To)
+		assert.Equal(t, windows[0].To, windows[1].From)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		e := NewRegistryExporter(c)
+		_, err := e.Export(context.Background(), 1, to, from)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,158 @@
+package pyrus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLen is the number of bytes used by http.DetectContentType.
+const sniffLen = 512
+
+// AttachmentInfo contains metadata of the file found before the upload.
+type AttachmentInfo struct {
+	Name string
+	// Size is -1 if it can't be found without reading the whole file.
+	Size int64
+	// ContentType is sniffed from the content, the extension is used if the content isn't recognized.
+	ContentType string
+}
+
+// UploadResult is a result of UploadAttachment.
+type UploadResult struct {
+	UploadResponse
+	Info AttachmentInfo
+	// Warnings contains problems of the file which are likely to make Pyrus reject it for the use.
+	Warnings []string
+}
+
+// DetectAttachment finds the size and the content type of the file and checks whether it suits the use.
+// It returns the reader which has to be uploaded instead of r, since the beginning of r could be consumed.
+func DetectAttachment(name string, r io.Reader, use AttachmentUse) (*AttachmentInfo, io.Reader, []string, error) {
+	info := &AttachmentInfo{Name: name, Size: -1}
+	if size, ok := readerSize(r); ok {
+		info.Size = size
+	}
+
+	head, r, err := sniffHead(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	info.ContentType = detectContentType(name, head)
+
+	return info, r, info.Warnings(use), nil
+}
+
+// Warnings returns problems of the file which are likely to make Pyrus reject it for the use.
+func (i *AttachmentInfo) Warnings(use AttachmentUse) []string {
+	var warnings []string
+	if i.Size == 0 {
+		warnings = append(warnings, "file is empty")
+	}
+	if i.Size > MaxUploadSize {
+		warnings = append(warnings, fmt.Sprintf("file size %d exceeds the limit of %d bytes", i.Size, MaxUploadSize))
+	}
+
+	if use == AttachmentUseCallRecording {
+		if err := checkCallRecordingFormat(i.Name); err != nil {
+			warnings = append(warnings, "unsupported call recording extension "+filepath.Ext(i.Name))
+		}
+		if !isAudioContentType(i.ContentType) {
+			warnings = append(warnings, "call recording content looks like "+i.ContentType)
+		}
+	}
+
+	return warnings
+}
+
+// UploadAttachment is like UploadFile, but detects metadata of the file and returns it with warnings for the use.
+// Warnings don't prevent the upload.
+func (c *Client) UploadAttachment(ctx context.Context, name string, file io.Reader, use AttachmentUse, opts ...UploadOption) (*UploadResult, error) {
+	info, r, warnings, err := DetectAttachment(name, file, use)
+	if err != nil {
+		c.logger.Error("Error while detecting a file type!", err)
+		return nil, err
+	}
+
+	// Size of the original reader is lost if it's wrapped
+	if info.Size >= 0 {
+		opts = append([]UploadOption{WithUploadSize(info.Size)}, opts...)
+	}
+	// Seekers are passed as is, so the upload could be deduplicated, other readers are counted
+	var counter *countingReader
+	body := r
+	if _, ok := r.(io.ReadSeeker); !ok {
+		counter = &countingReader{r: r}
+		body = counter
+	}
+
+	upload, err := c.UploadFileContext(ctx, name, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size < 0 && counter != nil {
+		info.Size = counter.n
+	}
+
+	return &UploadResult{
+		UploadResponse: *upload,
+		Info:           *info,
+		Warnings:       warnings,
+	}, nil
+}
+
+// sniffHead reads the beginning of the reader and returns it with the reader producing the whole content.
+// Seekers are rewound, so they are returned as is.
+func sniffHead(r io.Reader) ([]byte, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	if rs, ok := r.(io.ReadSeeker); ok {
+		offset, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, err
+		}
+		n, err := io.ReadFull(rs, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, nil, err
+		}
+		if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+		return head[:n], r, nil
+	}
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+
+	return head[:n], io.MultiReader(bytes.NewReader(head[:n]), r), nil
+}
+
+// detectContentType sniffs the content type falling back to the extension for unrecognized content.
+func detectContentType(name string, head []byte) string {
+	contentType := http.DetectContentType(head)
+	if contentType != "application/octet-stream" && !strings.HasPrefix(contentType, "text/plain") {
+		return contentType
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+		return byExt
+	}
+
+	return contentType
+}
+
+// isAudioContentType reports whether the content could be a recording in one of callRecordingFormats.
+func isAudioContentType(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	// M4A is an MP4 container and unrecognized content could still be a headerless MP3
+	return strings.HasPrefix(mt, "audio/") || mt == "application/ogg" || mt == "video/mp4" || mt == "application/octet-stream"
+}
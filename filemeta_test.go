@@ -0,0 +1,96 @@
+package pyrus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAttachment(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 600)...)
+
+	t.Run("seeker is rewound", func(t *testing.T) {
+		r := bytes.NewReader(png)
+		info, body, warnings, err := DetectAttachment("scan.png", r, AttachmentUseTask)
+		require.NoError(t, err)
+		assert.Equal(t, &AttachmentInfo{Name: "scan.png", Size: int64(len(png)), ContentType: "image/png"}, info)
+		assert.Empty(t, warnings)
+		assert.Same(t, r, body)
+		assert.Equal(t, len(png), r.Len())
+	})
+
+	t.Run("stream is restored", func(t *testing.T) {
+		info, body, _, err := DetectAttachment("scan", io.MultiReader(bytes.NewReader(png)), AttachmentUseTask)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), info.Size)
+		assert.Equal(t, "image/png", info.ContentType)
+
+		b, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, png, b)
+	})
+
+	t.Run("extension is used for unrecognized content", func(t *testing.T) {
+		info, _, _, err := DetectAttachment("data.json", strings.NewReader(`{"a":1}`), AttachmentUseTask)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", info.ContentType)
+	})
+
+	t.Run("call recording warnings", func(t *testing.T) {
+		_, _, warnings, err := DetectAttachment("call.mp3", strings.NewReader("<html><body>error</body></html>"), AttachmentUseCallRecording)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"call recording content looks like text/html; charset=utf-8"}, warnings)
+
+		_, _, warnings, err = DetectAttachment("call.flac", strings.NewReader("ID3\x03\x00"), AttachmentUseCallRecording)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"unsupported call recording extension .flac"}, warnings)
+
+		_, _, warnings, err = DetectAttachment("call.mp3", strings.NewReader("ID3\x03\x00"), AttachmentUseCallRecording)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("size warnings", func(t *testing.T) {
+		info := &AttachmentInfo{Name: "a.txt", Size: 0, ContentType: "text/plain"}
+		assert.Equal(t, []string{"file is empty"}, info.Warnings(AttachmentUseTask))
+
+		info.Size = MaxUploadSize + 1
+		assert.Len(t, info.Warnings(AttachmentUseTask), 1)
+	})
+}
+
+func TestClient_UploadAttachment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/files/upload":
+			f, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			b, err := io.ReadAll(f)
+			require.NoError(t, err)
+			assert.Equal(t, "<html>page</html>", string(b))
+			w.Write([]byte(`{"guid":"guid","md5_hash":"hash"}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	result, err := c.UploadAttachment(context.Background(), "call.wav",
+		io.MultiReader(strings.NewReader("<html>page</html>")), AttachmentUseCallRecording)
+	require.NoError(t, err)
+	assert.Equal(t, "guid", result.GUID)
+	assert.Equal(t, int64(17), result.Info.Size)
+	assert.Equal(t, "text/html; charset=utf-8", result.Info.ContentType)
+	assert.Len(t, result.Warnings, 1)
+}
@@ -0,0 +1,74 @@
+package pyrus
+
+// WithWebhookFilter allows webhook handlers to skip events before they are sent over Event chan.
+// Skipped events are acknowledged with 200, so Pyrus doesn't redeliver them. Multiple filters must all match.
+func WithWebhookFilter(fn func(e Event) bool) Option {
+	return func(c *Client) {
+		c.webhookFilters = append(c.webhookFilters, fn)
+	}
+}
+
+// WithWebhookForms allows webhook handlers to deliver only events of tasks based on the forms.
+func WithWebhookForms(formIDs ...int) Option {
+	forms := intSet(formIDs)
+
+	return WithWebhookFilter(func(e Event) bool {
+		if e.Task == nil || e.Task.Task == nil {
+			return false
+		}
+
+		_, ok := forms[e.Task.FormID]
+		return ok
+	})
+}
+
+// WithWebhookLists allows webhook handlers to deliver only events of tasks included in any of the lists.
+func WithWebhookLists(listIDs ...int) Option {
+	lists := intSet(listIDs)
+
+	return WithWebhookFilter(func(e Event) bool {
+		if e.Task == nil || e.Task.Task == nil {
+			return false
+		}
+
+		for _, id := range e.Task.ListIDs {
+			if _, ok := lists[id]; ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithWebhookEventTypes allows webhook handlers to deliver only events of the types, e.g. "comment".
+func WithWebhookEventTypes(types ...string) Option {
+	set := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		set[t] = struct{}{}
+	}
+
+	return WithWebhookFilter(func(e Event) bool {
+		_, ok := set[e.Event]
+		return ok
+	})
+}
+
+// acceptWebhook reports whether the event passes all filters.
+func (c *Client) acceptWebhook(e Event) bool {
+	for _, filter := range c.webhookFilters {
+		if !filter(e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func intSet(ids []int) map[int]struct{} {
+	set := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	return set
+}
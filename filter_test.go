@@ -0,0 +1,67 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_acceptWebhook(t *testing.T) {
+	e := Event{
+		Event: "comment",
+		Task: &TaskWithComments{Task: &Task{
+			FormID:  1,
+			ListIDs: []int{10, 11},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{"no filters", nil, true},
+		{"form", []Option{WithWebhookForms(1, 2)}, true},
+		{"other form", []Option{WithWebhookForms(2)}, false},
+		{"list", []Option{WithWebhookLists(11)}, true},
+		{"other list", []Option{WithWebhookLists(12)}, false},
+		{"event type", []Option{WithWebhookEventTypes("comment")}, true},
+		{"other event type", []Option{WithWebhookEventTypes("task_created")}, false},
+		{"all match", []Option{WithWebhookForms(1), WithWebhookLists(10)}, true},
+		{"one mismatch", []Option{WithWebhookForms(1), WithWebhookLists(12)}, false},
+		{"predicate", []Option{WithWebhookFilter(func(e Event) bool { return e.Task.FormID > 5 })}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient("login", "key", tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.acceptWebhook(e))
+		})
+	}
+
+	c, err := NewClient("login", "key", WithWebhookForms(1))
+	require.NoError(t, err)
+	assert.False(t, c.acceptWebhook(Event{Event: "comment", AnnouncementID: 1}))
+}
+
+func TestClient_WebhookHandler_filter(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookEventTypes("task_created"))
+	require.NoError(t, err)
+
+	handler, events := c.WebhookHandler()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedWebhookRequest(t, ts.URL, b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, events, 0)
+}
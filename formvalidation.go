@@ -0,0 +1,166 @@
+package pyrus
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ValidateAgainstForm checks the task request against the form definition before creating a task:
+// fields exist and aren't duplicated, values match field types, catalog and choice values are valid
+// and fields required on the first step are filled. Errors are Error values with the codes returned by the API
+// for the same problems, e.g. ErrInvalidFieldID or ErrRequiredFieldMissing.
+func ValidateAgainstForm(form *FormResponse, req *TaskRequest) error {
+	if req.FormID == 0 {
+		return localError(ErrFormIDMissing, "form_id is missing")
+	}
+	if req.FormID != form.ID {
+		return fmt.Errorf("request is for form %d, but form %d is given", req.FormID, form.ID)
+	}
+
+	columns := make(map[int]struct{})
+	for _, table := range definitionFields(form.Fields, func(f *FormField) bool { return f.Type == FieldTypeTable }) {
+		for _, column := range definitionFields(table.Info.Columns, func(*FormField) bool { return true }) {
+			columns[column.ID] = struct{}{}
+		}
+	}
+
+	filled := make(map[int]struct{}, len(req.Fields))
+	for _, f := range req.Fields {
+		if f == nil {
+			continue
+		}
+
+		def, err := form.definitionFor(f)
+		if err != nil {
+			return err
+		}
+		if _, ok := filled[def.ID]; ok {
+			return localError(ErrDuplicateField, "field "+fieldLabel(def)+" is set multiple times")
+		}
+		if _, ok := columns[def.ID]; ok {
+			return localError(ErrFieldIsInTable, "field "+fieldLabel(def)+" is a table column and can be set only within a row")
+		}
+		if err := validateFieldValue(def, f); err != nil {
+			return err
+		}
+
+		if f.Value != nil {
+			filled[def.ID] = struct{}{}
+		}
+	}
+
+	for _, def := range requiredOnCreate(form.Fields) {
+		if _, ok := filled[def.ID]; !ok {
+			return localError(ErrRequiredFieldMissing, "required field "+fieldLabel(def)+" is missing")
+		}
+	}
+
+	return nil
+}
+
+// definitionFor finds the definition of the request field by its id and name.
+func (r *FormResponse) definitionFor(f *FormField) (*FormField, error) {
+	switch {
+	case f.ID != 0:
+		found := definitionFields(r.Fields, func(def *FormField) bool { return def.ID == f.ID })
+		if len(found) == 0 {
+			return nil, localError(ErrInvalidFieldID, "field "+strconv.Itoa(f.ID)+" doesn't exist in the form")
+		}
+		if f.Name != "" && found[0].Name != f.Name {
+			return nil, localError(ErrInvalidFieldIDName, "field "+strconv.Itoa(f.ID)+" isn't named "+strconv.Quote(f.Name))
+		}
+		return found[0], nil
+	case f.Name != "":
+		found := definitionFields(r.Fields, func(def *FormField) bool { return def.Name == f.Name })
+		if len(found) == 0 {
+			return nil, localError(ErrInvalidFieldName, "field "+strconv.Quote(f.Name)+" doesn't exist in the form")
+		}
+		if len(found) > 1 {
+			return nil, localError(ErrNonUniqueName, "field name "+strconv.Quote(f.Name)+" isn't unique, use id")
+		}
+		return found[0], nil
+	default:
+		return nil, localError(ErrFieldIdentityMissing, "field id or name is missing")
+	}
+}
+
+// validateFieldValue checks the value of the request field against its definition.
+func validateFieldValue(def, f *FormField) error {
+	if f.Type != "" && f.Type != def.Type {
+		return localError(ErrInvalidValueFormat, "field "+fieldLabel(def)+" has type "+string(def.Type)+", not "+string(f.Type))
+	}
+
+	value, err := CoerceFieldValue(def.Type, f.Value)
+	if err != nil {
+		return localError(ErrInvalidValueFormat, "field "+fieldLabel(def)+": "+err.Error())
+	}
+
+	switch v := value.(type) {
+	case *CatalogItem:
+		if v.ItemID == 0 && len(v.ItemIDs) == 0 && len(v.Values) == 0 {
+			return localError(ErrCatalogIdentityMissing, "catalog field "+fieldLabel(def)+" requires item_id")
+		}
+		if len(v.ItemIDs) > 1 && (def.Info == nil || !def.Info.MultipleChoice) {
+			return localError(ErrInvalidValueFormat, "catalog field "+fieldLabel(def)+" doesn't allow multiple items")
+		}
+	case *MultipleChoice:
+		return validateChoice(def, v)
+	case MultipleChoice:
+		return validateChoice(def, &v)
+	}
+
+	return nil
+}
+
+// validateChoice checks that chosen options exist in the multiple choice field and aren't deleted.
+func validateChoice(def *FormField, v *MultipleChoice) error {
+	if def.Info == nil {
+		return nil
+	}
+
+	options := make(map[int]struct{}, len(def.Info.Options))
+	for _, option := range def.Info.Options {
+		if option != nil && !option.Deleted {
+			options[option.ChoiceID] = struct{}{}
+		}
+	}
+
+	ids := v.ChoiceIDs
+	if v.ChoiceID != 0 {
+		ids = append([]int{v.ChoiceID}, ids...)
+	}
+	for _, id := range ids {
+		if _, ok := options[id]; !ok {
+			return localError(ErrInvalidValueFormat, "field "+fieldLabel(def)+" has no option "+strconv.Itoa(id))
+		}
+	}
+
+	return nil
+}
+
+// requiredOnCreate returns fields required on the first step. Fields of choice options and table columns
+// are skipped, since they are required only when the option is chosen or within table rows.
+func requiredOnCreate(fields []*FormField) []*FormField {
+	var required []*FormField
+	for _, f := range fields {
+		if f == nil || f.Info == nil {
+			continue
+		}
+		if f.Info.RequiredStep == 1 {
+			required = append(required, f)
+		}
+		if f.Type == FieldTypeTitle {
+			required = append(required, requiredOnCreate(f.Info.Fields)...)
+		}
+	}
+
+	return required
+}
+
+func fieldLabel(f *FormField) string {
+	if f.Name != "" {
+		return strconv.Quote(f.Name)
+	}
+
+	return strconv.Itoa(f.ID)
+}
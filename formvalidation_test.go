@@ -0,0 +1,75 @@
+package pyrus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgainstForm(t *testing.T) {
+	form := &FormResponse{
+		ID: 1,
+		Fields: []*FormField{
+			{ID: 1, Type: FieldTypeText, Name: "Описание", Info: &FormFieldInfo{RequiredStep: 1}},
+			{ID: 2, Type: FieldTypeMoney, Name: "Сумма"},
+			{ID: 3, Type: FieldTypeMultipleChoice, Name: "Тип", Info: &FormFieldInfo{Options: []*ChoiceOption{
+				{ChoiceID: 1, ChoiceValue: "Закупка", Fields: []*FormField{
+					{ID: 4, Type: FieldTypeText, Name: "Поставщик", Info: &FormFieldInfo{RequiredStep: 1}},
+				}},
+				{ChoiceID: 2, ChoiceValue: "Старый", Deleted: true},
+			}}},
+			{ID: 5, Type: FieldTypeCatalog, Name: "Офис", Info: &FormFieldInfo{CatalogID: 10}},
+			{ID: 6, Type: FieldTypeTable, Name: "Товары", Info: &FormFieldInfo{Columns: []*FormField{
+				{ID: 7, Type: FieldTypeText, Name: "Товар"},
+			}}},
+			{ID: 8, Type: FieldTypeTitle, Name: "Итого", Info: &FormFieldInfo{Fields: []*FormField{
+				{ID: 9, Type: FieldTypeDate, Name: "Срок", Info: &FormFieldInfo{RequiredStep: 1}},
+			}}},
+		},
+	}
+
+	valid := func(fields ...*FormField) *TaskRequest {
+		return &TaskRequest{FormID: 1, Fields: append([]*FormField{
+			{ID: 1, Value: "Бумага"},
+			{ID: 9, Value: "2021-07-21"},
+		}, fields...)}
+	}
+
+	tests := []struct {
+		name string
+		req  *TaskRequest
+		code ErrorCode
+	}{
+		{"valid", valid(&FormField{Name: "Сумма", Value: 100}, &FormField{ID: 3, Value: &MultipleChoice{ChoiceIDs: []int{1}}}, &FormField{ID: 5, Value: 1}), ""},
+		{"no form", &TaskRequest{}, ErrFormIDMissing},
+		{"unknown id", valid(&FormField{ID: 100, Value: "x"}), ErrInvalidFieldID},
+		{"unknown name", valid(&FormField{Name: "Нет", Value: "x"}), ErrInvalidFieldName},
+		{"id and name mismatch", valid(&FormField{ID: 2, Name: "Описание", Value: 1}), ErrInvalidFieldIDName},
+		{"duplicate", valid(&FormField{Name: "Описание", Value: "x"}), ErrDuplicateField},
+		{"identity", valid(&FormField{Value: "x"}), ErrFieldIdentityMissing},
+		{"type mismatch", valid(&FormField{ID: 2, Type: FieldTypeText, Value: "x"}), ErrInvalidValueFormat},
+		{"invalid value", valid(&FormField{ID: 2, Value: "много"}), ErrInvalidValueFormat},
+		{"deleted choice", valid(&FormField{ID: 3, Value: &MultipleChoice{ChoiceIDs: []int{2}}}), ErrInvalidValueFormat},
+		{"catalog identity", valid(&FormField{ID: 5, Value: &CatalogItem{}}), ErrCatalogIdentityMissing},
+		{"multiple catalog items", valid(&FormField{ID: 5, Value: []int{1, 2}}), ErrInvalidValueFormat},
+		{"table column", valid(&FormField{ID: 7, Value: "x"}), ErrFieldIsInTable},
+		{"required", &TaskRequest{FormID: 1, Fields: []*FormField{{ID: 1, Value: "Бумага"}}}, ErrRequiredFieldMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstForm(form, tt.req)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var apiErr Error
+			require.True(t, errors.As(err, &apiErr), err)
+			assert.Equal(t, tt.code, apiErr.Code)
+		})
+	}
+
+	assert.Error(t, ValidateAgainstForm(form, &TaskRequest{FormID: 2}))
+}
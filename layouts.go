@@ -0,0 +1,77 @@
+package pyrus
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	layoutsMu sync.RWMutex
+
+	// timeLayouts are tried in order to parse values of date and time fields.
+	// The first layout of every type is the one used by API.
+	timeLayouts = map[FieldType][]string{
+		FieldTypeDate:         dateLayouts(),
+		FieldTypeDueDate:      dateLayouts(),
+		FieldTypeCreationDate: dateLayouts(),
+		FieldTypeDueDateTime: {
+			time.RFC3339,
+			time.RFC3339Nano,
+			"2006-01-02T15:04:05",
+			"2006-01-02T15:04",
+			"2006-01-02 15:04:05",
+			"2006-01-02 15:04",
+		},
+		FieldTypeTime: {
+			"15:04",
+			"15:04:05",
+			"15:04:05.999999999",
+			"15:04Z07:00",
+			"15:04:05Z07:00",
+		},
+	}
+)
+
+func dateLayouts() []string {
+	return []string{
+		"2006-01-02",
+		time.RFC3339,
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+	}
+}
+
+// RegisterTimeLayout registers an extra layout used to parse values of date and time fields of the type
+// in addition to the built-in ones.
+func RegisterTimeLayout(fieldType FieldType, layout string) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	for _, l := range timeLayouts[fieldType] {
+		if l == layout {
+			return
+		}
+	}
+	timeLayouts[fieldType] = append(timeLayouts[fieldType], layout)
+}
+
+// parseFieldTime parses the value of date or time field trying all layouts registered for the type.
+func parseFieldTime(fieldType FieldType, s string) (time.Time, error) {
+	layoutsMu.RLock()
+	layouts := timeLayouts[fieldType]
+	layoutsMu.RUnlock()
+
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return time.Time{}, firstErr
+}
@@ -0,0 +1,52 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldTime(t *testing.T) {
+	tests := []struct {
+		fieldType FieldType
+		value     string
+		expected  time.Time
+	}{
+		{FieldTypeDate, "2021-08-01", time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{FieldTypeDate, "2021-08-01T00:00:00Z", time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{FieldTypeTime, "15:04", time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC)},
+		{FieldTypeTime, "15:04:05", time.Date(0, 1, 1, 15, 4, 5, 0, time.UTC)},
+		{FieldTypeDueDateTime, "2021-08-01T21:20:00Z", time.Date(2021, 8, 1, 21, 20, 0, 0, time.UTC)},
+		{FieldTypeDueDateTime, "2021-08-01T21:20:00.123Z", time.Date(2021, 8, 1, 21, 20, 0, 123000000, time.UTC)},
+		{FieldTypeDueDateTime, "2021-08-01T21:20:00", time.Date(2021, 8, 1, 21, 20, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		parsed, err := parseFieldTime(tt.fieldType, tt.value)
+		require.NoError(t, err, tt.value)
+		assert.True(t, tt.expected.Equal(parsed), tt.value)
+	}
+
+	_, err := parseFieldTime(FieldTypeDate, "01.08.2021")
+	assert.Error(t, err)
+
+	// Registered layout mustn't leak into the other tests
+	layoutsMu.RLock()
+	saved := make(map[FieldType][]string, len(timeLayouts))
+	for fieldType, layouts := range timeLayouts {
+		saved[fieldType] = append([]string(nil), layouts...)
+	}
+	layoutsMu.RUnlock()
+	t.Cleanup(func() {
+		layoutsMu.Lock()
+		timeLayouts = saved
+		layoutsMu.Unlock()
+	})
+
+	RegisterTimeLayout(FieldTypeDate, "02.01.2006")
+	var f FormField
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"type":"date","value":"01.08.2021"}`), &f))
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), f.Value)
+}
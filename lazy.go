@@ -0,0 +1,135 @@
+package pyrus
+
+import "encoding/json"
+
+// WithLazyFieldDecoding enables lazy decoding mode for responses of Task and Registry and webhook events
+// of the client. In this mode FormField.Value of task fields and comment field updates is kept as json.RawMessage
+// and decoded by DecodedValue, so pipelines reading only a few fields of large forms don't pay for decoding
+// of every value.
+func WithLazyFieldDecoding() Option {
+	return func(c *Client) {
+		c.lazyFieldDecoding = true
+	}
+}
+
+// lazyFormField is FormField which keeps its value as json.RawMessage when decoded.
+type lazyFormField FormField
+
+func (f *lazyFormField) UnmarshalJSON(b []byte) error {
+	return (*FormField)(f).unmarshalJSON(b, true)
+}
+
+// Lazy types shadow fields containing form fields of the embedded types, so the rest of the fields
+// is decoded into the original structs as usual.
+
+type lazyTask struct {
+	*Task
+	Fields []*lazyFormField `json:"fields,omitempty"`
+}
+
+func (t *lazyTask) task() *Task {
+	if t == nil {
+		return nil
+	}
+	if t.Task == nil {
+		t.Task = &Task{}
+	}
+	t.Task.Fields = formFields(t.Fields)
+
+	return t.Task
+}
+
+type lazyTaskComment struct {
+	*TaskComment
+	FieldUpdates []*lazyFormField `json:"field_updates"`
+}
+
+func (c *lazyTaskComment) comment() *TaskComment {
+	if c == nil {
+		return nil
+	}
+	if c.TaskComment == nil {
+		c.TaskComment = &TaskComment{}
+	}
+	c.TaskComment.FieldUpdates = formFields(c.FieldUpdates)
+
+	return c.TaskComment
+}
+
+type lazyTaskWithComments struct {
+	lazyTask
+	Comments []*lazyTaskComment `json:"comments,omitempty"`
+}
+
+func (t *lazyTaskWithComments) task() *TaskWithComments {
+	if t == nil {
+		return nil
+	}
+
+	task := &TaskWithComments{Task: t.lazyTask.task()}
+	if t.Comments != nil {
+		task.Comments = make([]*TaskComment, len(t.Comments))
+		for i, c := range t.Comments {
+			task.Comments[i] = c.comment()
+		}
+	}
+
+	return task
+}
+
+func formFields(lazy []*lazyFormField) []*FormField {
+	if lazy == nil {
+		return nil
+	}
+
+	fields := make([]*FormField, len(lazy))
+	for i, f := range lazy {
+		fields[i] = (*FormField)(f)
+	}
+
+	return fields
+}
+
+// unmarshalLazy is like json.Unmarshal, but keeps values of task fields as json.RawMessage
+// if v is *TaskResponse, *FormRegisterResponse or *Event.
+func unmarshalLazy(data []byte, v interface{}) error {
+	switch r := v.(type) {
+	case *TaskResponse:
+		lazy := struct {
+			*TaskResponse
+			Task *lazyTaskWithComments `json:"task"`
+		}{TaskResponse: r}
+		if err := json.Unmarshal(data, &lazy); err != nil {
+			return err
+		}
+		r.Task = lazy.Task.task()
+	case *FormRegisterResponse:
+		lazy := struct {
+			*FormRegisterResponse
+			Tasks []*lazyTask `json:"tasks"`
+		}{FormRegisterResponse: r}
+		if err := json.Unmarshal(data, &lazy); err != nil {
+			return err
+		}
+		r.Tasks = nil
+		if lazy.Tasks != nil {
+			r.Tasks = make([]*Task, len(lazy.Tasks))
+			for i, t := range lazy.Tasks {
+				r.Tasks[i] = t.task()
+			}
+		}
+	case *Event:
+		lazy := struct {
+			*Event
+			Task *lazyTaskWithComments `json:"task"`
+		}{Event: r}
+		if err := json.Unmarshal(data, &lazy); err != nil {
+			return err
+		}
+		r.Task = lazy.Task.task()
+	default:
+		return json.Unmarshal(data, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLazyFieldDecoding(t *testing.T) {
+	data := []byte(`{"task":{"id":1,"text":"Заявка","fields":[
+		{"id":1,"type":"date","value":"2021-08-01"},
+		{"id":2,"type":"title","value":{"fields":[{"id":3,"type":"text","value":"Вложенный"}]}},
+		{"id":4,"type":"money","value":1500.5}
+	],"comments":[{"id":5,"field_updates":[{"id":4,"type":"money","value":100}]}]}}`)
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithLazyFieldDecoding())
+	require.NoError(t, err)
+	var resp TaskResponse
+	require.NoError(t, c.unmarshal(data, &resp))
+
+	require.NotNil(t, resp.Task)
+	assert.Equal(t, 1, resp.Task.ID)
+	assert.Equal(t, "Заявка", resp.Task.Text)
+	fields := resp.Task.Fields
+	require.Len(t, fields, 3)
+	assert.Equal(t, json.RawMessage(`"2021-08-01"`), fields[0].Value)
+	require.Len(t, resp.Task.Comments, 1)
+	assert.Equal(t, 5, resp.Task.Comments[0].ID)
+	assert.Equal(t, json.RawMessage(`100`), resp.Task.Comments[0].FieldUpdates[0].Value)
+
+	v, err := fields[0].DecodedValue()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), v)
+	// value is decoded without modification of the field
+	assert.Equal(t, json.RawMessage(`"2021-08-01"`), fields[0].Value)
+
+	// decoding outside of the client is not affected, even if it's concurrent
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var eager TaskResponse
+			assert.NoError(t, json.Unmarshal(data, &eager))
+			assert.Equal(t, time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), eager.Task.Fields[0].Value)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := fields[0].DecodedValue()
+			assert.NoError(t, err)
+			var lazy TaskResponse
+			assert.NoError(t, c.unmarshal(data, &lazy))
+			assert.IsType(t, json.RawMessage{}, lazy.Task.Fields[0].Value)
+		}()
+	}
+	wg.Wait()
+
+	// raw values are encoded as is
+	b, err := json.Marshal(fields[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":2,"type":"title","value":{"fields":[{"id":3,"type":"text","value":"Вложенный"}]}}`, string(b))
+	b, err = json.Marshal(fields[2])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":4,"type":"money","value":1500.5}`, string(b))
+
+	nested := findField(fields, func(f *FormField) bool { return f.ID == 3 })
+	require.NotNil(t, nested)
+	assert.Equal(t, "Вложенный", FormatFieldValue(nested))
+
+	t.Run("registry and events", func(t *testing.T) {
+		var registry FormRegisterResponse
+		require.NoError(t, c.unmarshal([]byte(`{"tasks":[{"id":1,"fields":[{"id":1,"type":"number","value":1}]},null]}`), &registry))
+		require.Len(t, registry.Tasks, 2)
+		assert.Equal(t, json.RawMessage(`1`), registry.Tasks[0].Fields[0].Value)
+		assert.Nil(t, registry.Tasks[1])
+
+		var event Event
+		require.NoError(t, c.unmarshal([]byte(`{"event":"comment","task_id":1,"task":{"id":1,"fields":[{"id":1,"type":"number","value":1}]}}`), &event))
+		assert.Equal(t, "comment", event.Event)
+		assert.Equal(t, json.RawMessage(`1`), event.Task.Fields[0].Value)
+	})
+}
+
+func BenchmarkRegistryResponse_UnmarshalJSON_lazy(b *testing.B) {
+	benchmarkRegistryResponseUnmarshal(b, unmarshalLazy)
+}
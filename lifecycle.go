@@ -0,0 +1,93 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrClientClosed is returned by requests and background loops after Client.Close.
+var ErrClientClosed = errors.New("client is closed")
+
+// Close stops webhook servers started with RunWebhookServer, closes Event chans returned by webhook handlers,
+// stops Run loops of SyncEngine, PollingEventSource and SLAMonitor using the client and closes idle connections.
+// Subsequent requests fail with ErrClientClosed. It's safe to call Close multiple times.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		// Senders give up as soon as c.closed is closed, so the lock is released quickly
+		c.webhookMu.Lock()
+		c.webhookClosed = true
+		for _, ch := range c.webhookChans {
+			close(ch)
+		}
+		c.webhookChans = nil
+		c.webhookMu.Unlock()
+
+		if c.httpClient != http.DefaultClient {
+			c.httpClient.CloseIdleConnections()
+		}
+	})
+
+	return nil
+}
+
+// Done returns a chan which is closed by Close.
+func (c *Client) Done() <-chan struct{} {
+	return c.closed
+}
+
+// isClosed reports whether Close has been called.
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// newWebhookChan returns Event chan closed by Close.
+func (c *Client) newWebhookChan() chan Event {
+	ch := make(chan Event, c.eventBufferSize)
+
+	c.webhookMu.Lock()
+	if c.webhookClosed {
+		close(ch)
+	} else {
+		c.webhookChans = append(c.webhookChans, ch)
+	}
+	c.webhookMu.Unlock()
+
+	return ch
+}
+
+// sendWebhookEvent sends the event unless the client is closed or the context is done.
+// It returns ErrClientClosed or ErrEventBufferFull if the event hasn't been sent.
+func (c *Client) sendWebhookEvent(ctx context.Context, ch chan Event, event Event) error {
+	c.webhookMu.RLock()
+	defer c.webhookMu.RUnlock()
+
+	if c.webhookClosed {
+		return ErrClientClosed
+	}
+
+	select {
+	case ch <- event:
+		return nil
+	case <-c.closed:
+		return ErrClientClosed
+	case <-ctx.Done():
+		return ErrEventBufferFull
+	}
+}
+
+// clientDone returns the chan closed by Client.Close if the client supports it.
+func clientDone(client IClient) <-chan struct{} {
+	if d, ok := client.(interface{ Done() <-chan struct{} }); ok {
+		return d.Done()
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package pyrus
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Close(t *testing.T) {
+	c, err := NewClient("login", "key", WithBaseURL("http://127.0.0.1:0"))
+	require.NoError(t, err)
+
+	_, events := c.WebhookHandler()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- c.RunWebhookServer(context.Background(), addr, func(Event) {})
+	}()
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/healthz")
+		if err != nil {
+			return false
+		}
+		return resp.Body.Close() == nil
+	}, time.Second, 10*time.Millisecond)
+
+	pollErr := make(chan error, 1)
+	go func() {
+		source := NewPollingEventSource(c, WithPollingInterval(time.Hour), WithPollingErrorHandler(func(error) {}))
+		pollErr <- source.Run(context.Background(), func(Event) {})
+	}()
+
+	require.NoError(t, c.Close())
+	require.NoError(t, c.Close())
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.NoError(t, <-serverErr)
+	assert.ErrorIs(t, <-pollErr, ErrClientClosed)
+
+	_, err = c.Profile()
+	assert.ErrorIs(t, err, ErrClientClosed)
+
+	_, events = c.WebhookHandler()
+	_, ok = <-events
+	assert.False(t, ok)
+}
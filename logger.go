@@ -16,6 +16,27 @@ func (l *zapLogger) Error(msg string, err error) {
 	l.logger.Error(msg, zap.Error(err))
 }
 
+// LogrLogger is the subset of github.com/go-logr/logr.Logger used by the library,
+// so logr.Logger could be passed to WithLogrLogger as is without adding the dependency.
+type LogrLogger interface {
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// WithLogrLogger allows to pass logr.Logger instance for error logging.
+func WithLogrLogger(l LogrLogger) Option {
+	return func(c *Client) {
+		c.logger = &logrLogger{logger: l}
+	}
+}
+
+type logrLogger struct {
+	logger LogrLogger
+}
+
+func (l *logrLogger) Error(msg string, err error) {
+	l.logger.Error(err, msg)
+}
+
 type noopLogger struct{}
 
 func (l *noopLogger) Error(string, error) {}
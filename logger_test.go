@@ -0,0 +1,30 @@
+package pyrus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLogr struct {
+	msgs []string
+	errs []error
+}
+
+func (l *fakeLogr) Error(err error, msg string, _ ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+	l.errs = append(l.errs, err)
+}
+
+func TestWithLogrLogger(t *testing.T) {
+	l := &fakeLogr{}
+	c, err := NewClient("login", "secret-key", WithLogrLogger(l))
+	require.NoError(t, err)
+
+	c.logger.Error("Error while doing a request!", errors.New("invalid secret-key"))
+	require.Len(t, l.errs, 1)
+	assert.Equal(t, "Error while doing a request!", l.msgs[0])
+	assert.EqualError(t, l.errs[0], "invalid [REDACTED]")
+}
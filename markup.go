@@ -0,0 +1,424 @@
+package pyrus
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// formattedTextTags maps HTML tags to the tags supported by Pyrus formatted text.
+var formattedTextTags = map[string]string{
+	"b":      "b",
+	"strong": "b",
+	"i":      "i",
+	"em":     "i",
+	"u":      "u",
+	"s":      "s",
+	"strike": "s",
+	"del":    "s",
+	"a":      "a",
+	"ul":     "ul",
+	"ol":     "ol",
+	"li":     "li",
+	"p":      "p",
+	"br":     "br",
+}
+
+// skippedTags are the tags which are removed together with their content.
+var skippedTags = map[string]struct{}{
+	"head":     {},
+	"title":    {},
+	"script":   {},
+	"style":    {},
+	"template": {},
+}
+
+var (
+	hrefRe       = regexp.MustCompile(`(?is)\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+
+	mdHeadingRe       = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdUnorderedItemRe = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdOrderedItemRe   = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+)
+
+// markdownSpecials are the characters escaped by EscapeMarkdown.
+const markdownSpecials = "\\`*_~[]()#+-.!|>"
+
+// HTMLToFormattedText converts arbitrary HTML (e.g. from emails or CRM systems) to the safe subset
+// accepted by Pyrus in formatted_text: bold, italic, underline, strikethrough, links, lists, paragraphs and line breaks.
+// Unsupported tags are removed while their text is kept, scripts and styles are removed completely,
+// links are kept only for http, https and mailto schemes.
+func HTMLToFormattedText(s string) string {
+	var (
+		out   strings.Builder
+		stack []string
+	)
+
+	closeTag := func(tag string) {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] != tag {
+				continue
+			}
+			for j := len(stack) - 1; j >= i; j-- {
+				out.WriteString("</" + stack[j] + ">")
+			}
+			stack = stack[:i]
+			return
+		}
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			out.WriteString(escapeHTMLText(s))
+			break
+		}
+		if lt > 0 {
+			out.WriteString(escapeHTMLText(s[:lt]))
+			s = s[lt:]
+		}
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+
+		end := tagEnd(s)
+		if end < 0 {
+			out.WriteString(escapeHTMLText(s))
+			break
+		}
+
+		raw := s[1:end]
+		s = s[end+1:]
+
+		closing := strings.HasPrefix(raw, "/")
+		name, attrs := tagName(strings.TrimPrefix(raw, "/"))
+		if name == "" {
+			continue
+		}
+
+		if _, ok := skippedTags[name]; ok && !closing {
+			endTag := "</" + name
+			idx := indexASCIIFold(s, endTag)
+			if idx < 0 {
+				break
+			}
+			s = s[idx:]
+			if gt := strings.IndexByte(s, '>'); gt >= 0 {
+				s = s[gt+1:]
+			} else {
+				break
+			}
+			continue
+		}
+
+		tag, ok := formattedTextTags[name]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case tag == "br":
+			out.WriteString("<br>")
+		case closing:
+			closeTag(tag)
+		case tag == "a":
+			href := linkHref(attrs)
+			if href == "" {
+				continue
+			}
+			out.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+			stack = append(stack, tag)
+		default:
+			out.WriteString("<" + tag + ">")
+			stack = append(stack, tag)
+		}
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		out.WriteString("</" + stack[i] + ">")
+	}
+
+	return strings.TrimSpace(out.String())
+}
+
+// indexASCIIFold is like strings.Index, but ignores ASCII case. Unlike searching in strings.ToLower(s),
+// the index is valid for s, since lowering could change byte length of non-ASCII characters.
+func indexASCIIFold(s, lowerSubstr string) int {
+	for i := 0; i+len(lowerSubstr) <= len(s); i++ {
+		j := 0
+		for ; j < len(lowerSubstr); j++ {
+			c := s[i+j]
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			if c != lowerSubstr[j] {
+				break
+			}
+		}
+		if j == len(lowerSubstr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// escapeHTMLText normalizes entities and whitespace of the HTML text node and escapes it back.
+func escapeHTMLText(s string) string {
+	return html.EscapeString(whitespaceRe.ReplaceAllString(html.UnescapeString(s), " "))
+}
+
+// tagEnd returns the index of '>' which closes the tag at the beginning of s, ignoring quoted attribute values.
+func tagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '>':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// tagName splits the tag content into lowercased name and attributes.
+func tagName(raw string) (string, string) {
+	i := 0
+	for i < len(raw) {
+		c := raw[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			break
+		}
+		i++
+	}
+
+	return strings.ToLower(raw[:i]), raw[i:]
+}
+
+// linkHref returns a href attribute value if it uses a safe scheme.
+func linkHref(attrs string) string {
+	m := hrefRe.FindStringSubmatch(attrs)
+	if m == nil {
+		return ""
+	}
+
+	return safeURL(html.UnescapeString(m[1] + m[2] + m[3]))
+}
+
+// safeURL returns trimmed URL if it uses http, https or mailto scheme and empty string otherwise.
+func safeURL(s string) string {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, scheme := range []string{"http://", "https://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// MarkdownToFormattedText renders a small Markdown subset to Pyrus formatted text.
+// Supported are paragraphs, headings (rendered as bold paragraphs), unordered and ordered lists,
+// **bold**, *italic*, ~~strikethrough~~, `code` spans, [links](https://example.org) and backslash escapes.
+// Unlike classic Markdown a single line break inside a paragraph is kept as a line break.
+// Any HTML in the source is escaped, so the result is always safe.
+func MarkdownToFormattedText(s string) string {
+	var (
+		out       strings.Builder
+		paragraph []string
+		listTag   string
+	)
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		out.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				out.WriteString("<br>")
+			}
+			out.WriteString(renderMarkdownInline(line))
+		}
+		out.WriteString("</p>")
+		paragraph = nil
+	}
+	flushList := func() {
+		if listTag == "" {
+			return
+		}
+		out.WriteString("</" + listTag + ">")
+		listTag = ""
+	}
+	listItem := func(tag, text string) {
+		flushParagraph()
+		if listTag != tag {
+			flushList()
+			out.WriteString("<" + tag + ">")
+			listTag = tag
+		}
+		out.WriteString("<li>" + renderMarkdownInline(text) + "</li>")
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			flushList()
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			flushList()
+			out.WriteString("<p><b>" + renderMarkdownInline(m[1]) + "</b></p>")
+			continue
+		}
+		if m := mdUnorderedItemRe.FindStringSubmatch(line); m != nil {
+			listItem("ul", m[1])
+			continue
+		}
+		if m := mdOrderedItemRe.FindStringSubmatch(line); m != nil {
+			listItem("ol", m[1])
+			continue
+		}
+
+		flushList()
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+	flushParagraph()
+	flushList()
+
+	return out.String()
+}
+
+// EscapeMarkdown escapes Markdown control characters, so untrusted input (client names, email subjects, etc.)
+// can be safely interpolated into templates rendered by MarkdownToFormattedText.
+func EscapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownSpecials, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// renderMarkdownInline renders inline Markdown elements of a single line.
+func renderMarkdownInline(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(markdownSpecials, s[i+1]) >= 0:
+			out.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				out.WriteString(html.EscapeString(s[i+1 : i+1+end]))
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if text, href, n := markdownLink(s[i:]); n > 0 {
+				if href = safeURL(href); href != "" {
+					out.WriteString(`<a href="` + html.EscapeString(href) + `">` + renderMarkdownInline(text) + "</a>")
+				} else {
+					out.WriteString(renderMarkdownInline(text))
+				}
+				i += n
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			if inner, n := markdownSpan(s, i, s[i:i+2]); n > 0 {
+				out.WriteString("<b>" + renderMarkdownInline(inner) + "</b>")
+				i += n
+				continue
+			}
+		case strings.HasPrefix(s[i:], "~~"):
+			if inner, n := markdownSpan(s, i, "~~"); n > 0 {
+				out.WriteString("<s>" + renderMarkdownInline(inner) + "</s>")
+				i += n
+				continue
+			}
+		case c == '*' || c == '_':
+			if inner, n := markdownSpan(s, i, s[i:i+1]); n > 0 {
+				out.WriteString("<i>" + renderMarkdownInline(inner) + "</i>")
+				i += n
+				continue
+			}
+		}
+
+		out.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+
+	return out.String()
+}
+
+// markdownSpan finds the emphasis span which starts at s[i] with the delimiter.
+// It returns the span content and the length of the whole span including delimiters or zero if there is no span.
+// Underscores inside words (like snake_case) are not treated as delimiters.
+func markdownSpan(s string, i int, delim string) (string, int) {
+	start := i + len(delim)
+	if start >= len(s) || s[start] == ' ' {
+		return "", 0
+	}
+	if delim[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0
+	}
+
+	for j := start + 1; j+len(delim) <= len(s); j++ {
+		if s[j-1] == '\\' || !strings.HasPrefix(s[j:], delim) || s[j-1] == ' ' {
+			continue
+		}
+		end := j + len(delim)
+		if len(delim) == 1 && end < len(s) && s[end] == delim[0] {
+			// Skip nested strong delimiter like in *a **b** c*.
+			j++
+			continue
+		}
+		if delim[0] == '_' && end < len(s) && isWordByte(s[end]) {
+			continue
+		}
+
+		return s[start:j], end - i
+	}
+
+	return "", 0
+}
+
+// markdownLink parses [text](href) at the beginning of s and returns its text, href and length.
+func markdownLink(s string) (string, string, int) {
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return "", "", 0
+	}
+	closeHref := strings.IndexByte(s[closeText+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0
+	}
+
+	return s[1:closeText], s[closeText+2 : closeText+2+closeHref], closeText + 3 + closeHref
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,110 @@
+package pyrus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToFormattedText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "supported tags",
+			in:   "<p>Hello, <strong>world</strong> and <em>you</em>!</p>",
+			out:  "<p>Hello, <b>world</b> and <i>you</i>!</p>",
+		},
+		{
+			name: "lists and breaks",
+			in:   "<ul>\n  <li>One</li>\n  <li>Two<br/>lines</li>\n</ul>",
+			out:  "<ul> <li>One</li> <li>Two<br>lines</li> </ul>",
+		},
+		{
+			name: "links",
+			in:   `<a href="https://pyrus.com/?a=1&amp;b=2" target="_blank">Pyrus</a> <a href='javascript:alert(1)'>bad</a>`,
+			out:  `<a href="https://pyrus.com/?a=1&amp;b=2">Pyrus</a> bad`,
+		},
+		{
+			name: "unsupported and dangerous tags",
+			in:   `<html><head><title>Mail</title><style>p {}</style></head><body><div class="x">Text<script>alert("x")</script></div><!-- comment --></body></html>`,
+			out:  "Text",
+		},
+		{
+			name: "unbalanced tags",
+			in:   "<b><i>bold italic</b> text",
+			out:  "<b><i>bold italic</i></b> text",
+		},
+		{
+			name: "entities and plain text",
+			in:   "1 &lt; 2 &amp;&amp; 3 > 2",
+			out:  "1 &lt; 2 &amp;&amp; 3 &gt; 2",
+		},
+		{
+			name: "skipped tag with text changing length when lowered",
+			in:   "<script>" + strings.Repeat("Ⱥ", 30) + "</script>after",
+			out:  "after",
+		},
+		{
+			name: "skipped tag closed in upper case",
+			in:   "<style>" + strings.Repeat("Ⱥ", 30) + "</STYLE>after",
+			out:  "after",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.out, HTMLToFormattedText(tt.in))
+		})
+	}
+}
+
+func TestMarkdownToFormattedText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "inline",
+			in:   "**Bold**, *italic*, _also italic_, ~~strike~~ and `**code**`",
+			out:  "<p><b>Bold</b>, <i>italic</i>, <i>also italic</i>, <s>strike</s> and **code**</p>",
+		},
+		{
+			name: "paragraphs and line breaks",
+			in:   "First line\nsecond line\n\nNew paragraph",
+			out:  "<p>First line<br>second line</p><p>New paragraph</p>",
+		},
+		{
+			name: "headings and lists",
+			in:   "# Task\n- one\n- two\n\n1. first\n2. second",
+			out:  "<p><b>Task</b></p><ul><li>one</li><li>two</li></ul><ol><li>first</li><li>second</li></ol>",
+		},
+		{
+			name: "links",
+			in:   "[Pyrus](https://pyrus.com) and [bad](javascript:void)",
+			out:  `<p><a href="https://pyrus.com">Pyrus</a> and bad</p>`,
+		},
+		{
+			name: "html is escaped",
+			in:   "<script>alert('x')</script> & snake_case_name",
+			out:  "<p>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; &amp; snake_case_name</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.out, MarkdownToFormattedText(tt.in))
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	untrusted := "**Иван** [link](https://example.org) - 1. _x_"
+	assert.Equal(t, `\*\*Иван\*\* \[link\]\(https://example\.org\) \- 1\. \_x\_`, EscapeMarkdown(untrusted))
+	assert.Equal(t, "<p>Hello, **Иван** [link](https://example.org) - 1. _x_!</p>", MarkdownToFormattedText("Hello, "+EscapeMarkdown(untrusted)+"!"))
+	assert.Equal(t, "<p>- item</p>", MarkdownToFormattedText(EscapeMarkdown("- item")))
+}
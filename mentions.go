@@ -0,0 +1,74 @@
+package pyrus
+
+import "strings"
+
+// MentionBuilder helps to compose a comment text with mentions of persons.
+// It renders each mentioned person as "@First Last" in the text and collects their ids for the mentions list.
+type MentionBuilder struct {
+	text     strings.Builder
+	mentions []int
+	seen     map[int]struct{}
+}
+
+// NewMentionBuilder returns an empty MentionBuilder.
+func NewMentionBuilder() *MentionBuilder {
+	return &MentionBuilder{
+		seen: make(map[int]struct{}),
+	}
+}
+
+// Text appends plain text.
+func (b *MentionBuilder) Text(s string) *MentionBuilder {
+	b.text.WriteString(s)
+	return b
+}
+
+// Mention appends a mention of the person. Persons without id are rendered in the text,
+// but can't be added to the mentions list.
+func (b *MentionBuilder) Mention(p *Person) *MentionBuilder {
+	if p == nil {
+		return b
+	}
+
+	b.text.WriteString("@" + mentionName(p))
+	if p.ID != 0 {
+		if _, ok := b.seen[p.ID]; !ok {
+			b.seen[p.ID] = struct{}{}
+			b.mentions = append(b.mentions, p.ID)
+		}
+	}
+
+	return b
+}
+
+// Mentions appends mentions of all persons separated by comma.
+func (b *MentionBuilder) Mentions(persons ...*Person) *MentionBuilder {
+	for i, p := range persons {
+		if i > 0 {
+			b.text.WriteString(", ")
+		}
+		b.Mention(p)
+	}
+
+	return b
+}
+
+// Build returns the composed text and ids of mentioned persons.
+func (b *MentionBuilder) Build() (string, []int) {
+	return b.text.String(), b.mentions
+}
+
+// Apply sets the composed text and mentions to the comment request.
+func (b *MentionBuilder) Apply(req *TaskCommentRequest) *TaskCommentRequest {
+	req.Text, req.Mentions = b.Build()
+	return req
+}
+
+func mentionName(p *Person) string {
+	name := strings.TrimSpace(p.FirstName + " " + p.LastName)
+	if name == "" {
+		name = p.Email
+	}
+
+	return name
+}
@@ -0,0 +1,164 @@
+package pyrus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// WithRootCAs allows to verify API certificates with own CA pool, e.g. for on-premise installations with a private CA.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		c.rootCAs = pool
+	}
+}
+
+// WithCACertFile allows to trust CA certificates from the PEM bundle in addition to the system pool.
+// The file is read by NewClient.
+func WithCACertFile(path string) Option {
+	return func(c *Client) {
+		c.caCertFiles = append(c.caCertFiles, path)
+	}
+}
+
+// WithInsecureSkipVerify disables verification of API certificates.
+// Use it only in test labs, it makes connections vulnerable to interception.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.insecureSkipVerify = true
+	}
+}
+
+// WithClientCertificate allows to authenticate with the PEM encoded certificate and key,
+// e.g. to a mutual TLS gateway in front of Pyrus. Other settings of the transport are kept.
+func WithClientCertificate(certPEM, keyPEM []byte) Option {
+	return func(c *Client) {
+		c.clientCertPEM = certPEM
+		c.clientKeyPEM = keyPEM
+	}
+}
+
+// WithClientCertificateFile is like WithClientCertificate, but reads the certificate and the key from PEM files.
+// The files are read by NewClient.
+func WithClientCertificateFile(certFile, keyFile string) Option {
+	return func(c *Client) {
+		c.clientCertFile = certFile
+		c.clientKeyFile = keyFile
+	}
+}
+
+// WithFileBaseURL allows to upload and download files via another host,
+// e.g. for on-premise installations with a separate file storage.
+func WithFileBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.fileBaseURL = baseURL
+	}
+}
+
+// WithAuthBaseURL allows to acquire access tokens from another host than the API, e.g. from the accounts endpoint.
+// The path /auth is appended to the URL.
+func WithAuthBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.authBaseURL = baseURL
+	}
+}
+
+// configureTLS applies TLS options to the transport of the HTTP client.
+// Transport is cloned, so the client passed to WithHTTPClient and http.DefaultClient are not modified.
+func (c *Client) configureTLS() error {
+	if c.rootCAs == nil && len(c.caCertFiles) == 0 && !c.insecureSkipVerify && !c.hasClientCertificate() {
+		return nil
+	}
+
+	pool := c.rootCAs
+	if len(c.caCertFiles) > 0 {
+		if pool == nil {
+			systemPool, err := x509.SystemCertPool()
+			if err != nil || systemPool == nil {
+				systemPool = x509.NewCertPool()
+			}
+			pool = systemPool
+		}
+		for _, path := range c.caCertFiles {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if !pool.AppendCertsFromPEM(b) {
+				return fmt.Errorf("no certificates found in %s", path)
+			}
+		}
+	}
+
+	transport := http.DefaultTransport
+	if c.httpClient.Transport != nil {
+		transport = c.httpClient.Transport
+	}
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return errors.New("TLS options require *http.Transport")
+	}
+	t = t.Clone()
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	if pool != nil {
+		t.TLSClientConfig.RootCAs = pool
+	}
+	if c.insecureSkipVerify {
+		t.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec
+	}
+	if c.hasClientCertificate() {
+		cert, err := c.clientCertificate()
+		if err != nil {
+			return err
+		}
+		t.TLSClientConfig.Certificates = append(t.TLSClientConfig.Certificates, cert)
+	}
+
+	hc := *c.httpClient
+	hc.Transport = t
+	c.httpClient = &hc
+
+	return nil
+}
+
+func (c *Client) hasClientCertificate() bool {
+	return c.clientCertPEM != nil || c.clientKeyPEM != nil || c.clientCertFile != "" || c.clientKeyFile != ""
+}
+
+// clientCertificate parses the certificate of WithClientCertificate or WithClientCertificateFile.
+func (c *Client) clientCertificate() (tls.Certificate, error) {
+	if c.clientCertFile != "" || c.clientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.clientCertFile, c.clientKeyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		return cert, nil
+	}
+
+	cert, err := tls.X509KeyPair(c.clientCertPEM, c.clientKeyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+// requestBaseURLFor returns the base URL of the path, taking WithAuthBaseURL and WithFileBaseURL into account.
+func (c *Client) requestBaseURLFor(ctx context.Context, path string) string {
+	if c.authBaseURL != "" && path == "/auth" {
+		return strings.TrimRight(c.authBaseURL, "/")
+	}
+	if c.fileBaseURL != "" && strings.HasPrefix(path, "/files/") {
+		return strings.TrimRight(c.fileBaseURL, "/")
+	}
+
+	return c.requestBaseURL(ctx)
+}
@@ -0,0 +1,195 @@
+package pyrus
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_onPremise(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/pyrus/api/v4/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/pyrus/api/v4/profile":
+			w.Write([]byte(`{"person_id":1}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	files := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/files/download/7" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Disposition", `attachment; filename="report.txt"`)
+		w.Write([]byte("report")) //nolint:errcheck
+	}))
+	defer files.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	var pemBytes []byte
+	for _, ts := range []*httptest.Server{api, files} {
+		pemBytes = append(pemBytes, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})...)
+	}
+	require.NoError(t, os.WriteFile(bundle, pemBytes, 0o600))
+
+	c, err := NewClient("login", "key",
+		WithBaseURL(api.URL+"/pyrus/api/v4"),
+		WithFileBaseURL(files.URL+"/storage"),
+		WithCACertFile(bundle),
+	)
+	require.NoError(t, err)
+
+	_, err = c.Profile()
+	require.NoError(t, err)
+
+	file, err := c.DownloadFile(7)
+	require.NoError(t, err)
+	assert.Equal(t, "report.txt", file.Filename)
+	assert.Equal(t, []byte("report"), file.RawFile)
+
+	// certificates of the lab are not trusted without CA bundle
+	c, err = NewClient("login", "key", WithBaseURL(api.URL+"/pyrus/api/v4"))
+	require.NoError(t, err)
+	_, err = c.Profile()
+	assert.Error(t, err)
+
+	c, err = NewClient("login", "key", WithBaseURL(api.URL+"/pyrus/api/v4"), WithInsecureSkipVerify())
+	require.NoError(t, err)
+	_, err = c.Profile()
+	assert.NoError(t, err)
+	if cfg := http.DefaultTransport.(*http.Transport).TLSClientConfig; cfg != nil {
+		assert.False(t, cfg.InsecureSkipVerify)
+	}
+
+	_, err = NewClient("login", "key", WithCACertFile(filepath.Join(t.TempDir(), "missing.pem")))
+	assert.Error(t, err)
+}
+
+func TestWithAuthBaseURL(t *testing.T) {
+	accounts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/auth" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+	}))
+	defer accounts.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/profile" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"person_id":1}`)) //nolint:errcheck
+	}))
+	defer api.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(api.URL+"/v4"), WithAuthBaseURL(accounts.URL+"/v4/"))
+	require.NoError(t, err)
+
+	_, err = c.Profile()
+	assert.NoError(t, err)
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bot"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	gateway := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/v4/profile":
+			w.Write([]byte(`{"person_id":1}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	gateway.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	gateway.StartTLS()
+	defer gateway.Close()
+
+	t.Run("pem", func(t *testing.T) {
+		c, err := NewClient("login", "key", WithBaseURL(gateway.URL+"/v4"), WithInsecureSkipVerify(),
+			WithClientCertificate(certPEM, keyPEM))
+		require.NoError(t, err)
+
+		_, err = c.Profile()
+		assert.NoError(t, err)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+		require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+		require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+		c, err := NewClient("login", "key", WithBaseURL(gateway.URL+"/v4"), WithInsecureSkipVerify(),
+			WithClientCertificateFile(certFile, keyFile))
+		require.NoError(t, err)
+
+		_, err = c.Profile()
+		assert.NoError(t, err)
+
+		_, err = NewClient("login", "key", WithClientCertificateFile(filepath.Join(dir, "missing.pem"), keyFile))
+		assert.Error(t, err)
+	})
+
+	t.Run("gateway rejects clients without certificate", func(t *testing.T) {
+		c, err := NewClient("login", "key", WithBaseURL(gateway.URL+"/v4"), WithInsecureSkipVerify())
+		require.NoError(t, err)
+
+		_, err = c.Profile()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := NewClient("login", "key", WithClientCertificate(certPEM, []byte("invalid")))
+		assert.Error(t, err)
+	})
+}
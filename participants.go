@@ -0,0 +1,95 @@
+package pyrus
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// AddParticipants adds persons to the task participants with a single comment.
+// Before sending it cross-checks persons against the contact list: anyone outside the organization of the current user
+// is rejected locally with ErrCannotAddExternalUser instead of failing on the API side.
+func (c *Client) AddParticipants(taskID int, persons ...*Person) (*TaskResponse, error) {
+	if len(persons) == 0 {
+		return nil, errors.New("at least one person is required")
+	}
+
+	if err := c.checkInternalPersons(persons); err != nil {
+		return nil, err
+	}
+
+	return c.CommentTask(taskID, &TaskCommentRequest{
+		ParticipantsAdded: persons,
+	})
+}
+
+// RemoveParticipants removes persons from the task participants with a single comment.
+func (c *Client) RemoveParticipants(taskID int, persons ...*Person) (*TaskResponse, error) {
+	if len(persons) == 0 {
+		return nil, errors.New("at least one person is required")
+	}
+
+	return c.CommentTask(taskID, &TaskCommentRequest{
+		ParticipantsRemoved: persons,
+	})
+}
+
+// checkInternalPersons makes sure that every person belongs to the organization of the current user.
+func (c *Client) checkInternalPersons(persons []*Person) error {
+	profile, err := c.Profile()
+	if err != nil {
+		return err
+	}
+
+	contacts, err := c.Contacts(false)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[int]struct{})
+	emails := make(map[string]struct{})
+	for _, org := range contacts.Organizations {
+		if org.ID != profile.OrganizationID {
+			continue
+		}
+
+		for _, p := range org.Persons {
+			ids[p.ID] = struct{}{}
+			if p.Email != "" {
+				emails[strings.ToLower(p.Email)] = struct{}{}
+			}
+		}
+		for _, r := range org.Roles {
+			ids[r.ID] = struct{}{}
+		}
+	}
+
+	for _, p := range persons {
+		if p == nil {
+			return errors.New("person cannot be nil")
+		}
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		if p.ID != 0 {
+			if _, ok := ids[p.ID]; ok {
+				continue
+			}
+
+			return Error{
+				Code:        ErrCannotAddExternalUser,
+				Description: "person " + strconv.Itoa(p.ID) + " is not a member of the organization",
+			}
+		}
+
+		if _, ok := emails[strings.ToLower(p.Email)]; !ok {
+			return Error{
+				Code:        ErrCannotAddExternalUser,
+				Description: "person " + p.Email + " is not a member of the organization",
+			}
+		}
+	}
+
+	return nil
+}
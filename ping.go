@@ -0,0 +1,37 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Ping performs a cheap authenticated request and classifies its result, e.g. for readiness probes.
+// The error is nil only for PingStatusOK.
+func (c *Client) Ping(ctx context.Context) (PingStatus, error) {
+	var profile ProfileResponse
+	err := c.performRequestContext(ctx, http.MethodGet, "/profile", nil, nil, &profile)
+
+	return pingStatus(err), err
+}
+
+func pingStatus(err error) PingStatus {
+	if err == nil {
+		return PingStatusOK
+	}
+
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return PingStatusAuthFailure
+	case errors.Is(err, ErrRateLimited):
+		return PingStatusRateLimited
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return PingStatusNetworkFailure
+	}
+
+	return PingStatusFailure
+}
@@ -0,0 +1,59 @@
+package pyrus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Ping(t *testing.T) {
+	var profileStatus int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/profile":
+			switch profileStatus {
+			case http.StatusOK:
+				w.Write([]byte(`{"person_id":1}`)) //nolint:errcheck
+			case http.StatusForbidden:
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error_code":"access_denied","error":"Access denied"}`)) //nolint:errcheck
+			case http.StatusTooManyRequests:
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"error_code":"too_many_requests","error":"Too many requests"}`)) //nolint:errcheck
+			case http.StatusGatewayTimeout:
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		status int
+		want   PingStatus
+	}{
+		{http.StatusOK, PingStatusOK},
+		{http.StatusForbidden, PingStatusAuthFailure},
+		{http.StatusTooManyRequests, PingStatusRateLimited},
+		{http.StatusGatewayTimeout, PingStatusNetworkFailure},
+	} {
+		profileStatus = tc.status
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		status, err := c.Ping(ctx)
+		cancel()
+
+		assert.Equal(t, tc.want, status, tc.status)
+		assert.Equal(t, tc.want == PingStatusOK, err == nil, tc.status)
+	}
+}
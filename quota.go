@@ -0,0 +1,98 @@
+package pyrus
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuotaStatus contains rate limit state parsed from the headers of the latest response.
+// Pyrus limits requests per 10 minutes, headers are parsed when they are present only.
+type QuotaStatus struct {
+	// Known is false until any response with rate limit headers is received.
+	Known     bool
+	Limit     int
+	Remaining int
+	// Reset is the time when the quota is restored, zero if unknown.
+	Reset time.Time
+	// RetryAfter is set for 429 responses with Retry-After header.
+	RetryAfter time.Duration
+	UpdatedAt  time.Time
+}
+
+// WithQuotaHook allows to receive quota status after every response containing rate limit headers,
+// e.g. to export it as metrics.
+func WithQuotaHook(fn func(QuotaStatus)) Option {
+	return func(c *Client) {
+		c.quotaHook = fn
+	}
+}
+
+// QuotaStatus returns rate limit state of the latest response.
+func (c *Client) QuotaStatus() QuotaStatus {
+	c.quotaMu.RLock()
+	defer c.quotaMu.RUnlock()
+
+	return c.quota
+}
+
+// updateQuota parses rate limit headers of the response.
+func (c *Client) updateQuota(h http.Header) {
+	status, ok := parseQuota(h, c.clock.Now())
+	if !ok {
+		return
+	}
+
+	c.quotaMu.Lock()
+	c.quota = status
+	c.quotaMu.Unlock()
+
+	if c.quotaHook != nil {
+		c.quotaHook(status)
+	}
+}
+
+func parseQuota(h http.Header, now time.Time) (QuotaStatus, bool) {
+	status := QuotaStatus{UpdatedAt: now}
+
+	limit, hasLimit := headerInt(h, "X-RateLimit-Limit", "RateLimit-Limit")
+	remaining, hasRemaining := headerInt(h, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	reset, hasReset := headerInt(h, "X-RateLimit-Reset", "RateLimit-Reset")
+	retryAfter, hasRetryAfter := headerInt(h, "Retry-After")
+	if !hasLimit && !hasRemaining && !hasReset && !hasRetryAfter {
+		return status, false
+	}
+
+	status.Known = true
+	status.Limit = limit
+	status.Remaining = remaining
+	if hasReset {
+		// Reset could be either unix timestamp or number of seconds
+		if reset > 1e9 {
+			status.Reset = time.Unix(int64(reset), 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	if hasRetryAfter {
+		status.RetryAfter = time.Duration(retryAfter) * time.Second
+	}
+
+	return status, true
+}
+
+// headerInt returns the integer value of the first present header.
+func headerInt(h http.Header, keys ...string) (int, bool) {
+	for _, k := range keys {
+		v := strings.TrimSpace(h.Get(k))
+		if v == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err == nil {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
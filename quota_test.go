@@ -0,0 +1,59 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_QuotaStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.Header().Set("X-RateLimit-Reset", "600")
+			w.Write([]byte(`{"forms":[]}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	var hooked []QuotaStatus
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithQuotaHook(func(s QuotaStatus) {
+		hooked = append(hooked, s)
+	}))
+	require.NoError(t, err)
+	assert.False(t, c.QuotaStatus().Known)
+
+	_, err = c.Forms()
+	require.NoError(t, err)
+
+	status := c.QuotaStatus()
+	assert.True(t, status.Known)
+	assert.Equal(t, 5000, status.Limit)
+	assert.Equal(t, 4999, status.Remaining)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), status.Reset, time.Minute)
+	assert.Len(t, hooked, 1)
+}
+
+func TestParseQuota(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	h := http.Header{}
+	_, ok := parseQuota(h, now)
+	assert.False(t, ok)
+
+	h.Set("Retry-After", "30")
+	h.Set("RateLimit-Reset", "1600000600")
+	status, ok := parseQuota(h, now)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, status.RetryAfter)
+	assert.Equal(t, now.Add(10*time.Minute), status.Reset)
+}
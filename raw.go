@@ -0,0 +1,39 @@
+package pyrus
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// WithRawResponses allows to keep the response JSON in Raw field of FormResponse, FormsResponse, FormRegisterResponse,
+// TaskResponse, AnnouncementResponse, CatalogResponse, CatalogsResponse and ProfileResponse,
+// so fields unknown to the library could be inspected or forwarded without a second request.
+func WithRawResponses() Option {
+	return func(c *Client) {
+		c.rawResponses = true
+	}
+}
+
+// attachRaw sets Raw field of the response to the copy of the body.
+func attachRaw(respBody interface{}, body []byte) {
+	raw := json.RawMessage(bytes.TrimSpace(append([]byte(nil), body...)))
+
+	switch r := respBody.(type) {
+	case *FormResponse:
+		r.Raw = raw
+	case *FormsResponse:
+		r.Raw = raw
+	case *FormRegisterResponse:
+		r.Raw = raw
+	case *TaskResponse:
+		r.Raw = raw
+	case *AnnouncementResponse:
+		r.Raw = raw
+	case *CatalogResponse:
+		r.Raw = raw
+	case *CatalogsResponse:
+		r.Raw = raw
+	case *ProfileResponse:
+		r.Raw = raw
+	}
+}
@@ -0,0 +1,46 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRawResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/tasks/1":
+			w.Write([]byte(`{"task":{"id":1,"brand_new_field":{"a":1}}}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithRawResponses())
+	require.NoError(t, err)
+
+	task, err := c.Task(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Task.ID)
+
+	var raw struct {
+		Task struct {
+			BrandNewField map[string]int `json:"brand_new_field"`
+		} `json:"task"`
+	}
+	require.NoError(t, json.Unmarshal(task.Raw, &raw))
+	assert.Equal(t, 1, raw.Task.BrandNewField["a"])
+
+	c, err = NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	task, err = c.Task(1)
+	require.NoError(t, err)
+	assert.Nil(t, task.Raw)
+}
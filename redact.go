@@ -0,0 +1,60 @@
+package pyrus
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	secretHeaderRe = regexp.MustCompile(`(?im)^((?:Authorization|` + WebhookSignatureHeader + `):\s*)(?:Bearer\s+)?[^\r\n]+`)
+	secretJSONRe   = regexp.MustCompile(`("(?:security_key|access_token)"\s*:\s*")[^"]*(")`)
+	bearerRe       = regexp.MustCompile(`(Bearer\s+)[^\s"]+`)
+)
+
+// RedactSecrets replaces credentials in the text: Authorization and webhook signature headers,
+// bearer tokens and security_key or access_token JSON values.
+func RedactSecrets(s string) string {
+	s = secretHeaderRe.ReplaceAllString(s, "${1}"+redacted)
+	s = secretJSONRe.ReplaceAllString(s, "${1}"+redacted+"${2}")
+	return bearerRe.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// redact replaces credentials of the client in the text, including their occurrences outside of known patterns.
+func (c *Client) redact(s string) string {
+	c.mu.RLock()
+	secrets := append([]string{c.securityKey, c.accessToken}, c.webhookSecrets...)
+	c.mu.RUnlock()
+
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return RedactSecrets(s)
+}
+
+// redactingLogger redacts credentials from errors before passing them to the logger.
+// It's applied to any Logger passed to Client, so secrets can't leak through logs.
+type redactingLogger struct {
+	next   Logger
+	redact func(s string) string
+}
+
+func (l *redactingLogger) Error(msg string, err error) {
+	if err != nil {
+		err = &redactedError{msg: l.redact(err.Error())}
+	}
+	l.next.Error(l.redact(msg), err)
+}
+
+// redactedError hides the original error, so loggers can't print its fields or wrapped errors.
+type redactedError struct {
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
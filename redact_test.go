@@ -0,0 +1,69 @@
+package pyrus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leakyTransport fails every request with an error containing its credentials.
+type leakyTransport struct{}
+
+func (leakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/auth") {
+		b, _ := io.ReadAll(req.Body)
+		if strings.Contains(string(b), "fail") {
+			return nil, fmt.Errorf("connection reset, body: %s", b)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"opaque-token"}`)),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("connection reset, token opaque-token, header %s", req.Header.Get("Authorization"))
+}
+
+func TestRedactingLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	c, err := NewClient("login", "fail-secret-key", WithLogger(logger), WithHTTPClient(&http.Client{Transport: leakyTransport{}}))
+	require.NoError(t, err)
+
+	_, err = c.Profile()
+	require.Error(t, err)
+
+	c, err = NewClient("login", "secret-key", WithLogger(logger), WithHTTPClient(&http.Client{Transport: leakyTransport{}}))
+	require.NoError(t, err)
+
+	_, err = c.Profile()
+	require.Error(t, err)
+
+	require.NotEmpty(t, logger.errs)
+	for _, err := range logger.errs {
+		assert.NotContains(t, err.Error(), "secret-key")
+		assert.NotContains(t, err.Error(), "opaque-token")
+		assert.Contains(t, err.Error(), "connection reset")
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	dump := "POST /hook HTTP/1.1\r\nX-Pyrus-Sig: abcdef\r\nAuthorization: Bearer token\r\n\r\n" +
+		`{"login":"bot@example.org","security_key": "key","access_token":"token"}`
+
+	assert.Equal(t, "POST /hook HTTP/1.1\r\nX-Pyrus-Sig: [REDACTED]\r\nAuthorization: [REDACTED]\r\n\r\n"+
+		`{"login":"bot@example.org","security_key": "[REDACTED]","access_token":"[REDACTED]"}`, RedactSecrets(dump))
+	assert.Equal(t, "invalid Bearer [REDACTED] header", RedactSecrets("invalid Bearer abc.def header"))
+}
+
+func TestClient_redact(t *testing.T) {
+	c, err := NewClient("login", "secret-key", WithWebhookSecrets("old-secret"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "keys [REDACTED] and [REDACTED]", c.redact("keys secret-key and old-secret"))
+}
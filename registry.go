@@ -0,0 +1,117 @@
+package pyrus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldID returns id of the form field found by id, name or code, including fields nested
+// into titles, choice options and table columns.
+func (r *FormResponse) FieldID(key string) (int, error) {
+	f := r.findDefinitionField(key, nil)
+	if f == nil {
+		return 0, fmt.Errorf("field %q not found", key)
+	}
+
+	return f.ID, nil
+}
+
+// FieldIDs resolves field names or codes to ids of the form, see FieldID.
+func (r *FormResponse) FieldIDs(keys ...string) ([]int, error) {
+	ids := make([]int, 0, len(keys))
+	for _, key := range keys {
+		id, err := r.FieldID(key)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// SelectFields populates FieldIDs with fields of the form found by names or codes,
+// so registry requests are portable between forms with the same structure but different field ids.
+func (r *RegistryRequest) SelectFields(form *FormResponse, keys ...string) error {
+	ids, err := form.FieldIDs(keys...)
+	if err != nil {
+		return err
+	}
+	r.FieldIDs = ids
+
+	return nil
+}
+
+// FilterSteps limits the registry to tasks on the workflow steps of the form.
+// It returns Error with ErrStepFieldDoesNotExists code if the form has no step field,
+// so the request fails on the client side instead of the API.
+func (r *RegistryRequest) FilterSteps(form *FormResponse, steps ...int) error {
+	f := form.fieldOfType(FieldTypeStep)
+	if f == nil {
+		return localError(ErrStepFieldDoesNotExists, ErrStepFieldDoesNotExists.Description(LanguageEnglish))
+	}
+
+	values := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if _, ok := form.Steps[step]; len(form.Steps) > 0 && !ok {
+			return fmt.Errorf("form doesn't have step %d", step)
+		}
+		values = append(values, strconv.Itoa(step))
+	}
+
+	r.setFieldFilter(f.ID, strings.Join(values, ","))
+
+	return nil
+}
+
+// FilterStatus limits the registry to open or closed tasks using the status field of the form.
+func (r *RegistryRequest) FilterStatus(form *FormResponse, status StatusType) error {
+	if status != StatusTypeOpen && status != StatusTypeClosed {
+		return fmt.Errorf("unsupported status %q", status)
+	}
+
+	f := form.fieldOfType(FieldTypeStatus)
+	if f == nil {
+		return fmt.Errorf("form doesn't have %s field", FieldTypeStatus)
+	}
+
+	r.setFieldFilter(f.ID, string(status))
+
+	return nil
+}
+
+func (r *RegistryRequest) setFieldFilter(fieldID int, value string) {
+	if r.FieldFilters == nil {
+		r.FieldFilters = make(map[int]string)
+	}
+	r.FieldFilters[fieldID] = value
+}
+
+// fieldOfType returns the first field of the type in the form definition.
+func (r *FormResponse) fieldOfType(fieldType FieldType) *FormField {
+	found := definitionFields(r.Fields, func(f *FormField) bool {
+		return f.Type == fieldType
+	})
+	if len(found) == 0 {
+		return nil
+	}
+
+	return found[0]
+}
+
+// findDefinitionField searches the field of the form definition by id, name or code, optionally filtering by type.
+func (r *FormResponse) findDefinitionField(key string, match func(f *FormField) bool) *FormField {
+	id, _ := strconv.Atoi(key)
+	found := definitionFields(r.Fields, func(f *FormField) bool {
+		if match != nil && !match(f) {
+			return false
+		}
+		return f.Name == key || (id != 0 && f.ID == id) || (f.Info != nil && f.Info.Code != "" && f.Info.Code == key)
+	})
+	if len(found) == 0 {
+		return nil
+	}
+
+	return found[0]
+}
@@ -0,0 +1,69 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRequest_SelectFields(t *testing.T) {
+	var form FormResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"fields": [
+			{"id":11,"type":"text","name":"Описание","info":{"code":"description"}},
+			{"id":12,"type":"title","name":"Закупка","info":{"fields":[
+				{"id":13,"type":"money","name":"Сумма","info":{"code":"amount"}}
+			]}}
+		]
+	}`), &form))
+
+	var req RegistryRequest
+	require.NoError(t, req.SelectFields(&form, "description", "Сумма", "12"))
+	assert.Equal(t, []int{11, 13, 12}, req.FieldIDs)
+
+	assert.Error(t, req.SelectFields(&form, "Нет такого"))
+}
+
+func TestRegistryRequest_FilterSteps(t *testing.T) {
+	form := &FormResponse{
+		Steps: Steps{1: "Согласование", 2: "Оплата"},
+		Fields: []*FormField{
+			{ID: 1, Type: FieldTypeStep, Name: "Этап"},
+			{ID: 2, Type: FieldTypeStatus, Name: "Статус"},
+		},
+	}
+
+	var req RegistryRequest
+	require.NoError(t, req.FilterSteps(form, 1, 2))
+	require.NoError(t, req.FilterStatus(form, StatusTypeOpen))
+	assert.Equal(t, map[int]string{1: "1,2", 2: "open"}, req.FieldFilters)
+
+	b, err := json.Marshal(&req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fld1":"1,2","fld2":"open"}`, string(b))
+
+	assert.Error(t, req.FilterSteps(form, 3))
+	assert.Error(t, req.FilterStatus(form, "unknown"))
+
+	var apiErr Error
+	require.ErrorAs(t, req.FilterSteps(&FormResponse{}, 1), &apiErr)
+	assert.Equal(t, ErrStepFieldDoesNotExists, apiErr.Code)
+	assert.ErrorIs(t, apiErr, ErrValidation)
+}
+
+func TestRegistryRequest_Validate(t *testing.T) {
+	assert.NoError(t, RegistryRequest{}.Validate())
+	assert.NoError(t, RegistryRequest{
+		Format:    RegistryFormatCSV,
+		Delimiter: RegistryDelimiterSemicolon,
+		Encoding:  RegistryEncodingWindows1251,
+	}.Validate())
+
+	assert.Error(t, RegistryRequest{Format: "xlsx"}.Validate())
+	assert.Error(t, RegistryRequest{Format: RegistryFormatCSV, Delimiter: "."}.Validate())
+	assert.Error(t, RegistryRequest{Format: RegistryFormatCSV, Encoding: "koi8-r"}.Validate())
+	assert.Error(t, RegistryRequest{Delimiter: RegistryDelimiterComma}.Validate())
+}
@@ -0,0 +1,119 @@
+package pyrus
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventStore remembers processed events. Events are identified by the task and its last note,
+// so the same task state delivered by webhook and by Replay is processed once.
+type EventStore interface {
+	Processed(taskID, noteID int) (bool, error)
+	MarkProcessed(taskID, noteID int) error
+}
+
+// MemoryEventStore is an in-memory EventStore.
+type MemoryEventStore struct {
+	mu        sync.RWMutex
+	processed map[string]struct{}
+}
+
+// NewMemoryEventStore returns an empty MemoryEventStore.
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{
+		processed: make(map[string]struct{}),
+	}
+}
+
+// Processed reports whether the event has been marked as processed.
+func (s *MemoryEventStore) Processed(taskID, noteID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.processed[eventKey(taskID, noteID)]
+	return ok, nil
+}
+
+// MarkProcessed marks the event as processed.
+func (s *MemoryEventStore) MarkProcessed(taskID, noteID int) error {
+	s.mu.Lock()
+	s.processed[eventKey(taskID, noteID)] = struct{}{}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func eventKey(taskID, noteID int) string {
+	return strconv.Itoa(taskID) + ":" + strconv.Itoa(noteID)
+}
+
+// MarkEventProcessed marks the webhook event as processed, call it after handling every event
+// to let Replay skip it.
+func MarkEventProcessed(store EventStore, e Event) error {
+	if e.Task == nil || e.Task.Task == nil {
+		return nil
+	}
+
+	return store.MarkProcessed(e.TaskID, e.Task.LastNoteID)
+}
+
+// Replay re-reads tasks of the forms modified within the window from Registry and passes synthesized
+// "comment" events to fn for task states which have not been processed according to the store,
+// closing gaps caused by webhook outages. Events are marked processed once fn returns nil.
+func Replay(client IClient, store EventStore, from, to time.Time, fn func(Event) error, formIDs ...int) error {
+	for _, formID := range formIDs {
+		resp, err := client.Registry(formID, &RegistryRequest{
+			IncludeArchived: true,
+			ModifiedAfter:   &from,
+			ModifiedBefore:  &to,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, t := range resp.Tasks {
+			if t == nil || t.TaskHeader == nil {
+				continue
+			}
+
+			// Registry may already contain the last note, so the task could be skipped without fetching
+			if t.LastNoteID != 0 {
+				processed, err := store.Processed(t.ID, t.LastNoteID)
+				if err != nil {
+					return err
+				}
+				if processed {
+					continue
+				}
+			}
+
+			task, err := client.Task(t.ID)
+			if err != nil {
+				return err
+			}
+			if task.Task == nil || task.Task.Task == nil {
+				continue
+			}
+
+			processed, err := store.Processed(t.ID, task.Task.LastNoteID)
+			if err != nil {
+				return err
+			}
+			if processed {
+				continue
+			}
+
+			event := Event{Event: "comment", TaskID: t.ID, Task: task.Task}
+			event.setLastComments()
+			if err := fn(event); err != nil {
+				return err
+			}
+			if err := store.MarkProcessed(t.ID, task.Task.LastNoteID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
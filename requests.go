@@ -2,6 +2,7 @@ package pyrus
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"reflect"
 	"strconv"
@@ -20,6 +21,7 @@ type authRequest struct {
 // TaskRequest is necessary to create a task.
 type TaskRequest struct {
 	Text                 string        `json:"text,omitempty"`
+	FormattedText        string        `json:"formatted_text,omitempty"`
 	Responsible          *Person       `json:"responsible,omitempty"`
 	DueDate              string        `json:"due_date,omitempty"`
 	Due                  *time.Time    `json:"due,omitempty"`
@@ -86,7 +88,10 @@ func (f FormField) Validate() error {
 		&f,
 		validation.Field(&f.ID, validation.When(f.Name != "", validation.Empty.Error(bothMsg)).Else(validation.Required.Error(eitherMsg))),
 		validation.Field(&f.Name, validation.When(f.ID != 0, validation.Empty.Error(bothMsg)).Else(validation.Required.Error(eitherMsg))),
-		validation.Field(&f.Value, validation.Required),
+		validation.Field(&f.Value, validation.Required, validation.By(func(value interface{}) error {
+			_, err := CoerceFieldValue(f.Type, value)
+			return err
+		})),
 	)
 }
 
@@ -132,6 +137,8 @@ func (a Attachment) Validate() error {
 // TaskCommentRequest is necessary to create a comment in the task.
 type TaskCommentRequest struct {
 	Text                   string        `json:"text,omitempty"`
+	FormattedText          string        `json:"formatted_text,omitempty"`
+	Mentions               []int         `json:"mentions,omitempty"`
 	Subject                string        `json:"subject,omitempty"`
 	DueDate                string        `json:"due_date,omitempty"`
 	Due                    *time.Time    `json:"due,omitempty"`
@@ -156,6 +163,10 @@ type TaskCommentRequest struct {
 	CancelSchedule         bool          `json:"cancel_schedule,omitempty"`
 	Channel                *Channel      `json:"channel,omitempty"`
 	SpentMinutes           int           `json:"spent_minutes,omitempty"`
+
+	// ExpectedLastNoteID enables optimistic concurrency check: CommentTask refetches the task
+	// and returns *ConflictError if its last_note_id differs, so concurrent changes are not overwritten.
+	ExpectedLastNoteID int `json:"-"`
 }
 
 // Validate allows to validate request before sending.
@@ -186,20 +197,38 @@ func (r TaskCommentRequest) Validate() error {
 type RegistryRequest struct {
 	FieldFilters map[int]string `json:"-"`
 
-	Steps           int        `json:"steps,omitempty"`
-	IncludeArchived bool       `json:"include_archived,omitempty"`
-	FieldIDs        []int      `json:"field_ids,omitempty"`
-	Format          string     `json:"format,omitempty"`
-	Delimiter       string     `json:"delimiter,omitempty"`
-	Encoding        string     `json:"encoding,omitempty"`
-	SimpleFormat    bool       `json:"simple_format,omitempty"`
-	ModifiedBefore  *time.Time `json:"modified_before,omitempty"`
-	ModifiedAfter   *time.Time `json:"modified_after,omitempty"`
-	CreatedBefore   *time.Time `json:"created_before,omitempty"`
-	CreatedAfter    *time.Time `json:"created_after,omitempty"`
-	ClosedBefore    *time.Time `json:"closed_before,omitempty"`
-	ClosedAfter     *time.Time `json:"closed_after,omitempty"`
-	TaskIDs         []int      `json:"task_ids,omitempty"`
+	Steps           int               `json:"steps,omitempty"`
+	IncludeArchived bool              `json:"include_archived,omitempty"`
+	FieldIDs        []int             `json:"field_ids,omitempty"`
+	Format          RegistryFormat    `json:"format,omitempty"`
+	Delimiter       RegistryDelimiter `json:"delimiter,omitempty"`
+	Encoding        RegistryEncoding  `json:"encoding,omitempty"`
+	SimpleFormat    bool              `json:"simple_format,omitempty"`
+	ModifiedBefore  *time.Time        `json:"modified_before,omitempty"`
+	ModifiedAfter   *time.Time        `json:"modified_after,omitempty"`
+	CreatedBefore   *time.Time        `json:"created_before,omitempty"`
+	CreatedAfter    *time.Time        `json:"created_after,omitempty"`
+	ClosedBefore    *time.Time        `json:"closed_before,omitempty"`
+	ClosedAfter     *time.Time        `json:"closed_after,omitempty"`
+	TaskIDs         []int             `json:"task_ids,omitempty"`
+}
+
+// Validate allows to validate request before sending.
+func (r RegistryRequest) Validate() error {
+	return validation.ValidateStruct(
+		&r,
+		validation.Field(&r.Format, validation.In(RegistryFormatCSV)),
+		validation.Field(&r.Delimiter,
+			validation.When(r.Format != RegistryFormatCSV, validation.Empty.Error("delimiter requires csv format")),
+			validation.In(RegistryDelimiterComma, RegistryDelimiterSemicolon, RegistryDelimiterTab),
+		),
+		validation.Field(&r.Encoding,
+			validation.When(r.Format != RegistryFormatCSV, validation.Empty.Error("encoding requires csv format")),
+			validation.By(func(interface{}) error {
+				return validateCSVEncoding(r.Encoding)
+			}),
+		),
+	)
 }
 
 // MarshalJSON is a custom RegistryRequest marshaller that allows to merge the main struct and a map of field filters.
@@ -265,6 +294,7 @@ func (r AnnouncementRequest) Validate() error {
 	return validation.ValidateStruct(
 		&r,
 		validation.Field(&r.Text, validation.Required),
+		validation.Field(&r.Attachments, validation.Each()),
 	)
 }
 
@@ -279,12 +309,26 @@ func (r AnnouncementCommentRequest) Validate() error {
 	return validation.ValidateStruct(
 		&r,
 		validation.Field(&r.Text, validation.Required),
+		validation.Field(&r.Attachments, validation.Each()),
 	)
 }
 
+// Validate allows to validate request before sending.
+func (f NewFile) Validate() error {
+	return Attachment{
+		GUID:         f.GUID,
+		RootID:       f.RootID,
+		AttachmentID: f.AttachmentID,
+		URL:          f.URL,
+		Name:         f.Name,
+	}.Validate()
+}
+
 type fileRequest struct {
 	Filename string
 	io.Reader
+	// Size is the number of bytes in Reader or -1 if it's unknown.
+	Size int64
 }
 
 type catalogRequest struct {
@@ -351,7 +395,46 @@ type AddCallDetailsRequest struct {
 	FileGUID        string              `json:"file_guid"`
 }
 
+// Validate allows to validate request before sending.
+func (r AddCallDetailsRequest) Validate() error {
+	return validation.ValidateStruct(
+		&r,
+		validation.Field(&r.EndTime, validation.When(
+			r.StartTime != nil && r.EndTime != nil,
+			validation.By(func(interface{}) error {
+				if r.EndTime.Before(*r.StartTime) {
+					return errors.New("end_time must not be before start_time")
+				}
+				return nil
+			}),
+		)),
+		validation.Field(&r.Rating, validation.Min(1), validation.Max(5)),
+		validation.Field(&r.DisconnectParty, validation.In(
+			DisconnectPartyTypeAgent,
+			DisconnectPartyTypeClient,
+			DisconnectPartyTypeError,
+			DisconnectPartyTypeOther,
+		)),
+		validation.Field(&r.CallStatus, validation.In(
+			CallStatusTypeAnswered,
+			CallStatusTypeNoAnswer,
+			CallStatusTypeBusy,
+			CallStatusTypeError,
+			CallStatusTypeOther,
+		)),
+		validation.Field(&r.FileGUID, is.UUID),
+	)
+}
+
 type registerCallEventRequest struct {
 	EventType CallEventType `json:"event_type"`
 	Extension string        `json:"extension,omitempty"`
 }
+
+// Validate allows to validate request before sending.
+func (r registerCallEventRequest) Validate() error {
+	return validation.ValidateStruct(
+		&r,
+		validation.Field(&r.EventType, validation.Required),
+	)
+}
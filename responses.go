@@ -1,5 +1,13 @@
 package pyrus
 
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrNoMorePages is returned by NextPage methods when there are no more pages to fetch.
+var ErrNoMorePages = errors.New("no more pages")
+
 // AuthResponse represents a response from Auth method.
 type AuthResponse struct {
 	AccessToken string `json:"access_token"`
@@ -7,13 +15,16 @@ type AuthResponse struct {
 
 // FormResponse represents a response from Form method.
 type FormResponse struct {
-	ID              int            `json:"id"`
-	Name            string         `json:"name"`
-	Steps           map[int]string `json:"steps"`
-	Fields          []*FormField   `json:"fields"`
-	DeletedOrClosed bool           `json:"deleted_or_closed"`
-	PrintForms      []PrintForm    `json:"print_forms"`
-	Folder          []string       `json:"folder"`
+	ID              int          `json:"id"`
+	Name            string       `json:"name"`
+	Steps           Steps        `json:"steps"`
+	Fields          []*FormField `json:"fields"`
+	DeletedOrClosed bool         `json:"deleted_or_closed"`
+	PrintForms      []PrintForm  `json:"print_forms"`
+	Folder          []string     `json:"folder"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 type PrintForm struct {
@@ -24,22 +35,34 @@ type PrintForm struct {
 // FormsResponse represents a response from Forms method.
 type FormsResponse struct {
 	Forms []*FormResponse `json:"forms"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // FormRegisterResponse represents a response from Registry method.
 type FormRegisterResponse struct {
 	Tasks []*Task `json:"tasks"`
 	CSV   string  `json:"csv"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // TaskResponse represents a response from Task method.
 type TaskResponse struct {
 	Task *TaskWithComments `json:"task"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // AnnouncementResponse represents a response from Announcement method.
 type AnnouncementResponse struct {
 	Announcement *AnnouncementWithComments `json:"announcement"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // ContactsResponse represents a response from Contacts method.
@@ -50,6 +73,9 @@ type ContactsResponse struct {
 // CatalogsResponse represents a list of available catalogs
 type CatalogsResponse struct {
 	Catalogs []*CatalogResponse `json:"catalogs"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // CatalogResponse represents a response from Catalog method.
@@ -62,15 +88,21 @@ type CatalogResponse struct {
 	ExternalVersion int              `json:"external_version"`
 	CatalogHeaders  []*CatalogHeader `json:"catalog_headers"`
 	Items           []*CatalogItem   `json:"items"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // UploadResponse represents a response from UploadFile method.
 type UploadResponse struct {
 	GUID    string `json:"guid"`
 	MD5Hash string `json:"md5_hash"`
+
+	// Deduplicated is true if the file wasn't sent, since it's found in the index of WithUploadIndex.
+	Deduplicated bool `json:"-"`
 }
 
-// DownloadResponse represents a response from DownloadFile method.
+// DownloadResponse represents a response from DownloadFile and DownloadPrintForm methods.
 type DownloadResponse struct {
 	Filename string
 	RawFile  []byte `json:"raw_file"`
@@ -81,10 +113,37 @@ type ListsResponses struct {
 	Lists []*TaskList `json:"lists"`
 }
 
-// TaskListResponse represents a response from TaskList method.
+// TaskListResponse represents a response from TaskList and Inbox methods.
 type TaskListResponse struct {
-	Tasks   []*TaskHeader `json:"tasks"`
-	HasMode bool          `json:"has_mode"`
+	Tasks []*TaskHeader `json:"tasks"`
+	// HasMore indicates that the list was truncated by item_count, use NextPage to get the rest of tasks.
+	HasMore bool `json:"has_more"`
+	// Deprecated: HasMode was a typo, it mirrors HasMore and kept for backward compatibility.
+	HasMode bool `json:"-"`
+
+	nextPage func() (*TaskListResponse, error)
+}
+
+// UnmarshalJSON is a custom unmarshaler which keeps deprecated HasMode in sync with HasMore.
+func (r *TaskListResponse) UnmarshalJSON(b []byte) error {
+	type RawTaskListResponse TaskListResponse
+	raw := (*RawTaskListResponse)(r)
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+
+	r.HasMode = r.HasMore
+	return nil
+}
+
+// NextPage returns the next tasks of the list. Tasks returned by previous pages are not repeated.
+// It returns ErrNoMorePages if the list is not truncated.
+func (r *TaskListResponse) NextPage() (*TaskListResponse, error) {
+	if !r.HasMore || r.nextPage == nil {
+		return nil, ErrNoMorePages
+	}
+
+	return r.nextPage()
 }
 
 // SyncCatalogResponse represents a response from SyncCatalog method.
@@ -114,19 +173,79 @@ type ProfileResponse struct {
 	Email          string `json:"email"`
 	Locale         string `json:"locale"`
 	OrganizationID int    `json:"organization_id"`
+
+	// Raw is the response JSON, set only with WithRawResponses.
+	Raw json.RawMessage `json:"-"`
 }
 
 // RegisterCallResponse represents a response from RegisterCall method.
 type RegisterCallResponse struct {
-	CallGUID string `json:"call_guid"`
-	TaskID   string `json:"task_id"`
+	CallGUID string    `json:"call_guid"`
+	TaskID   NumericID `json:"task_id"`
 }
 
 // Event represents an event received from webhook.
+// Depending on Subject it contains either task or announcement.
 type Event struct {
-	Event       string            `json:"event"`
-	AccessToken string            `json:"access_token"`
-	TaskID      int               `json:"task_id"`
-	UserID      int               `json:"user_id"`
-	Task        *TaskWithComments `json:"task"`
+	Event          string                    `json:"event"`
+	AccessToken    string                    `json:"access_token"`
+	TaskID         int                       `json:"task_id"`
+	AnnouncementID int                       `json:"announcement_id"`
+	UserID         int                       `json:"user_id"`
+	Task           *TaskWithComments         `json:"task"`
+	Announcement   *AnnouncementWithComments `json:"announcement"`
+
+	// LastComment is the latest comment of the task, usually the one the event is about.
+	LastComment *TaskComment `json:"-"`
+	// LastAnnouncementComment is the latest comment of the announcement.
+	LastAnnouncementComment *AnnouncementComment `json:"-"`
+	// SchemaError is *WebhookSchemaError of the invalid payload accepted with WebhookSchemaFlag.
+	SchemaError error `json:"-"`
+
+	// client is the client which received the webhook, see Client method.
+	client *Client
+}
+
+// Subject returns what the event is about.
+func (e Event) Subject() EventSubject {
+	if e.Announcement != nil || e.AnnouncementID != 0 {
+		return EventSubjectAnnouncement
+	}
+
+	return EventSubjectTask
+}
+
+// ChangedFields returns fields updated by the latest comment.
+func (e Event) ChangedFields() []*FormField {
+	if e.LastComment == nil {
+		return nil
+	}
+
+	return e.LastComment.FieldUpdates
+}
+
+// ChangedField returns the field updated by the latest comment found by id, name or code or nil if it wasn't changed.
+func (e Event) ChangedField(key interface{}) *FormField {
+	f, _ := templateField(e.ChangedFields(), key)
+	return f
+}
+
+// setLastComments fills LastComment and LastAnnouncementComment with the latest comments.
+func (e *Event) setLastComments() {
+	if e.Task != nil {
+		for _, c := range e.Task.Comments {
+			if c != nil && (e.LastComment == nil || c.CreateDate.After(e.LastComment.CreateDate) ||
+				c.CreateDate.Equal(e.LastComment.CreateDate) && c.ID > e.LastComment.ID) {
+				e.LastComment = c
+			}
+		}
+	}
+	if e.Announcement != nil {
+		for _, c := range e.Announcement.Comments {
+			if c != nil && (e.LastAnnouncementComment == nil || c.CreateDate.After(e.LastAnnouncementComment.CreateDate) ||
+				c.CreateDate.Equal(e.LastAnnouncementComment.CreateDate) && c.ID > e.LastAnnouncementComment.ID) {
+				e.LastAnnouncementComment = c
+			}
+		}
+	}
 }
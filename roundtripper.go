@@ -0,0 +1,111 @@
+package pyrus
+
+import "net/http"
+
+// RetryingTransport is an http.RoundTripper authorizing requests with the access token of the client.
+// It gets a new token on 401 and retries the request once, waits for WithAdaptiveThrottling and updates QuotaStatus
+// of the client, so raw HTTP calls to endpoints not wrapped by the client behave consistently with it.
+// Like the client, it doesn't retry other failures, so they could be handled by the caller.
+// Requests with a body are retried only if the body can be replayed, which is the case for requests
+// created by http.NewRequest with bytes or strings.
+type RetryingTransport struct {
+	client *Client
+	next   http.RoundTripper
+}
+
+// NewRetryingTransport returns RetryingTransport for the client performing requests with next
+// or with the transport of the client if next is nil.
+func NewRetryingTransport(client *Client, next http.RoundTripper) *RetryingTransport {
+	if next == nil {
+		next = client.httpClient.Transport
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RetryingTransport{
+		client: client,
+		next:   next,
+	}
+}
+
+// HTTPClient returns http.Client using the transport, e.g. for calls of endpoints not wrapped by the client.
+func (t *RetryingTransport) HTTPClient() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.client.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	t.client.mu.RLock()
+	hasToken := t.client.accessToken != ""
+	t.client.mu.RUnlock()
+	if !hasToken {
+		if err := t.client.getAndSetAccessToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.attempt(req, 1)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.client.staticToken || !t.replayable(req) {
+		return resp, err
+	}
+
+	// Get new access_token in case of old session
+	drainBody(resp)
+	if err := t.client.getAndSetAccessToken(); err != nil {
+		return nil, err
+	}
+
+	return t.attempt(req, 2)
+}
+
+// attempt sends the request with the current access token and a fresh body.
+func (t *RetryingTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	t.client.mu.RLock()
+	r.Header.Set("Authorization", "Bearer "+t.client.accessToken)
+	t.client.mu.RUnlock()
+	if r.Header.Get("User-Agent") == "" {
+		r.Header.Set("User-Agent", userAgent)
+	}
+
+	if err := t.client.waitThrottle(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	t.client.recordUsage(req.Method, req.URL.Path)
+	if resp != nil {
+		t.client.updateQuota(resp.Header)
+		t.client.observeThrottle(resp.StatusCode, resp.Header)
+	}
+
+	return resp, err
+}
+
+// replayable reports whether the request could be sent again.
+func (t *RetryingTransport) replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func drainBody(resp *http.Response) {
+	buf := make([]byte, 4096)
+	for i := 0; i < 16; i++ {
+		if _, err := resp.Body.Read(buf); err != nil {
+			break
+		}
+	}
+	resp.Body.Close() //nolint:errcheck
+}
@@ -0,0 +1,64 @@
+package pyrus
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryingTransport(t *testing.T) {
+	var (
+		auths    int
+		attempts int
+		bodies   []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			auths++
+			w.Write([]byte(`{"access_token":"token` + string(rune('0'+auths)) + `"}`)) //nolint:errcheck
+			return
+		}
+
+		attempts++
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		switch {
+		case r.Header.Get("Authorization") != "Bearer token2":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/busy":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	hc := NewRetryingTransport(c, nil).HTTPClient()
+	resp, err := hc.Post(ts.URL+"/custom", "application/json", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The request is sent again with the new token and the same body
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, auths)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{`{"a":1}`, `{"a":1}`}, bodies)
+
+	// Other failures are returned as is, like by the client
+	resp, err = hc.Get(ts.URL + "/busy")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2, auths)
+}
@@ -0,0 +1,150 @@
+package pyrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// WebhookSchemaError lists structural problems of the webhook payload.
+type WebhookSchemaError struct {
+	Problems []string
+}
+
+func (e *WebhookSchemaError) Error() string {
+	return "invalid webhook payload: " + strings.Join(e.Problems, "; ")
+}
+
+// WithWebhookSchemaValidation enables validation of webhook payloads against the event schema
+// after the signature check, e.g. to detect bodies modified by a proxy. See ValidateWebhookPayload.
+func WithWebhookSchemaValidation(mode WebhookSchemaMode) Option {
+	return func(c *Client) {
+		c.webhookSchemaMode = mode
+	}
+}
+
+// ValidateWebhookPayload checks the structure of the webhook body: it must be a JSON object with non-empty event,
+// numeric ids and either task or announcement object matching task_id or announcement_id.
+// Fields and comments of the task must be objects with numeric ids. Unknown keys are allowed.
+// It returns *WebhookSchemaError if the payload is invalid.
+func ValidateWebhookPayload(body []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var payload map[string]interface{}
+	if err := dec.Decode(&payload); err != nil || payload == nil {
+		return &WebhookSchemaError{Problems: []string{"body is not a JSON object"}}
+	}
+
+	v := &schemaValidator{}
+	if event, ok := payload["event"].(string); !ok || event == "" {
+		v.problem("event must be a non-empty string")
+	}
+	if token, ok := payload["access_token"]; ok {
+		if _, ok := token.(string); !ok {
+			v.problem("access_token must be a string")
+		}
+	}
+	v.optionalID(payload, "user_id")
+
+	_, hasAnnouncement := payload["announcement"]
+	_, hasAnnouncementID := payload["announcement_id"]
+	if hasAnnouncement || hasAnnouncementID {
+		v.entity(payload, "announcement", "announcement_id")
+	} else {
+		task := v.entity(payload, "task", "task_id")
+		if task != nil {
+			v.objects(task, "task.fields", "fields", "type")
+			v.objects(task, "task.comments", "comments", "")
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return &WebhookSchemaError{Problems: v.problems}
+	}
+
+	return nil
+}
+
+type schemaValidator struct {
+	problems []string
+}
+
+func (v *schemaValidator) problem(s string) {
+	v.problems = append(v.problems, s)
+}
+
+// optionalID checks that the key is an integer if it's present and returns it.
+func (v *schemaValidator) optionalID(obj map[string]interface{}, key string) (int64, bool) {
+	raw, ok := obj[key]
+	if !ok {
+		return 0, false
+	}
+
+	n, ok := raw.(json.Number)
+	if ok {
+		id, err := n.Int64()
+		if err == nil {
+			return id, true
+		}
+	}
+	v.problem(key + " must be an integer")
+
+	return 0, false
+}
+
+// entity checks the object of the event subject and its id.
+func (v *schemaValidator) entity(payload map[string]interface{}, key, idKey string) map[string]interface{} {
+	id, hasID := v.optionalID(payload, idKey)
+
+	obj, ok := payload[key].(map[string]interface{})
+	if !ok {
+		v.problem(key + " must be an object")
+		return nil
+	}
+
+	entityID, ok := v.optionalID(obj, "id")
+	if !ok {
+		v.problem(key + ".id is required")
+	} else if hasID && entityID != id {
+		v.problem(key + ".id " + strconv.FormatInt(entityID, 10) + " doesn't match " + idKey + " " + strconv.FormatInt(id, 10))
+	}
+
+	return obj
+}
+
+// objects checks that the key is an array of objects with integer ids and, optionally, string typeKey.
+func (v *schemaValidator) objects(obj map[string]interface{}, name, key, typeKey string) {
+	raw, ok := obj[key]
+	if !ok || raw == nil {
+		return
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		v.problem(name + " must be an array")
+		return
+	}
+	for i, item := range items {
+		itemName := name + "[" + strconv.Itoa(i) + "]"
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			v.problem(itemName + " must be an object")
+			continue
+		}
+
+		sub := &schemaValidator{}
+		if _, ok := sub.optionalID(o, "id"); !ok && len(sub.problems) == 0 {
+			v.problem(itemName + ".id is required")
+		}
+		for _, p := range sub.problems {
+			v.problem(itemName + "." + p)
+		}
+		if typeKey != "" {
+			if t, ok := o[typeKey].(string); !ok || t == "" {
+				v.problem(itemName + "." + typeKey + " must be a non-empty string")
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package pyrus
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWebhookPayload(t *testing.T) {
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+	assert.NoError(t, ValidateWebhookPayload(b))
+
+	assert.NoError(t, ValidateWebhookPayload([]byte(`{"event":"comment","announcement_id":5,"announcement":{"id":5}}`)))
+
+	tests := map[string]struct {
+		body     string
+		problems []string
+	}{
+		"not object": {`[]`, []string{"body is not a JSON object"}},
+		"truncated":  {`{"event":"comm`, []string{"body is not a JSON object"}},
+		"no event":   {`{"task_id":1,"task":{"id":1}}`, []string{"event must be a non-empty string"}},
+		"string id": {
+			`{"event":"comment","task_id":"1","task":{"id":1}}`,
+			[]string{"task_id must be an integer"},
+		},
+		"mismatched id": {
+			`{"event":"comment","task_id":2,"task":{"id":1}}`,
+			[]string{"task.id 1 doesn't match task_id 2"},
+		},
+		"missing task": {`{"event":"comment","task_id":2}`, []string{"task must be an object"}},
+		"broken fields": {
+			`{"event":"comment","task_id":1,"task":{"id":1,"fields":[{"id":1,"type":"text"},{"type":"text"},{"id":1.5,"type":""},7],"comments":{}}}`,
+			[]string{
+				"task.fields[1].id is required",
+				"task.fields[2].id must be an integer",
+				"task.fields[2].type must be a non-empty string",
+				"task.fields[3] must be an object",
+				"task.comments must be an array",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateWebhookPayload([]byte(tt.body))
+			var schemaErr *WebhookSchemaError
+			require.True(t, errors.As(err, &schemaErr))
+			assert.Equal(t, tt.problems, schemaErr.Problems)
+		})
+	}
+}
+
+func TestWithWebhookSchemaValidation(t *testing.T) {
+	body := []byte(`{"event":"comment","task_id":2,"task":{"id":1}}`)
+
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookSchemaValidation(WebhookSchemaFlag))
+	require.NoError(t, err)
+	req := signedWebhookRequest(t, "http://localhost", body)
+	event, err := c.ParseWebhook(body, req.Header.Get(WebhookSignatureHeader))
+	require.NoError(t, err)
+	assert.Error(t, event.SchemaError)
+
+	c, err = NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookSchemaValidation(WebhookSchemaReject))
+	require.NoError(t, err)
+	handler, _ := c.WebhookHTTPHandler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "http://localhost", body))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "task.id 1 doesn't match task_id 2")
+}
@@ -0,0 +1,219 @@
+package pyrus
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// AccessLog describes a request handled by RunWebhookServer.
+type AccessLog struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Status     int
+	Bytes      int
+	Duration   time.Duration
+}
+
+type webhookServer struct {
+	path            string
+	healthPath      string
+	tlsConfig       *tls.Config
+	certFile        string
+	keyFile         string
+	shutdownTimeout time.Duration
+	accessLog       func(AccessLog)
+}
+
+// WebhookServerOption helps to create an option for RunWebhookServer.
+type WebhookServerOption func(*webhookServer)
+
+// WithWebhookPath allows to override default webhook path "/".
+func WithWebhookPath(path string) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.path = path
+	}
+}
+
+// WithHealthPath allows to override default health check path "/healthz".
+func WithHealthPath(path string) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.healthPath = path
+	}
+}
+
+// WithTLSConfig enables TLS with the config. Pass autocert.Manager's TLSConfig() to get certificates from Let's Encrypt.
+func WithTLSConfig(cfg *tls.Config) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.tlsConfig = cfg
+	}
+}
+
+// WithTLSCertFiles enables TLS with the certificate and key files.
+func WithTLSCertFiles(certFile, keyFile string) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithShutdownTimeout allows to override default graceful shutdown timeout of 10 seconds.
+func WithShutdownTimeout(d time.Duration) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.shutdownTimeout = d
+	}
+}
+
+// WithAccessLog allows to receive an entry for every handled request.
+func WithAccessLog(fn func(AccessLog)) WebhookServerOption {
+	return func(s *webhookServer) {
+		s.accessLog = fn
+	}
+}
+
+// RunWebhookServer starts HTTP server receiving webhooks at addr and passes events to fn one by one.
+// Server is gracefully shut down when the context is done, events received before that are still passed to fn.
+func (c *Client) RunWebhookServer(ctx context.Context, addr string, fn func(Event), opts ...WebhookServerOption) error {
+	s := &webhookServer{
+		path:            "/",
+		healthPath:      "/healthz",
+		shutdownTimeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	events := c.MountWebhook(s.path, mux)
+	mux.HandleFunc(s.healthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("ok")); err != nil {
+			c.logger.Error("Error while writing a response!", err)
+		}
+	})
+
+	var handler http.Handler = mux
+	if s.accessLog != nil {
+		handler = accessLogHandler(mux, s.accessLog)
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         s.tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Close of the client stops the server as well
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		select {
+		case <-c.Done():
+			stop()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Events are consumed until the server is shut down, since handlers finishing during graceful shutdown
+	// have already replied 200 to Pyrus
+	done, shutdown := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				fn(event)
+			case <-shutdown:
+				// Drain events accepted before shutdown
+				for {
+					select {
+					case event, ok := <-events:
+						if !ok {
+							return
+						}
+						fn(event)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.tlsConfig != nil || s.certFile != "" {
+			serveErr <- srv.ListenAndServeTLS(s.certFile, s.keyFile)
+		} else {
+			serveErr <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		close(shutdown)
+		<-done
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := srv.Shutdown(shutdownCtx)
+	close(shutdown)
+	<-done
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return err
+}
+
+// statusRecorder remembers status code and size of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+func accessLogHandler(next http.Handler, fn func(AccessLog)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		fn(AccessLog{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			RemoteAddr: r.RemoteAddr,
+			Status:     rec.status,
+			Bytes:      rec.bytes,
+			Duration:   time.Since(start),
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package pyrus
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RunWebhookServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	var (
+		mu   sync.Mutex
+		logs []AccessLog
+	)
+	events := make(chan Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cl.(*Client).RunWebhookServer(ctx, addr, func(e Event) {
+			events <- e
+		}, WithWebhookPath("/pyrus"), WithAccessLog(func(l AccessLog) {
+			mu.Lock()
+			logs = append(logs, l)
+			mu.Unlock()
+		}))
+	}()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://" + addr + "/healthz")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(signedWebhookRequest(t, "http://"+addr+"/pyrus", b))
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	<-events
+
+	cancel()
+	require.NoError(t, <-errCh)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "/healthz", logs[0].Path)
+	assert.Equal(t, http.MethodPost, logs[1].Method)
+	assert.Equal(t, http.StatusOK, logs[1].Status)
+}
+
+func TestClient_RunWebhookServer_shutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	// Occupied slot of the limit tells the handler is in flight
+	c, err := NewClient(fakePyrusLogin, fakePyrusSecurityKey, WithWebhookConcurrencyLimit(1, time.Second))
+	require.NoError(t, err)
+
+	events := make(chan Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.RunWebhookServer(ctx, addr, func(e Event) {
+			events <- e
+		})
+	}()
+	// Probes don't keep connections, otherwise Shutdown waits for them
+	probe := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	healthy := func() bool {
+		resp, err := probe.Get("http://" + addr + "/healthz")
+		if err != nil {
+			return false
+		}
+		return resp.Body.Close() == nil
+	}
+	require.Eventually(t, healthy, time.Second, 10*time.Millisecond)
+
+	b, err := os.ReadFile("testdata/event.json")
+	require.NoError(t, err)
+	req := signedWebhookRequest(t, "http://"+addr+"/", b)
+	pr, pw := io.Pipe()
+	req.Body = pr
+	req.ContentLength = int64(len(b))
+
+	respCh := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		respCh <- resp
+	}()
+	_, err = pw.Write(b[:10])
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(c.webhookSem) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Body is finished only after the shutdown has started
+	cancel()
+	require.Eventually(t, func() bool {
+		return !healthy()
+	}, time.Second, 10*time.Millisecond)
+	_, err = pw.Write(b[10:])
+	require.NoError(t, err)
+	require.NoError(t, pw.Close())
+
+	resp := <-respCh
+	require.NotNil(t, resp)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, <-errCh)
+	select {
+	case <-events:
+	default:
+		t.Fatal("event accepted during shutdown wasn't passed to fn")
+	}
+}
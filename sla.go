@@ -0,0 +1,277 @@
+package pyrus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SLAAlert is emitted by SLAMonitor for the task approaching or past due or stuck on the workflow step.
+type SLAAlert struct {
+	Type SLAAlertType
+	// FormID is set for tasks of monitored forms.
+	FormID int
+	// ListID is set for tasks of monitored lists.
+	ListID int
+	Task   *Task
+	// Due is the deadline of the task, set for due_soon and overdue alerts.
+	Due time.Time
+	// Step is the current step of the task and Since is the time it was entered, set for stuck alerts.
+	Step  int
+	Since time.Time
+}
+
+// SLAMonitor periodically scans open tasks of forms and lists and emits alerts for tasks approaching or past due
+// and for form tasks staying on the same workflow step longer than the threshold.
+// Every alert is emitted once per task until the state of the task changes.
+type SLAMonitor struct {
+	client   IClient
+	forms    []int
+	lists    []int
+	dueSoon  time.Duration
+	stuck    time.Duration
+	interval time.Duration
+	onError  func(err error)
+	now      func() time.Time
+
+	mu      sync.Mutex
+	alerted map[int]SLAAlertType
+}
+
+// SLAOption helps to create an option for SLAMonitor.
+type SLAOption func(*SLAMonitor)
+
+// WithSLAForms allows to monitor open tasks of the forms.
+func WithSLAForms(formIDs ...int) SLAOption {
+	return func(m *SLAMonitor) {
+		m.forms = append(m.forms, formIDs...)
+	}
+}
+
+// WithSLALists allows to monitor open tasks of the lists.
+func WithSLALists(listIDs ...int) SLAOption {
+	return func(m *SLAMonitor) {
+		m.lists = append(m.lists, listIDs...)
+	}
+}
+
+// WithSLADueSoon allows to override default 24 hours before the deadline when due_soon alert is emitted.
+func WithSLADueSoon(d time.Duration) SLAOption {
+	return func(m *SLAMonitor) {
+		m.dueSoon = d
+	}
+}
+
+// WithSLAStuckThreshold enables stuck alerts for form tasks staying on the same step longer than d.
+func WithSLAStuckThreshold(d time.Duration) SLAOption {
+	return func(m *SLAMonitor) {
+		m.stuck = d
+	}
+}
+
+// WithSLAInterval allows to override default scan interval of 5 minutes.
+func WithSLAInterval(d time.Duration) SLAOption {
+	return func(m *SLAMonitor) {
+		m.interval = d
+	}
+}
+
+// WithSLAErrorHandler allows Run to continue after failed scans, passing errors to the handler.
+// Without it Run stops on the first error.
+func WithSLAErrorHandler(fn func(err error)) SLAOption {
+	return func(m *SLAMonitor) {
+		m.onError = fn
+	}
+}
+
+// NewSLAMonitor returns an instance of SLAMonitor.
+func NewSLAMonitor(client IClient, opts ...SLAOption) *SLAMonitor {
+	m := &SLAMonitor{
+		client:   client,
+		dueSoon:  24 * time.Hour,
+		interval: 5 * time.Minute,
+		now:      clientClock(client).Now,
+		alerted:  make(map[int]SLAAlertType),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Check scans monitored forms and lists once and passes new alerts to fn.
+func (m *SLAMonitor) Check(fn func(alert SLAAlert)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	active := make(map[int]struct{})
+	emit := func(alert SLAAlert) {
+		active[alert.Task.ID] = struct{}{}
+		if m.alerted[alert.Task.ID] == alert.Type {
+			return
+		}
+		m.alerted[alert.Task.ID] = alert.Type
+		fn(alert)
+	}
+
+	for _, formID := range m.forms {
+		resp, err := m.client.Registry(formID, &RegistryRequest{})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range resp.Tasks {
+			if task == nil || task.TaskHeader == nil || task.CloseDate != nil {
+				continue
+			}
+
+			if alert, ok := m.dueAlert(task, now); ok {
+				alert.FormID = formID
+				emit(alert)
+				continue
+			}
+
+			alert, ok, err := m.stuckAlert(task, now)
+			if err != nil {
+				return err
+			}
+			if ok {
+				alert.FormID = formID
+				emit(alert)
+			}
+		}
+	}
+
+	for _, listID := range m.lists {
+		resp, err := m.client.TaskList(listID, defaultTaskListPageSize, false)
+		if err != nil {
+			return err
+		}
+
+		for _, header := range resp.Tasks {
+			if header == nil || header.CloseDate != nil {
+				continue
+			}
+
+			if alert, ok := m.dueAlert(&Task{TaskHeader: header}, now); ok {
+				alert.ListID = listID
+				emit(alert)
+			}
+		}
+	}
+
+	// Tasks which are fine again could be alerted again later
+	for id := range m.alerted {
+		if _, ok := active[id]; !ok {
+			delete(m.alerted, id)
+		}
+	}
+
+	return nil
+}
+
+// Run scans monitored forms and lists every interval until the context is done.
+func (m *SLAMonitor) Run(ctx context.Context, fn func(alert SLAAlert)) error {
+	if m.interval <= 0 {
+		return fmt.Errorf("SLA interval must be positive, got %v", m.interval)
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	closed := clientDone(m.client)
+	for {
+		if err := m.Check(fn); err != nil {
+			if m.onError == nil {
+				return err
+			}
+			m.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-closed:
+			return ErrClientClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *SLAMonitor) dueAlert(task *Task, now time.Time) (SLAAlert, bool) {
+	due, ok := taskDue(task)
+	if !ok {
+		return SLAAlert{}, false
+	}
+
+	alert := SLAAlert{Task: task, Due: due}
+	switch {
+	case !now.Before(due):
+		alert.Type = SLAAlertTypeOverdue
+	case due.Sub(now) <= m.dueSoon:
+		alert.Type = SLAAlertTypeDueSoon
+	default:
+		return SLAAlert{}, false
+	}
+
+	return alert, true
+}
+
+func (m *SLAMonitor) stuckAlert(task *Task, now time.Time) (SLAAlert, bool, error) {
+	if m.stuck <= 0 || task.CurrentStep == 0 || now.Sub(task.CreateDate) < m.stuck {
+		return SLAAlert{}, false, nil
+	}
+
+	// Step could only be changed by a comment, so the task modified recently has to be checked closer
+	since := task.CreateDate
+	if now.Sub(taskModifiedDate(task)) < m.stuck {
+		resp, err := m.client.Task(task.ID)
+		if err != nil {
+			return SLAAlert{}, false, err
+		}
+		if resp.Task == nil {
+			return SLAAlert{}, false, nil
+		}
+		for _, comment := range resp.Task.Comments {
+			if comment != nil && (comment.ChangedStep != 0 || comment.ResetToStep != 0) && comment.CreateDate.After(since) {
+				since = comment.CreateDate
+			}
+		}
+		if now.Sub(since) < m.stuck {
+			return SLAAlert{}, false, nil
+		}
+	}
+
+	return SLAAlert{
+		Type:  SLAAlertTypeStuck,
+		Task:  task,
+		Step:  task.CurrentStep,
+		Since: since,
+	}, true, nil
+}
+
+// taskDue returns the deadline of the task. Due date without time is treated as the end of the day in UTC.
+func taskDue(task *Task) (time.Time, bool) {
+	if task.Due != nil {
+		return *task.Due, true
+	}
+
+	dueDate := task.DueDate
+	if dueDate == "" {
+		dueDate = task.TaskHeader.DueDate
+	}
+	if dueDate == "" {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse("2006-01-02", dueDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t.Add(24 * time.Hour), true
+}
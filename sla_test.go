@@ -0,0 +1,94 @@
+package pyrus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slaClient struct {
+	IClient
+	tasks    []*Task
+	list     []*TaskHeader
+	comments map[int][]*TaskComment
+	missing  map[int]bool
+}
+
+func (c *slaClient) Registry(int, *RegistryRequest) (*FormRegisterResponse, error) {
+	return &FormRegisterResponse{Tasks: c.tasks}, nil
+}
+
+func (c *slaClient) TaskList(int, int, bool) (*TaskListResponse, error) {
+	return &TaskListResponse{Tasks: c.list}, nil
+}
+
+func (c *slaClient) Task(taskID int) (*TaskResponse, error) {
+	if c.missing[taskID] {
+		return &TaskResponse{}, nil
+	}
+	return &TaskResponse{Task: &TaskWithComments{Comments: c.comments[taskID]}}, nil
+}
+
+func TestSLAMonitor_Check(t *testing.T) {
+	now := time.Date(2021, 8, 2, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-72 * time.Hour)
+	modified := now.Add(-time.Hour)
+	soon, past := now.Add(2*time.Hour), now.Add(-time.Hour)
+
+	client := &slaClient{
+		tasks: []*Task{
+			{TaskHeader: &TaskHeader{ID: 1, CreateDate: created}, Due: &soon},
+			{TaskHeader: &TaskHeader{ID: 2, CreateDate: created}, Due: &past},
+			{TaskHeader: &TaskHeader{ID: 3, CreateDate: created}, CurrentStep: 2},
+			{TaskHeader: &TaskHeader{ID: 4, CreateDate: created, LastModifiedDate: &modified}, CurrentStep: 2},
+			{TaskHeader: &TaskHeader{ID: 5, CreateDate: created, LastModifiedDate: &modified}, CurrentStep: 3},
+			{TaskHeader: &TaskHeader{ID: 8, CreateDate: created, LastModifiedDate: &modified}, CurrentStep: 2},
+		},
+		list: []*TaskHeader{
+			{ID: 6, CreateDate: created, DueDate: "2021-08-01"},
+			{ID: 7, CreateDate: created, DueDate: "2021-08-10"},
+		},
+		comments: map[int][]*TaskComment{
+			4: {{CreateDate: now.Add(-48 * time.Hour), ChangedStep: 2}},
+			5: {{CreateDate: now.Add(-2 * time.Hour), ChangedStep: 3}},
+		},
+		missing: map[int]bool{8: true},
+	}
+
+	monitor := NewSLAMonitor(client,
+		WithSLAForms(1),
+		WithSLALists(2),
+		WithSLAStuckThreshold(24*time.Hour),
+	)
+	monitor.now = func() time.Time { return now }
+
+	alerts := make(map[int]SLAAlert)
+	collect := func(alert SLAAlert) { alerts[alert.Task.ID] = alert }
+
+	require.NoError(t, monitor.Check(collect))
+	require.Len(t, alerts, 5)
+	assert.Equal(t, SLAAlertTypeDueSoon, alerts[1].Type)
+	assert.Equal(t, 1, alerts[1].FormID)
+	assert.Equal(t, SLAAlertTypeOverdue, alerts[2].Type)
+	assert.Equal(t, SLAAlertTypeStuck, alerts[3].Type)
+	assert.Equal(t, created, alerts[3].Since)
+	assert.Equal(t, SLAAlertTypeStuck, alerts[4].Type)
+	assert.Equal(t, now.Add(-48*time.Hour), alerts[4].Since)
+	assert.Equal(t, SLAAlertTypeOverdue, alerts[6].Type)
+	assert.Equal(t, 2, alerts[6].ListID)
+
+	// alerts are not repeated until the state changes
+	alerts = make(map[int]SLAAlert)
+	monitor.now = func() time.Time { return now.Add(3 * time.Hour) }
+	require.NoError(t, monitor.Check(collect))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, SLAAlertTypeOverdue, alerts[1].Type)
+}
+
+func TestSLAMonitor_Run_invalidInterval(t *testing.T) {
+	monitor := NewSLAMonitor(&slaClient{}, WithSLAInterval(-time.Minute))
+	assert.EqualError(t, monitor.Run(context.Background(), func(SLAAlert) {}), "SLA interval must be positive, got -1m0s")
+}
@@ -0,0 +1,176 @@
+package pyrus
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Snapshot is a dump of organization settings: forms, catalogs with items, roles and members.
+type Snapshot struct {
+	CreatedAt time.Time          `json:"created_at"`
+	Forms     []*FormResponse    `json:"forms"`
+	Catalogs  []*CatalogResponse `json:"catalogs"`
+	Roles     []*Role            `json:"roles"`
+	Members   []*Member          `json:"members"`
+}
+
+// SnapshotExporter fetches Snapshot of the organization. Catalogs are fetched one by one with a delay
+// between requests, so export doesn't exhaust the quota shared with other integrations.
+type SnapshotExporter struct {
+	client   IClient
+	interval time.Duration
+}
+
+// SnapshotOption helps to create an option for SnapshotExporter.
+type SnapshotOption func(*SnapshotExporter)
+
+// WithSnapshotInterval allows to override default delay of 200ms between catalog requests.
+func WithSnapshotInterval(d time.Duration) SnapshotOption {
+	return func(e *SnapshotExporter) {
+		e.interval = d
+	}
+}
+
+// NewSnapshotExporter returns an instance of SnapshotExporter.
+func NewSnapshotExporter(client IClient, opts ...SnapshotOption) *SnapshotExporter {
+	e := &SnapshotExporter{
+		client:   client,
+		interval: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Export fetches the snapshot until the context is done.
+func (e *SnapshotExporter) Export(ctx context.Context) (*Snapshot, error) {
+	clock := clientClock(e.client)
+	snapshot := &Snapshot{CreatedAt: clock.Now()}
+
+	forms, err := e.client.Forms()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Forms = forms.Forms
+
+	roles, err := e.client.Roles()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Roles = roles.Roles
+
+	members, err := e.client.Members()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Members = members.Members
+
+	catalogs, err := e.client.Catalogs()
+	if err != nil {
+		return nil, err
+	}
+
+	closed := clientDone(e.client)
+	for _, c := range catalogs.Catalogs {
+		if c == nil || c.Deleted {
+			continue
+		}
+
+		if len(snapshot.Catalogs) > 0 && e.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-closed:
+				return nil, ErrClientClosed
+			case <-clock.After(e.interval):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		catalog, err := e.client.Catalog(c.CatalogID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Catalogs = append(snapshot.Catalogs, catalog)
+	}
+
+	return snapshot, nil
+}
+
+// ReadSnapshot decodes the snapshot written by WriteJSON.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// WriteJSON writes the snapshot as a single indented JSON document.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// WriteArchive writes the snapshot as a zip archive with a file per form and catalog, which is handy to diff
+// between environments or commits: manifest.json, roles.json, members.json, forms/ID.json and catalogs/ID.json.
+func (s *Snapshot) WriteArchive(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := struct {
+		CreatedAt time.Time `json:"created_at"`
+		Forms     int       `json:"forms"`
+		Catalogs  int       `json:"catalogs"`
+		Roles     int       `json:"roles"`
+		Members   int       `json:"members"`
+	}{s.CreatedAt, len(s.Forms), len(s.Catalogs), len(s.Roles), len(s.Members)}
+
+	if err := writeArchiveJSON(zw, "manifest.json", s.CreatedAt, manifest); err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(zw, "roles.json", s.CreatedAt, s.Roles); err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(zw, "members.json", s.CreatedAt, s.Members); err != nil {
+		return err
+	}
+	for _, f := range s.Forms {
+		if err := writeArchiveJSON(zw, "forms/"+strconv.Itoa(f.ID)+".json", s.CreatedAt, f); err != nil {
+			return err
+		}
+	}
+	for _, c := range s.Catalogs {
+		if err := writeArchiveJSON(zw, "catalogs/"+strconv.Itoa(c.CatalogID)+".json", s.CreatedAt, c); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeArchiveJSON(zw *zip.Writer, name string, modified time.Time, v interface{}) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
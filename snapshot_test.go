@@ -0,0 +1,76 @@
+package pyrus
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotExporter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms":
+			w.Write([]byte(`{"forms":[{"id":1,"name":"Заявка"}]}`)) //nolint:errcheck
+		case "/roles":
+			w.Write([]byte(`{"roles":[{"id":2,"name":"Бухгалтерия"}]}`)) //nolint:errcheck
+		case "/members":
+			w.Write([]byte(`{"members":[{"id":3,"first_name":"Иван"}]}`)) //nolint:errcheck
+		case "/catalogs":
+			w.Write([]byte(`{"catalogs":[{"catalog_id":4},{"catalog_id":5,"deleted":true},{"catalog_id":6}]}`)) //nolint:errcheck
+		case "/catalogs/4", "/catalogs/6":
+			w.Write([]byte(`{"catalog_id":` + r.URL.Path[len("/catalogs/"):] + `,"items":[{"item_id":1,"values":["a"]}]}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	start := time.Date(2021, 7, 21, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithClock(clock))
+	require.NoError(t, err)
+
+	snapshot, err := NewSnapshotExporter(c, WithSnapshotInterval(time.Second)).Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, start, snapshot.CreatedAt)
+	assert.Equal(t, start.Add(time.Second), clock.Now())
+	require.Len(t, snapshot.Forms, 1)
+	require.Len(t, snapshot.Roles, 1)
+	require.Len(t, snapshot.Members, 1)
+	require.Len(t, snapshot.Catalogs, 2)
+	assert.Equal(t, 6, snapshot.Catalogs[1].CatalogID)
+	assert.Len(t, snapshot.Catalogs[1].Items, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.WriteJSON(&buf))
+	decoded, err := ReadSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Catalogs[0].Items, decoded.Catalogs[0].Items)
+
+	buf.Reset()
+	require.NoError(t, snapshot.WriteArchive(&buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"manifest.json", "roles.json", "members.json", "forms/1.json", "catalogs/4.json", "catalogs/6.json",
+	}, names)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewSnapshotExporter(c).Export(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,70 @@
+package pyrus
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// StatsCollector receives statistics of every HTTP request performed by Client,
+// e.g. to export them to statsd or OpenCensus.
+// Endpoint is the method and the path with ids replaced by placeholder: "GET /tasks/{id}".
+// Status is zero if the request failed without a response. Bytes is the size of the response body read from the wire.
+// It's called synchronously, so implementations should be fast and safe for concurrent use.
+type StatsCollector interface {
+	ObserveRequest(endpoint string, status int, duration time.Duration, bytes int64)
+}
+
+// WithStatsCollector allows to collect statistics of requests per endpoint.
+func WithStatsCollector(s StatsCollector) Option {
+	return func(c *Client) {
+		c.stats = s
+	}
+}
+
+// observeRequest counts API usage and passes request statistics to the collector if there is any.
+func (c *Client) observeRequest(method, path string, status int, start time.Time, bytes int64) {
+	c.recordUsage(method, path)
+	if c.stats == nil {
+		return
+	}
+
+	c.stats.ObserveRequest(endpointName(method, path), status, c.clock.Now().Sub(start), bytes)
+}
+
+// endpointName returns the method and the path with numeric and call ids replaced by {id}.
+func endpointName(method, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if isDigits(segment) || (i > 0 && segments[i-1] == "calls") {
+			segments[i] = "{id}"
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// countingReader counts bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,66 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requestStats struct {
+	endpoint string
+	status   int
+	bytes    int64
+}
+
+type recordingCollector struct {
+	mu    sync.Mutex
+	stats []requestStats
+}
+
+func (c *recordingCollector) ObserveRequest(endpoint string, status int, _ time.Duration, bytes int64) {
+	c.mu.Lock()
+	c.stats = append(c.stats, requestStats{endpoint: endpoint, status: status, bytes: bytes})
+	c.mu.Unlock()
+}
+
+func TestWithStatsCollector(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/forms/42":
+			w.Write([]byte(`{"id":42}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	collector := &recordingCollector{}
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithStatsCollector(collector))
+	require.NoError(t, err)
+
+	_, err = c.Form(42)
+	require.NoError(t, err)
+	_, err = c.Task(1)
+	assert.Error(t, err)
+
+	assert.Equal(t, []requestStats{
+		{endpoint: "POST /auth", status: http.StatusOK, bytes: 24},
+		{endpoint: "GET /forms/{id}", status: http.StatusOK, bytes: 9},
+		{endpoint: "GET /tasks/{id}", status: http.StatusNotFound, bytes: 2},
+	}, collector.stats)
+}
+
+func TestEndpointName(t *testing.T) {
+	assert.Equal(t, "POST /forms/{id}/register", endpointName(http.MethodPost, "/forms/1/register"))
+	assert.Equal(t, "PUT /calls/{id}", endpointName(http.MethodPut, "/calls/5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5"))
+	assert.Equal(t, "GET /files/download/{id}", endpointName(http.MethodGet, "/files/download/7"))
+}
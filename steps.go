@@ -0,0 +1,78 @@
+package pyrus
+
+import "sort"
+
+// Steps maps workflow step numbers of the form to their names.
+type Steps map[int]string
+
+// Step is a single workflow step of the form.
+type Step struct {
+	Number int
+	Name   string
+}
+
+// Ordered returns steps sorted by number.
+func (s Steps) Ordered() []Step {
+	steps := make([]Step, 0, len(s))
+	for number, name := range s {
+		steps = append(steps, Step{Number: number, Name: name})
+	}
+	sort.Slice(steps, func(i, j int) bool {
+		return steps[i].Number < steps[j].Number
+	})
+
+	return steps
+}
+
+// ByName returns the first step with the name in order of numbers.
+func (s Steps) ByName(name string) (Step, bool) {
+	for _, step := range s.Ordered() {
+		if step.Name == name {
+			return step, true
+		}
+	}
+
+	return Step{}, false
+}
+
+// RequiredFields returns fields of the form definition which are required for filling on the step,
+// including fields nested into titles, choice options and table columns.
+func (r *FormResponse) RequiredFields(step int) []*FormField {
+	return definitionFields(r.Fields, func(f *FormField) bool {
+		return f.Info != nil && f.Info.RequiredStep != 0 && f.Info.RequiredStep <= step
+	})
+}
+
+// ImmutableFields returns fields of the form definition which can't be changed on the step,
+// including fields nested into titles, choice options and table columns.
+func (r *FormResponse) ImmutableFields(step int) []*FormField {
+	return definitionFields(r.Fields, func(f *FormField) bool {
+		return f.Info != nil && f.Info.ImmutableStep != 0 && f.Info.ImmutableStep <= step
+	})
+}
+
+// definitionFields collects fields of the form definition matching the predicate.
+func definitionFields(fields []*FormField, match func(f *FormField) bool) []*FormField {
+	var found []*FormField
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+		if match(f) {
+			found = append(found, f)
+		}
+		if f.Info == nil {
+			continue
+		}
+
+		found = append(found, definitionFields(f.Info.Fields, match)...)
+		found = append(found, definitionFields(f.Info.Columns, match)...)
+		for _, option := range f.Info.Options {
+			if option != nil {
+				found = append(found, definitionFields(option.Fields, match)...)
+			}
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,46 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSteps(t *testing.T) {
+	var form FormResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"steps": {"3": "Оплата", "1": "Согласование", "2": "Закупка"},
+		"fields": [
+			{"id":1,"type":"text","name":"Описание","info":{"required_step":1,"immutable_step":2}},
+			{"id":2,"type":"title","name":"Закупка","info":{"required_step":0,"immutable_step":0,"fields":[
+				{"id":3,"type":"money","name":"Сумма","info":{"required_step":2,"immutable_step":3}}
+			]}},
+			{"id":4,"type":"table","name":"Товары","info":{"required_step":0,"immutable_step":0,"columns":[
+				{"id":5,"type":"text","name":"Название","info":{"required_step":3,"immutable_step":0}}
+			]}}
+		]
+	}`), &form))
+
+	assert.Equal(t, []Step{{1, "Согласование"}, {2, "Закупка"}, {3, "Оплата"}}, form.Steps.Ordered())
+
+	step, ok := form.Steps.ByName("Закупка")
+	require.True(t, ok)
+	assert.Equal(t, 2, step.Number)
+	_, ok = form.Steps.ByName("Нет такого")
+	assert.False(t, ok)
+
+	ids := func(fields []*FormField) []int {
+		var ids []int
+		for _, f := range fields {
+			ids = append(ids, f.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []int{1}, ids(form.RequiredFields(1)))
+	assert.Equal(t, []int{1, 3, 5}, ids(form.RequiredFields(3)))
+	assert.Equal(t, []int{1}, ids(form.ImmutableFields(2)))
+	assert.Equal(t, []int{1, 3}, ids(form.ImmutableFields(3)))
+}
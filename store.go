@@ -0,0 +1,177 @@
+package pyrus
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrTaskNotFound is returned by TaskStore when there is no task with such id.
+var ErrTaskNotFound = errors.New("task not found")
+
+// TaskStore keeps local snapshots of tasks, so applications can query them without hitting the API.
+// Use WithSyncTaskStore to keep the store up to date with SyncEngine.
+type TaskStore interface {
+	Get(taskID int) (*Task, error)
+	Upsert(task *Task) error
+	List(formID int) ([]*Task, error)
+}
+
+// MemoryTaskStore is an in-memory TaskStore.
+type MemoryTaskStore struct {
+	mu    sync.RWMutex
+	tasks map[int]*Task
+}
+
+// NewMemoryTaskStore returns an empty MemoryTaskStore.
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{
+		tasks: make(map[int]*Task),
+	}
+}
+
+// Get returns the task by id or ErrTaskNotFound.
+func (s *MemoryTaskStore) Get(taskID int) (*Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	return t, nil
+}
+
+// Upsert adds or replaces the task.
+func (s *MemoryTaskStore) Upsert(task *Task) error {
+	if task == nil || task.TaskHeader == nil {
+		return errors.New("task without header cannot be stored")
+	}
+
+	s.mu.Lock()
+	s.tasks[task.ID] = task
+	s.mu.Unlock()
+
+	return nil
+}
+
+// List returns all tasks of the form ordered by id.
+func (s *MemoryTaskStore) List(formID int) ([]*Task, error) {
+	s.mu.RLock()
+	tasks := make([]*Task, 0)
+	for _, t := range s.tasks {
+		if t.FormID == formID {
+			tasks = append(tasks, t)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+
+	return tasks, nil
+}
+
+// SQLTaskStore is an example TaskStore backed by database/sql which keeps tasks as JSON documents.
+// Queries use $N placeholders and ON CONFLICT clause, so it works with PostgreSQL and SQLite out of the box.
+// The table could be created with CreateTable:
+//
+//	CREATE TABLE IF NOT EXISTS pyrus_tasks (
+//		task_id BIGINT PRIMARY KEY,
+//		form_id BIGINT NOT NULL,
+//		data TEXT NOT NULL
+//	)
+type SQLTaskStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLTaskStore returns an instance of SQLTaskStore working with the table (pyrus_tasks if empty).
+// The table name is put into queries as is, so it must come from a trusted source.
+func NewSQLTaskStore(db *sql.DB, table string) *SQLTaskStore {
+	if table == "" {
+		table = "pyrus_tasks"
+	}
+
+	return &SQLTaskStore{
+		db:    db,
+		table: table,
+	}
+}
+
+// CreateTable creates the table if it doesn't exist.
+func (s *SQLTaskStore) CreateTable() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+		task_id BIGINT PRIMARY KEY,
+		form_id BIGINT NOT NULL,
+		data TEXT NOT NULL
+	)`)
+	return err
+}
+
+// Get returns the task by id or ErrTaskNotFound.
+func (s *SQLTaskStore) Get(taskID int) (*Task, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM `+s.table+` WHERE task_id = $1`, taskID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// Upsert adds or replaces the task.
+func (s *SQLTaskStore) Upsert(task *Task) error {
+	if task == nil || task.TaskHeader == nil {
+		return errors.New("task without header cannot be stored")
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO `+s.table+` (task_id, form_id, data) VALUES ($1, $2, $3)
+		ON CONFLICT (task_id) DO UPDATE SET form_id = excluded.form_id, data = excluded.data`,
+		task.ID, task.FormID, string(data),
+	)
+	return err
+}
+
+// List returns all tasks of the form ordered by id.
+func (s *SQLTaskStore) List(formID int) ([]*Task, error) {
+	rows, err := s.db.Query(`SELECT data FROM `+s.table+` WHERE form_id = $1 ORDER BY task_id`, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	tasks := make([]*Task, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var task Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,321 @@
+package pyrus
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTaskStore(t *testing.T) {
+	store := NewMemoryTaskStore()
+	engine := NewSyncEngine(cl, WithSyncTaskStore(store))
+	require.NoError(t, engine.Sync(formID, func(*TaskChange) error { return nil }))
+
+	tasks, err := store.List(123456)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, 123456, tasks[0].ID)
+
+	task, err := store.Get(123457)
+	require.NoError(t, err)
+	assert.Equal(t, 123457, task.ID)
+
+	_, err = store.Get(1)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+
+	assert.Error(t, store.Upsert(&Task{}))
+}
+
+func TestFormField_MarshalJSON(t *testing.T) {
+	b, err := os.ReadFile("testdata/task.json")
+	require.NoError(t, err)
+
+	var task TaskResponse
+	require.NoError(t, json.Unmarshal(b, &task))
+
+	// snapshots are stored as JSON, so decoding of encoded task must give the same task
+	b, err = json.Marshal(task)
+	require.NoError(t, err)
+
+	var decoded TaskResponse
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, task, decoded)
+}
+
+func TestSQLTaskStore(t *testing.T) {
+	db, fake := openFakeTaskDB(t)
+	store := NewSQLTaskStore(db, "")
+
+	require.NoError(t, store.CreateTable())
+	assert.True(t, fake.tables["pyrus_tasks"])
+
+	_, err := store.Get(1)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+
+	require.NoError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 2, Text: "second"}, FormID: 10}))
+	require.NoError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 1, Text: "first"}, FormID: 10}))
+	require.NoError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 3, Text: "other form"}, FormID: 20}))
+	// the conflicting insert replaces the stored task
+	require.NoError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 1, Text: "updated"}, FormID: 10}))
+	assert.Len(t, fake.rows, 3)
+	assert.Error(t, store.Upsert(&Task{}))
+
+	task, err := store.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", task.Text)
+
+	tasks, err := store.List(10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, 1, tasks[0].ID)
+	assert.Equal(t, 2, tasks[1].ID)
+
+	tasks, err = store.List(30)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	t.Run("custom table", func(t *testing.T) {
+		db, fake := openFakeTaskDB(t)
+		store := NewSQLTaskStore(db, "snapshots")
+
+		require.NoError(t, store.CreateTable())
+		require.NoError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 1}, FormID: 10}))
+		assert.True(t, fake.tables["snapshots"])
+		for _, query := range fake.queries {
+			assert.Contains(t, query, " snapshots ")
+		}
+	})
+
+	t.Run("broken rows", func(t *testing.T) {
+		db, fake := openFakeTaskDB(t)
+		store := NewSQLTaskStore(db, "")
+		require.NoError(t, store.CreateTable())
+
+		fake.rows[1] = fakeTaskRow{formID: 10, data: "{"}
+		_, err := store.Get(1)
+		assert.Error(t, err)
+		_, err = store.List(10)
+		assert.Error(t, err)
+
+		// NULL can't be scanned into string
+		fake.rows[1] = fakeTaskRow{formID: 10, data: nil}
+		_, err = store.Get(1)
+		assert.Error(t, err)
+		_, err = store.List(10)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing table", func(t *testing.T) {
+		db, _ := openFakeTaskDB(t)
+		store := NewSQLTaskStore(db, "")
+
+		_, err := store.Get(1)
+		assert.EqualError(t, err, "no such table: pyrus_tasks")
+		_, err = store.List(10)
+		assert.EqualError(t, err, "no such table: pyrus_tasks")
+		assert.EqualError(t, store.Upsert(&Task{TaskHeader: &TaskHeader{ID: 1}}), "no such table: pyrus_tasks")
+	})
+}
+
+// fakeTaskDriver is a database/sql driver which understands only the queries of SQLTaskStore.
+// Every DSN is a separate database.
+type fakeTaskDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeTaskDB
+}
+
+var taskDriver = &fakeTaskDriver{dbs: make(map[string]*fakeTaskDB)}
+
+func init() {
+	sql.Register("pyrus-fake-tasks", taskDriver)
+}
+
+func openFakeTaskDB(t *testing.T) (*sql.DB, *fakeTaskDB) {
+	fake := &fakeTaskDB{
+		tables: make(map[string]bool),
+		rows:   make(map[int64]fakeTaskRow),
+	}
+
+	taskDriver.mu.Lock()
+	taskDriver.dbs[t.Name()] = fake
+	taskDriver.mu.Unlock()
+
+	db, err := sql.Open("pyrus-fake-tasks", t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close() //nolint:errcheck
+	})
+
+	return db, fake
+}
+
+func (d *fakeTaskDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	db, ok := d.dbs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %q", name)
+	}
+
+	return &fakeTaskConn{db: db}, nil
+}
+
+type fakeTaskRow struct {
+	formID int64
+	data   driver.Value
+}
+
+type fakeTaskDB struct {
+	mu      sync.Mutex
+	tables  map[string]bool
+	rows    map[int64]fakeTaskRow
+	queries []string
+}
+
+var (
+	createTableQuery = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS (\w+) \(\s*task_id BIGINT PRIMARY KEY,\s*form_id BIGINT NOT NULL,\s*data TEXT NOT NULL\s*\)$`)
+	upsertQuery      = regexp.MustCompile(`^INSERT INTO (\w+) \(task_id, form_id, data\) VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(task_id\) DO UPDATE SET form_id = excluded.form_id, data = excluded.data$`)
+	getQuery         = regexp.MustCompile(`^SELECT data FROM (\w+) WHERE task_id = \$1$`)
+	listQuery        = regexp.MustCompile(`^SELECT data FROM (\w+) WHERE form_id = \$1 ORDER BY task_id$`)
+	placeholder      = regexp.MustCompile(`\$\d+`)
+)
+
+func (db *fakeTaskDB) exec(query string, args []driver.Value) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.queries = append(db.queries, query)
+	if m := createTableQuery.FindStringSubmatch(query); m != nil {
+		db.tables[m[1]] = true
+		return nil
+	}
+	if m := upsertQuery.FindStringSubmatch(query); m != nil {
+		if !db.tables[m[1]] {
+			return errors.New("no such table: " + m[1])
+		}
+		db.rows[args[0].(int64)] = fakeTaskRow{formID: args[1].(int64), data: args[2]}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected exec: %s", query)
+}
+
+func (db *fakeTaskDB) query(query string, args []driver.Value) ([]driver.Value, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.queries = append(db.queries, query)
+	if m := getQuery.FindStringSubmatch(query); m != nil {
+		if !db.tables[m[1]] {
+			return nil, errors.New("no such table: " + m[1])
+		}
+		row, ok := db.rows[args[0].(int64)]
+		if !ok {
+			return nil, nil
+		}
+		return []driver.Value{row.data}, nil
+	}
+	if m := listQuery.FindStringSubmatch(query); m != nil {
+		if !db.tables[m[1]] {
+			return nil, errors.New("no such table: " + m[1])
+		}
+		ids := make([]int64, 0)
+		for id, row := range db.rows {
+			if row.formID == args[0].(int64) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return ids[i] < ids[j]
+		})
+
+		values := make([]driver.Value, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, db.rows[id].data)
+		}
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+type fakeTaskConn struct {
+	db *fakeTaskDB
+}
+
+func (c *fakeTaskConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeTaskStmt{db: c.db, query: strings.TrimSpace(query)}, nil
+}
+
+func (c *fakeTaskConn) Close() error {
+	return nil
+}
+
+func (c *fakeTaskConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeTaskStmt struct {
+	db    *fakeTaskDB
+	query string
+}
+
+func (s *fakeTaskStmt) Close() error {
+	return nil
+}
+
+// NumInput makes database/sql check that every $N placeholder gets its argument.
+func (s *fakeTaskStmt) NumInput() int {
+	return len(placeholder.FindAllString(s.query, -1))
+}
+
+func (s *fakeTaskStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.db.exec(s.query, args); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeTaskStmt) Query(args []driver.Value) (driver.Rows, error) {
+	values, err := s.db.query(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeTaskRows{values: values}, nil
+}
+
+// fakeTaskRows returns a single data column.
+type fakeTaskRows struct {
+	values []driver.Value
+}
+
+func (r *fakeTaskRows) Columns() []string {
+	return []string{"data"}
+}
+
+func (r *fakeTaskRows) Close() error {
+	return nil
+}
+
+func (r *fakeTaskRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
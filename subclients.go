@@ -0,0 +1,197 @@
+package pyrus
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// FormsAPI groups methods working with forms. It's returned by Client.FormsAPI.
+type FormsAPI interface {
+	List() (*FormsResponse, error)
+	Get(formID int) (*FormResponse, error)
+	Registry(formID int, req *RegistryRequest) (*FormRegisterResponse, error)
+}
+
+// TasksAPI groups methods working with tasks and task lists. It's returned by Client.TasksAPI.
+type TasksAPI interface {
+	Get(taskID int) (*TaskResponse, error)
+	Create(req *TaskRequest) (*TaskResponse, error)
+	Comment(taskID int, req *TaskCommentRequest) (*TaskResponse, error)
+	DownloadPrintForm(taskID, printFormID int) (*DownloadResponse, error)
+	Lists() (*ListsResponses, error)
+	List(listID, itemCount int, includeArchived bool) (*TaskListResponse, error)
+	Inbox(itemCount int) (*TaskListResponse, error)
+}
+
+// AnnouncementsAPI groups methods working with announcements. It's returned by Client.AnnouncementsAPI.
+type AnnouncementsAPI interface {
+	Get(announcementID int) (*AnnouncementResponse, error)
+	Create(req *AnnouncementRequest) (*AnnouncementResponse, error)
+	Comment(announcementID int, req *AnnouncementCommentRequest) (*AnnouncementResponse, error)
+}
+
+// FilesAPI groups methods working with files. It's returned by Client.FilesAPI.
+type FilesAPI interface {
+	Upload(name string, file io.Reader) (*UploadResponse, error)
+	Download(fileID int) (*DownloadResponse, error)
+}
+
+// CatalogsAPI groups methods working with catalogs. It's returned by Client.CatalogsAPI.
+type CatalogsAPI interface {
+	List() (*CatalogsResponse, error)
+	Get(catalogID int) (*CatalogResponse, error)
+	Create(name string, headers []string, items []*CatalogItem) (*CatalogResponse, error)
+	Sync(catalogID int, apply bool, headers []string, items []*CatalogItem) (*SyncCatalogResponse, error)
+}
+
+// MembersAPI groups methods working with members, roles and contacts of organization. It's returned by Client.MembersAPI.
+type MembersAPI interface {
+	List() (*MembersResponse, error)
+	Create(req *MemberRequest) (*Member, error)
+	Update(memberID int, req *MemberRequest) (*Member, error)
+	Block(memberID int) (*Member, error)
+	Roles() (*RolesResponse, error)
+	CreateRole(name string, members []int) (*Role, error)
+	UpdateRole(roleID int, name string, add, remove []int, banned bool) (*Role, error)
+	Contacts(includeInactive bool) (*ContactsResponse, error)
+	Profile() (*ProfileResponse, error)
+}
+
+// CallsAPI groups methods of calls API. It's returned by Client.CallsAPI.
+type CallsAPI interface {
+	Register(req *RegisterCallRequest) (*RegisterCallResponse, error)
+	AddDetails(callGUID string, req *AddCallDetailsRequest) error
+	RegisterEvent(callGUID string, eventType CallEventType, extension string) error
+}
+
+// WebhooksAPI groups methods receiving webhooks. It's returned by Client.WebhooksAPI.
+type WebhooksAPI interface {
+	Handler() (http.HandlerFunc, <-chan Event)
+	HTTPHandler() (http.Handler, <-chan Event)
+	Mount(path string, mux WebhookMux) <-chan Event
+	Parse(body []byte, signature string) (*Event, error)
+	Run(ctx context.Context, addr string, fn func(Event), opts ...WebhookServerOption) error
+}
+
+// FormsAPI returns methods working with forms.
+func (c *Client) FormsAPI() FormsAPI { return formsAPI{c} }
+
+// TasksAPI returns methods working with tasks and task lists.
+func (c *Client) TasksAPI() TasksAPI { return tasksAPI{c} }
+
+// AnnouncementsAPI returns methods working with announcements.
+func (c *Client) AnnouncementsAPI() AnnouncementsAPI { return announcementsAPI{c} }
+
+// FilesAPI returns methods working with files.
+func (c *Client) FilesAPI() FilesAPI { return filesAPI{c} }
+
+// CatalogsAPI returns methods working with catalogs.
+func (c *Client) CatalogsAPI() CatalogsAPI { return catalogsAPI{c} }
+
+// MembersAPI returns methods working with members, roles and contacts.
+func (c *Client) MembersAPI() MembersAPI { return membersAPI{c} }
+
+// CallsAPI returns methods of calls API.
+func (c *Client) CallsAPI() CallsAPI { return callsAPI{c} }
+
+// WebhooksAPI returns methods receiving webhooks.
+func (c *Client) WebhooksAPI() WebhooksAPI { return webhooksAPI{c} }
+
+type formsAPI struct{ c *Client }
+
+func (a formsAPI) List() (*FormsResponse, error)         { return a.c.Forms() }
+func (a formsAPI) Get(formID int) (*FormResponse, error) { return a.c.Form(formID) }
+func (a formsAPI) Registry(formID int, req *RegistryRequest) (*FormRegisterResponse, error) {
+	return a.c.Registry(formID, req)
+}
+
+type tasksAPI struct{ c *Client }
+
+func (a tasksAPI) Get(taskID int) (*TaskResponse, error)          { return a.c.Task(taskID) }
+func (a tasksAPI) Create(req *TaskRequest) (*TaskResponse, error) { return a.c.CreateTask(req) }
+func (a tasksAPI) Comment(taskID int, req *TaskCommentRequest) (*TaskResponse, error) {
+	return a.c.CommentTask(taskID, req)
+}
+func (a tasksAPI) DownloadPrintForm(taskID, printFormID int) (*DownloadResponse, error) {
+	return a.c.DownloadPrintForm(taskID, printFormID)
+}
+func (a tasksAPI) Lists() (*ListsResponses, error) { return a.c.Lists() }
+func (a tasksAPI) List(listID, itemCount int, includeArchived bool) (*TaskListResponse, error) {
+	return a.c.TaskList(listID, itemCount, includeArchived)
+}
+func (a tasksAPI) Inbox(itemCount int) (*TaskListResponse, error) { return a.c.Inbox(itemCount) }
+
+type announcementsAPI struct{ c *Client }
+
+func (a announcementsAPI) Get(announcementID int) (*AnnouncementResponse, error) {
+	return a.c.Announcement(announcementID)
+}
+func (a announcementsAPI) Create(req *AnnouncementRequest) (*AnnouncementResponse, error) {
+	return a.c.CreateAnnouncement(req)
+}
+func (a announcementsAPI) Comment(announcementID int, req *AnnouncementCommentRequest) (*AnnouncementResponse, error) {
+	return a.c.CommentAnnouncement(announcementID, req)
+}
+
+type filesAPI struct{ c *Client }
+
+func (a filesAPI) Upload(name string, file io.Reader) (*UploadResponse, error) {
+	return a.c.UploadFile(name, file)
+}
+func (a filesAPI) Download(fileID int) (*DownloadResponse, error) { return a.c.DownloadFile(fileID) }
+
+type catalogsAPI struct{ c *Client }
+
+func (a catalogsAPI) List() (*CatalogsResponse, error)            { return a.c.Catalogs() }
+func (a catalogsAPI) Get(catalogID int) (*CatalogResponse, error) { return a.c.Catalog(catalogID) }
+func (a catalogsAPI) Create(name string, headers []string, items []*CatalogItem) (*CatalogResponse, error) {
+	return a.c.CreateCatalog(name, headers, items)
+}
+func (a catalogsAPI) Sync(catalogID int, apply bool, headers []string, items []*CatalogItem) (*SyncCatalogResponse, error) {
+	return a.c.SyncCatalog(catalogID, apply, headers, items)
+}
+
+type membersAPI struct{ c *Client }
+
+func (a membersAPI) List() (*MembersResponse, error)                   { return a.c.Members() }
+func (a membersAPI) Create(req *MemberRequest) (*Member, error)        { return a.c.CreateMember(req) }
+func (a membersAPI) Block(memberID int) (*Member, error)               { return a.c.BlockMember(memberID) }
+func (a membersAPI) Roles() (*RolesResponse, error)                    { return a.c.Roles() }
+func (a membersAPI) Profile() (*ProfileResponse, error)                { return a.c.Profile() }
+func (a membersAPI) Contacts(inactive bool) (*ContactsResponse, error) { return a.c.Contacts(inactive) }
+func (a membersAPI) Update(memberID int, req *MemberRequest) (*Member, error) {
+	return a.c.UpdateMember(memberID, req)
+}
+func (a membersAPI) CreateRole(name string, members []int) (*Role, error) {
+	return a.c.CreateRole(name, members)
+}
+func (a membersAPI) UpdateRole(roleID int, name string, add, remove []int, banned bool) (*Role, error) {
+	return a.c.UpdateRole(roleID, name, add, remove, banned)
+}
+
+type callsAPI struct{ c *Client }
+
+func (a callsAPI) Register(req *RegisterCallRequest) (*RegisterCallResponse, error) {
+	return a.c.RegisterCall(req)
+}
+func (a callsAPI) AddDetails(callGUID string, req *AddCallDetailsRequest) error {
+	return a.c.AddCallDetails(callGUID, req)
+}
+func (a callsAPI) RegisterEvent(callGUID string, eventType CallEventType, extension string) error {
+	return a.c.RegisterCallEvent(callGUID, eventType, extension)
+}
+
+type webhooksAPI struct{ c *Client }
+
+func (a webhooksAPI) Handler() (http.HandlerFunc, <-chan Event) { return a.c.WebhookHandler() }
+func (a webhooksAPI) HTTPHandler() (http.Handler, <-chan Event) { return a.c.WebhookHTTPHandler() }
+func (a webhooksAPI) Mount(path string, mux WebhookMux) <-chan Event {
+	return a.c.MountWebhook(path, mux)
+}
+func (a webhooksAPI) Parse(body []byte, signature string) (*Event, error) {
+	return a.c.ParseWebhook(body, signature)
+}
+func (a webhooksAPI) Run(ctx context.Context, addr string, fn func(Event), opts ...WebhookServerOption) error {
+	return a.c.RunWebhookServer(ctx, addr, fn, opts...)
+}
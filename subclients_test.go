@@ -0,0 +1,37 @@
+package pyrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_subclients(t *testing.T) {
+	c := cl.(*Client)
+
+	task, err := c.TasksAPI().Get(taskID)
+	require.NoError(t, err)
+	assert.NotZero(t, task.Task.ID)
+
+	_, err = c.FormsAPI().Registry(formID, &RegistryRequest{})
+	assert.NoError(t, err)
+
+	file, err := c.FilesAPI().Download(fileID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, file.RawFile)
+
+	_, err = c.MembersAPI().Profile()
+	assert.NoError(t, err)
+	_, err = c.TasksAPI().Lists()
+	assert.NoError(t, err)
+}
+
+// Consumers could depend on the part of IClient
+var (
+	_ FormService    = (*Client)(nil)
+	_ TaskService    = (*Client)(nil)
+	_ CatalogService = (*Client)(nil)
+	_ MemberService  = (*Client)(nil)
+	_ IClient        = (*Client)(nil)
+)
@@ -0,0 +1,300 @@
+package pyrus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeType is a type of task change detected by SyncEngine.
+type ChangeType string
+
+const (
+	ChangeTypeCreated ChangeType = "created"
+	ChangeTypeUpdated ChangeType = "updated"
+)
+
+// TaskChange is emitted by SyncEngine for every created or modified task.
+type TaskChange struct {
+	FormID int
+	Type   ChangeType
+	Task   *Task
+}
+
+// Checkpoint is a sync state of the form.
+type Checkpoint struct {
+	// ModifiedAfter is the last_modified_date of the latest processed task.
+	ModifiedAfter time.Time `json:"modified_after"`
+	// Seen contains last_modified_date of tasks processed within the overlap window, used for deduplication.
+	Seen map[int]time.Time `json:"seen"`
+}
+
+// CheckpointStore persists sync state between SyncEngine runs.
+// Load should return nil checkpoint without error if there is no saved state yet.
+type CheckpointStore interface {
+	Load(formID int) (*Checkpoint, error)
+	Save(formID int, cp *Checkpoint) error
+}
+
+// MemoryCheckpointStore is an in-memory CheckpointStore. State is lost on restart, so use it for tests
+// or for processes that are fine with full resync on start.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[int]*Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[int]*Checkpoint),
+	}
+}
+
+// Load returns a copy of the saved checkpoint.
+func (s *MemoryCheckpointStore) Load(formID int) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp, ok := s.checkpoints[formID]
+	if !ok {
+		return nil, nil
+	}
+
+	return cp.copy(), nil
+}
+
+// Save saves a copy of the checkpoint.
+func (s *MemoryCheckpointStore) Save(formID int, cp *Checkpoint) error {
+	s.mu.Lock()
+	s.checkpoints[formID] = cp.copy()
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (cp *Checkpoint) copy() *Checkpoint {
+	seen := make(map[int]time.Time, len(cp.Seen))
+	for k, v := range cp.Seen {
+		seen[k] = v
+	}
+
+	return &Checkpoint{
+		ModifiedAfter: cp.ModifiedAfter,
+		Seen:          seen,
+	}
+}
+
+// SyncEngine incrementally fetches tasks changed since the last run using form registry.
+// Every run re-reads an overlap window before the checkpoint to survive clock skew between Pyrus servers,
+// while already processed tasks are deduplicated, so each change is emitted exactly once.
+type SyncEngine struct {
+	client  IClient
+	store   CheckpointStore
+	overlap time.Duration
+	request RegistryRequest
+	onError func(formID int, err error)
+	tasks   TaskStore
+}
+
+// SyncOption helps to create an option for SyncEngine.
+type SyncOption func(*SyncEngine)
+
+// WithCheckpointStore allows to persist checkpoints in own storage. MemoryCheckpointStore is used by default.
+func WithCheckpointStore(s CheckpointStore) SyncOption {
+	return func(e *SyncEngine) {
+		e.store = s
+	}
+}
+
+// WithSyncOverlap allows to override default overlap window of 5 minutes.
+func WithSyncOverlap(d time.Duration) SyncOption {
+	return func(e *SyncEngine) {
+		e.overlap = d
+	}
+}
+
+// WithSyncRegistryRequest allows to pass base registry request, e.g. with FieldIDs or field filters.
+// Modified date filters are managed by SyncEngine and always overridden.
+func WithSyncRegistryRequest(req RegistryRequest) SyncOption {
+	return func(e *SyncEngine) {
+		e.request = req
+	}
+}
+
+// WithSyncErrorHandler allows Run to continue after failed syncs, passing errors to the handler.
+// Without it Run stops on the first error.
+func WithSyncErrorHandler(fn func(formID int, err error)) SyncOption {
+	return func(e *SyncEngine) {
+		e.onError = fn
+	}
+}
+
+// WithSyncTaskStore allows to save every changed task into the store before passing it to the handler.
+func WithSyncTaskStore(s TaskStore) SyncOption {
+	return func(e *SyncEngine) {
+		e.tasks = s
+	}
+}
+
+// NewSyncEngine returns an instance of SyncEngine.
+func NewSyncEngine(client IClient, opts ...SyncOption) *SyncEngine {
+	e := &SyncEngine{
+		client:  client,
+		store:   NewMemoryCheckpointStore(),
+		overlap: 5 * time.Minute,
+		request: RegistryRequest{IncludeArchived: true},
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Sync fetches tasks of the form changed since the saved checkpoint and passes them to fn in the order of modification.
+// If fn returns an error, sync stops, progress up to the failed task is saved and the error is returned.
+func (e *SyncEngine) Sync(formID int, fn func(change *TaskChange) error) error {
+	return e.SyncContext(context.Background(), formID, fn)
+}
+
+// SyncContext is like Sync, but stops passing tasks to fn as soon as the context is done,
+// progress up to the last passed task is saved and the context error is returned.
+func (e *SyncEngine) SyncContext(ctx context.Context, formID int, fn func(change *TaskChange) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cp, err := e.store.Load(formID)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &Checkpoint{}
+	}
+	if cp.Seen == nil {
+		cp.Seen = make(map[int]time.Time)
+	}
+
+	req := e.request
+	req.ModifiedBefore = nil
+	req.ModifiedAfter = nil
+
+	var since time.Time
+	if !cp.ModifiedAfter.IsZero() {
+		since = cp.ModifiedAfter.Add(-e.overlap)
+		req.ModifiedAfter = &since
+	}
+
+	resp, err := e.client.Registry(formID, &req)
+	if err != nil {
+		return err
+	}
+
+	tasks := make([]*Task, 0, len(resp.Tasks))
+	for _, t := range resp.Tasks {
+		if t != nil && t.TaskHeader != nil {
+			tasks = append(tasks, t)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		mi, mj := taskModifiedDate(tasks[i]), taskModifiedDate(tasks[j])
+		if mi.Equal(mj) {
+			return tasks[i].ID < tasks[j].ID
+		}
+		return mi.Before(mj)
+	})
+
+	var fnErr error
+	for _, t := range tasks {
+		if fnErr = ctx.Err(); fnErr != nil {
+			break
+		}
+
+		modified := taskModifiedDate(t)
+		seenModified, seen := cp.Seen[t.ID]
+		if seen && !modified.After(seenModified) {
+			continue
+		}
+
+		change := &TaskChange{
+			FormID: formID,
+			Type:   ChangeTypeUpdated,
+			Task:   t,
+		}
+		if !seen && (since.IsZero() || !t.CreateDate.Before(since)) {
+			change.Type = ChangeTypeCreated
+		}
+
+		if e.tasks != nil {
+			if fnErr = e.tasks.Upsert(t); fnErr != nil {
+				break
+			}
+		}
+		if fnErr = fn(change); fnErr != nil {
+			break
+		}
+
+		cp.Seen[t.ID] = modified
+		if modified.After(cp.ModifiedAfter) {
+			cp.ModifiedAfter = modified
+		}
+	}
+
+	// Forget tasks which left the overlap window
+	threshold := cp.ModifiedAfter.Add(-e.overlap)
+	for id, modified := range cp.Seen {
+		if modified.Before(threshold) {
+			delete(cp.Seen, id)
+		}
+	}
+
+	if err := e.store.Save(formID, cp); err != nil {
+		return err
+	}
+
+	return fnErr
+}
+
+// Run syncs the forms every interval until the context is done.
+func (e *SyncEngine) Run(ctx context.Context, interval time.Duration, fn func(change *TaskChange) error, formIDs ...int) error {
+	if interval <= 0 {
+		return fmt.Errorf("sync interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	closed := clientDone(e.client)
+	for {
+		for _, formID := range formIDs {
+			if err := e.SyncContext(ctx, formID, fn); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				if e.onError == nil {
+					return err
+				}
+				e.onError(formID, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-closed:
+			return ErrClientClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func taskModifiedDate(t *Task) time.Time {
+	if t.LastModifiedDate != nil {
+		return *t.LastModifiedDate
+	}
+
+	return t.CreateDate
+}
@@ -0,0 +1,91 @@
+package pyrus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncEngine_Sync(t *testing.T) {
+	store := NewMemoryCheckpointStore()
+	engine := NewSyncEngine(cl, WithCheckpointStore(store), WithSyncOverlap(time.Minute))
+
+	var changes []*TaskChange
+	collect := func(change *TaskChange) error {
+		changes = append(changes, change)
+		return nil
+	}
+
+	t.Run("first run emits every task in order of modification", func(t *testing.T) {
+		require.NoError(t, engine.Sync(formID, collect))
+		require.Len(t, changes, 3)
+		assert.Equal(t, []int{123457, 123458, 123456}, []int{changes[0].Task.ID, changes[1].Task.ID, changes[2].Task.ID})
+		for _, change := range changes {
+			assert.Equal(t, ChangeTypeCreated, change.Type)
+			assert.Equal(t, formID, change.FormID)
+		}
+
+		cp, err := store.Load(formID)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, 8, 2, 10, 0, 0, 0, time.UTC), cp.ModifiedAfter.UTC())
+		// only the latest task is inside the overlap window
+		assert.Len(t, cp.Seen, 1)
+	})
+
+	t.Run("second run deduplicates already processed tasks", func(t *testing.T) {
+		changes = nil
+		require.NoError(t, engine.Sync(formID, collect))
+		// fake server ignores modified_after, so older tasks come back and are emitted as updates
+		require.Len(t, changes, 2)
+		for _, change := range changes {
+			assert.Equal(t, ChangeTypeUpdated, change.Type)
+			assert.NotEqual(t, 123456, change.Task.ID)
+		}
+	})
+
+	t.Run("progress is saved on handler error", func(t *testing.T) {
+		engine := NewSyncEngine(cl)
+		testErr := errors.New("test")
+		calls := 0
+		err := engine.Sync(formID, func(change *TaskChange) error {
+			calls++
+			if calls == 2 {
+				return testErr
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, testErr)
+
+		cp, err := engine.store.Load(formID)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, 8, 1, 9, 30, 0, 0, time.UTC), cp.ModifiedAfter.UTC())
+	})
+
+	t.Run("canceled sync saves progress", func(t *testing.T) {
+		engine := NewSyncEngine(cl)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := 0
+		err := engine.SyncContext(ctx, formID, func(change *TaskChange) error {
+			calls++
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+
+		cp, err := engine.store.Load(formID)
+		require.NoError(t, err)
+		assert.Len(t, cp.Seen, 1)
+	})
+}
+
+func TestSyncEngine_Run_invalidInterval(t *testing.T) {
+	engine := NewSyncEngine(cl)
+	err := engine.Run(context.Background(), 0, func(*TaskChange) error { return nil }, formID)
+	assert.EqualError(t, err, "sync interval must be positive, got 0s")
+}
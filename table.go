@@ -0,0 +1,110 @@
+package pyrus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TableColumns allows to access table cells by column name or code instead of the positional index,
+// so reordering of columns in the form doesn't break integrations.
+type TableColumns struct {
+	columns []*FormField
+	byKey   map[string]*FormField
+}
+
+// NewTableColumns returns columns of the table field definition taken from FormResponse.Fields.
+// Use FindTableColumns to search the definition by table id, name or code.
+func NewTableColumns(definition *FormField) (*TableColumns, error) {
+	if definition == nil || definition.Info == nil || len(definition.Info.Columns) == 0 {
+		return nil, errors.New("table definition with columns was expected")
+	}
+
+	c := &TableColumns{
+		columns: definition.Info.Columns,
+		byKey:   make(map[string]*FormField, 2*len(definition.Info.Columns)),
+	}
+	for _, col := range definition.Info.Columns {
+		if col == nil {
+			continue
+		}
+		if col.Name != "" {
+			c.byKey[col.Name] = col
+		}
+		if col.Info != nil && col.Info.Code != "" {
+			c.byKey[col.Info.Code] = col
+		}
+	}
+
+	return c, nil
+}
+
+// FindTableColumns searches the table field in the form definition by id, name or code and returns its columns.
+func (r *FormResponse) FindTableColumns(key string) (*TableColumns, error) {
+	definition := r.findDefinitionField(key, func(f *FormField) bool {
+		return f.Type == FieldTypeTable
+	})
+	if definition == nil {
+		return nil, fmt.Errorf("table %q not found", key)
+	}
+
+	return NewTableColumns(definition)
+}
+
+// Column returns the column definition by name or code.
+func (c *TableColumns) Column(key string) (*FormField, bool) {
+	col, ok := c.byKey[key]
+	return col, ok
+}
+
+// Cell returns the cell of the row by column name or code or nil if it's not filled.
+func (c *TableColumns) Cell(row *TableRow, key string) *FormField {
+	col, ok := c.byKey[key]
+	if !ok || row == nil {
+		return nil
+	}
+
+	for _, cell := range row.Cells {
+		if cell != nil && cell.ID == col.ID {
+			return cell
+		}
+	}
+
+	return nil
+}
+
+// SetCell sets the value of the row cell by column name or code adding the cell if it doesn't exist.
+func (c *TableColumns) SetCell(row *TableRow, key string, value interface{}) error {
+	if row == nil {
+		return errors.New("row cannot be nil")
+	}
+
+	col, ok := c.byKey[key]
+	if !ok {
+		return fmt.Errorf("column %q not found", key)
+	}
+
+	if cell := c.Cell(row, key); cell != nil {
+		cell.Value = value
+		return nil
+	}
+
+	row.Cells = append(row.Cells, &FormField{
+		ID:    col.ID,
+		Type:  col.Type,
+		Value: value,
+	})
+
+	return nil
+}
+
+// NewRow returns a new row with cells filled from values keyed by column name or code.
+func (c *TableColumns) NewRow(rowID int, values map[string]interface{}) (*TableRow, error) {
+	row := &TableRow{RowID: rowID}
+	for key, value := range values {
+		if err := c.SetCell(row, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,48 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableColumns(t *testing.T) {
+	var form FormResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"fields": [{"id":1,"type":"table","name":"Товары","info":{"columns":[
+			{"id":2,"type":"text","name":"Название"},
+			{"id":3,"type":"money","name":"Цена","info":{"code":"price"}}
+		]}}]
+	}`), &form))
+
+	columns, err := form.FindTableColumns("Товары")
+	require.NoError(t, err)
+
+	var task FormField
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"type":"table","value":[
+		{"row_id":0,"cells":[{"id":3,"type":"money","value":100},{"id":2,"type":"text","value":"Стол"}]}
+	]}`), &task))
+	table := task.Value.(Table)
+
+	assert.Equal(t, "Стол", columns.Cell(table[0], "Название").Value)
+	assert.Equal(t, 100.0, columns.Cell(table[0], "price").Value)
+	assert.Nil(t, columns.Cell(table[0], "Нет такой"))
+
+	require.NoError(t, columns.SetCell(table[0], "Цена", 150))
+	assert.Equal(t, 150, columns.Cell(table[0], "price").Value)
+	assert.Error(t, columns.SetCell(table[0], "Нет такой", 1))
+	assert.EqualError(t, columns.SetCell(nil, "Цена", 1), "row cannot be nil")
+
+	row, err := columns.NewRow(1, map[string]interface{}{"Название": "Стул", "price": 50})
+	require.NoError(t, err)
+	assert.Len(t, row.Cells, 2)
+	assert.Equal(t, 3, columns.Cell(row, "Цена").ID)
+
+	_, err = form.FindTableColumns("1")
+	assert.NoError(t, err)
+	_, err = form.FindTableColumns("Нет такой")
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateParamRe matches ${name} placeholders of task templates.
+var templateParamRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// TaskTemplates is a registry of named TaskRequest skeletons: form, default fields, approvals, etc.
+// String values of templates may contain ${name} placeholders substituted by Instantiate.
+// A string consisting of a single placeholder is replaced with the parameter as is, so numbers,
+// persons and other values keep their types; otherwise the parameter is formatted into the string.
+type TaskTemplates struct {
+	mu        sync.RWMutex
+	templates map[string]json.RawMessage
+}
+
+// NewTaskTemplates returns an empty registry.
+func NewTaskTemplates() *TaskTemplates {
+	return &TaskTemplates{
+		templates: make(map[string]json.RawMessage),
+	}
+}
+
+// Register saves the request as a template with the name, replacing the previous one.
+func (t *TaskTemplates) Register(name string, req *TaskRequest) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.templates[name] = b
+	t.mu.Unlock()
+
+	return nil
+}
+
+// LoadJSON registers templates from JSON object mapping names to requests in the API format.
+// Values of typed fields may be placeholders, they are checked only when the template is instantiated:
+//
+//	{"purchase": {"form_id": 1, "fields": [{"id": 2, "type": "money", "value": "${amount}"}]}}
+func (t *TaskTemplates) LoadJSON(r io.Reader) error {
+	var templates map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&templates); err != nil {
+		return err
+	}
+
+	t.load(templates)
+	return nil
+}
+
+// LoadYAML is like LoadJSON, but templates are written in YAML:
+//
+//	purchase:
+//	  form_id: 1
+//	  fields:
+//	    - {id: 2, type: money, value: "${amount}"}
+func (t *TaskTemplates) LoadYAML(r io.Reader) error {
+	var decoded map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&decoded); err != nil {
+		return err
+	}
+
+	// Templates are kept in JSON, so they are instantiated the same way as loaded by LoadJSON
+	templates := make(map[string]json.RawMessage, len(decoded))
+	for name, tmpl := range decoded {
+		b, err := json.Marshal(tmpl)
+		if err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+		templates[name] = b
+	}
+
+	t.load(templates)
+	return nil
+}
+
+// load registers decoded templates.
+func (t *TaskTemplates) load(templates map[string]json.RawMessage) {
+	t.mu.Lock()
+	for name, b := range templates {
+		t.templates[name] = b
+	}
+	t.mu.Unlock()
+}
+
+// Names returns sorted names of registered templates.
+func (t *TaskTemplates) Names() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	names := make([]string, 0, len(t.templates))
+	for name := range t.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Instantiate returns a new request from the template with placeholders replaced by the parameters.
+// Missing parameters are reported as an error.
+func (t *TaskTemplates) Instantiate(name string, params map[string]interface{}) (*TaskRequest, error) {
+	t.mu.RLock()
+	b, ok := t.templates[name]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("task template %q is not registered", name)
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+	tree, err := substituteParams(tree, params)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+
+	b, err = json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var req TaskRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// substituteParams replaces placeholders in the decoded JSON tree.
+func substituteParams(v interface{}, params map[string]interface{}) (interface{}, error) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, child := range node {
+			replaced, err := substituteParams(child, params)
+			if err != nil {
+				return nil, err
+			}
+			node[k] = replaced
+		}
+		// Substituted values of typed fields are converted to the API format, e.g. person id into the object
+		if fieldType, ok := node["type"].(string); ok && node["value"] != nil {
+			value, err := CoerceFieldValue(FieldType(fieldType), node["value"])
+			if err != nil {
+				return nil, err
+			}
+			node["value"] = value
+		}
+		return node, nil
+	case []interface{}:
+		for i, child := range node {
+			replaced, err := substituteParams(child, params)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = replaced
+		}
+		return node, nil
+	case string:
+		if m := templateParamRe.FindStringSubmatchIndex(node); m != nil && m[0] == 0 && m[1] == len(node) {
+			param, ok := params[node[m[2]:m[3]]]
+			if !ok {
+				return nil, fmt.Errorf("parameter %q is missing", node[m[2]:m[3]])
+			}
+			return param, nil
+		}
+
+		var missing string
+		s := templateParamRe.ReplaceAllStringFunc(node, func(placeholder string) string {
+			key := placeholder[2 : len(placeholder)-1]
+			param, ok := params[key]
+			if !ok {
+				missing = key
+				return placeholder
+			}
+			return fmt.Sprint(param)
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("parameter %q is missing", missing)
+		}
+		return s, nil
+	default:
+		return v, nil
+	}
+}
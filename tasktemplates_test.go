@@ -0,0 +1,87 @@
+package pyrus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTemplates(t *testing.T) {
+	templates := NewTaskTemplates()
+	require.NoError(t, templates.LoadJSON(strings.NewReader(`{
+		"purchase": {
+			"form_id": 1,
+			"subject": "Закупка ${item} для ${department}",
+			"fields": [
+				{"id": 2, "type": "money", "value": "${amount}"},
+				{"id": 3, "type": "person", "value": "${buyer}"}
+			],
+			"approvals": [[{"id": 10}]]
+		}
+	}`)))
+	require.NoError(t, templates.Register("note", &TaskRequest{Text: "${text}"}))
+	assert.Equal(t, []string{"note", "purchase"}, templates.Names())
+
+	req, err := templates.Instantiate("purchase", map[string]interface{}{
+		"item":       "бумаги",
+		"department": "бухгалтерии",
+		"amount":     1500,
+		"buyer":      &Person{ID: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, req.FormID)
+	assert.Equal(t, "Закупка бумаги для бухгалтерии", req.Subject)
+	require.Len(t, req.Fields, 2)
+	assert.Equal(t, 1500.0, req.Fields[0].Value)
+	assert.Equal(t, 5, req.Fields[1].Value.(*Person).ID)
+	assert.Equal(t, [][]*Person{{{ID: 10}}}, req.Approvals)
+
+	// Instances don't share state
+	req.Subject = "changed"
+	again, err := templates.Instantiate("purchase", map[string]interface{}{"item": "a", "department": "b", "amount": 1, "buyer": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Закупка a для b", again.Subject)
+
+	_, err = templates.Instantiate("note", nil)
+	assert.Error(t, err)
+	_, err = templates.Instantiate("unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestTaskTemplates_LoadYAML(t *testing.T) {
+	params := map[string]interface{}{"item": "a", "department": "b", "amount": 1500, "buyer": 5}
+
+	fromJSON := NewTaskTemplates()
+	require.NoError(t, fromJSON.LoadJSON(strings.NewReader(`{"purchase": {
+		"form_id": 1,
+		"subject": "Закупка ${item} для ${department}",
+		"fields": [{"id": 2, "type": "money", "value": "${amount}"}, {"id": 3, "type": "person", "value": "${buyer}"}],
+		"approvals": [[{"id": 10}]]
+	}}`)))
+	expected, err := fromJSON.Instantiate("purchase", params)
+	require.NoError(t, err)
+
+	templates := NewTaskTemplates()
+	require.NoError(t, templates.LoadYAML(strings.NewReader(`
+purchase:
+  form_id: 1
+  subject: Закупка ${item} для ${department}
+  fields:
+    - {id: 2, type: money, value: "${amount}"}
+    - id: 3
+      type: person
+      value: ${buyer}
+  approvals:
+    - [{id: 10}]
+`)))
+	assert.Equal(t, []string{"purchase"}, templates.Names())
+
+	req, err := templates.Instantiate("purchase", params)
+	require.NoError(t, err)
+	assert.Equal(t, expected, req)
+
+	assert.Error(t, templates.LoadYAML(strings.NewReader("purchase: [")))
+	assert.Equal(t, []string{"purchase"}, templates.Names())
+}
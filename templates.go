@@ -0,0 +1,305 @@
+package pyrus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// CommentTemplate renders comment bodies with text/template using task and form field values.
+//
+// Besides the data passed to Render, templates have access to the following functions:
+//
+//	field SOURCE KEY   returns *FormField found by id, name or code (nil if there is no such field)
+//	value SOURCE KEY   returns field value formatted as a string
+//	date LAYOUT VALUE  formats time.Time, *time.Time or date field with the layout
+//	money VALUE        formats number, money field or float with two decimals and grouped thousands: 1 234 567.80
+//	person VALUE       returns a full name of *Person or person field
+//	escape STRING      escapes Markdown control characters, see EscapeMarkdown
+//
+// SOURCE is *TaskWithComments, *Task or []*FormField.
+//
+// Example:
+//
+//	Сумма заявки {{ money (field .Task "Сумма") }} до {{ date "02.01.2006" (field .Task "Срок") }}
+type CommentTemplate struct {
+	tmpl *template.Template
+}
+
+// CommentTemplateData is passed to the template during rendering.
+type CommentTemplateData struct {
+	Task *TaskWithComments
+	Data interface{}
+}
+
+// NewCommentTemplate parses the template text.
+func NewCommentTemplate(name, text string) (*CommentTemplate, error) {
+	tmpl, err := template.New(name).Funcs(commentTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template for the task and optional user data.
+func (t *CommentTemplate) Render(task *TaskWithComments, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, &CommentTemplateData{Task: task, Data: data}); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// Comment renders the template into the text of a new comment request.
+func (t *CommentTemplate) Comment(task *TaskWithComments, data interface{}) (*TaskCommentRequest, error) {
+	text, err := t.Render(task, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskCommentRequest{Text: text}, nil
+}
+
+// FormattedComment renders the template as Markdown into the formatted text of a new comment request.
+func (t *CommentTemplate) FormattedComment(task *TaskWithComments, data interface{}) (*TaskCommentRequest, error) {
+	text, err := t.Render(task, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskCommentRequest{FormattedText: MarkdownToFormattedText(text)}, nil
+}
+
+var commentTemplateFuncs = template.FuncMap{
+	"field":  templateField,
+	"value":  templateValue,
+	"date":   templateDate,
+	"money":  templateMoney,
+	"person": templatePerson,
+	"escape": EscapeMarkdown,
+}
+
+func templateField(source interface{}, key interface{}) (*FormField, error) {
+	var fields []*FormField
+	switch s := source.(type) {
+	case *TaskWithComments:
+		if s != nil && s.Task != nil {
+			fields = s.Fields
+		}
+	case *Task:
+		if s != nil {
+			fields = s.Fields
+		}
+	case []*FormField:
+		fields = s
+	case nil:
+	default:
+		return nil, fmt.Errorf("unsupported fields source %T", source)
+	}
+
+	switch k := key.(type) {
+	case int:
+		return findField(fields, func(f *FormField) bool { return f.ID == k }), nil
+	case string:
+		return findField(fields, func(f *FormField) bool {
+			return f.Name == k || (f.Info != nil && f.Info.Code != "" && f.Info.Code == k)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported field key %T", key)
+	}
+}
+
+func templateValue(source interface{}, key interface{}) (string, error) {
+	f, err := templateField(source, key)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatFieldValue(f), nil
+}
+
+func templateDate(layout string, v interface{}) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case *FormField:
+		if t == nil {
+			return "", nil
+		}
+		v, err := t.DecodedValue()
+		if err != nil || v == nil {
+			return "", err
+		}
+		return templateDate(layout, v)
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported date value %T", v)
+	}
+}
+
+func templateMoney(v interface{}) (string, error) {
+	switch n := v.(type) {
+	case float64:
+		return formatMoney(n), nil
+	case float32:
+		return formatMoney(float64(n)), nil
+	case int:
+		return formatMoney(float64(n)), nil
+	case int64:
+		return formatMoney(float64(n)), nil
+	case *FormField:
+		if n == nil {
+			return "", nil
+		}
+		v, err := n.DecodedValue()
+		if err != nil || v == nil {
+			return "", err
+		}
+		return templateMoney(v)
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported money value %T", v)
+	}
+}
+
+func templatePerson(v interface{}) (string, error) {
+	switch p := v.(type) {
+	case *Person:
+		if p == nil {
+			return "", nil
+		}
+		return mentionName(p), nil
+	case *FormField:
+		if p == nil {
+			return "", nil
+		}
+		v, err := p.DecodedValue()
+		if err != nil || v == nil {
+			return "", err
+		}
+		return templatePerson(v)
+	case nil:
+		return "", nil
+	default:
+		return "", errors.New("person value was expected")
+	}
+}
+
+// FormatFieldValue returns a human readable representation of the field value.
+func FormatFieldValue(f *FormField) string {
+	if f == nil {
+		return ""
+	}
+
+	value, err := f.DecodedValue()
+	if err != nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		switch f.Type {
+		case FieldTypeTime:
+			return v.Format("15:04")
+		case FieldTypeDueDateTime:
+			return v.Format("2006-01-02 15:04")
+		default:
+			return v.Format("2006-01-02")
+		}
+	case CheckmarkType:
+		return string(v)
+	case FlagType:
+		return string(v)
+	case StatusType:
+		return string(v)
+	case *Person:
+		return mentionName(v)
+	case *CatalogItem:
+		return strings.Join(v.Values, ", ")
+	case *MultipleChoice:
+		return strings.Join(v.ChoiceNames, ", ")
+	case *FormLink:
+		return v.Subject
+	case *Project:
+		names := make([]string, 0, len(v.Projects))
+		for _, project := range v.Projects {
+			names = append(names, project.Name)
+		}
+		return strings.Join(names, ", ")
+	case []*File:
+		names := make([]string, 0, len(v))
+		for _, file := range v {
+			names = append(names, file.Name)
+		}
+		return strings.Join(names, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// findField searches for the first field matching the predicate, including fields nested into titles and choices.
+func findField(fields []*FormField, match func(f *FormField) bool) *FormField {
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+		if match(f) {
+			return f
+		}
+
+		var nested []*FormField
+		value, _ := f.DecodedValue()
+		switch v := value.(type) {
+		case *Title:
+			nested = v.Fields
+		case *MultipleChoice:
+			nested = v.Fields
+		}
+		if found := findField(nested, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// formatMoney formats amount with two decimals and thousands separated by spaces.
+func formatMoney(amount float64) string {
+	s := strconv.FormatFloat(math.Abs(amount), 'f', 2, 64)
+	intPart, fracPart := s[:len(s)-3], s[len(s)-2:]
+
+	var b strings.Builder
+	if amount < 0 && s != "0.00" {
+		b.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString("." + fracPart)
+
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentTemplate(t *testing.T) {
+	b, err := os.ReadFile("testdata/task.json")
+	require.NoError(t, err)
+
+	var task TaskResponse
+	require.NoError(t, json.Unmarshal(b, &task))
+
+	tmpl, err := NewCommentTemplate("comment", `**{{ value .Task "Текст" }}**: {{ money (field .Task "Деньги") }} до {{ date "02.01.2006" (field .Task 9) }}, {{ escape .Data }}`)
+	require.NoError(t, err)
+
+	text, err := tmpl.Render(task.Task, "*клиент*")
+	require.NoError(t, err)
+	assert.Equal(t, `**Пример**: 1 000.00 до 01.08.2021, \*клиент\*`, text)
+
+	req, err := tmpl.FormattedComment(task.Task, "*клиент*")
+	require.NoError(t, err)
+	assert.Equal(t, "<p><b>Пример</b>: 1 000.00 до 01.08.2021, *клиент*</p>", req.FormattedText)
+
+	_, err = NewCommentTemplate("invalid", "{{ unknown }}")
+	assert.Error(t, err)
+
+	assert.Equal(t, "-1 234 567.80", formatMoney(-1234567.8))
+	assert.Equal(t, "0.00", formatMoney(0))
+}
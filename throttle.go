@@ -0,0 +1,117 @@
+package pyrus
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithAdaptiveThrottling allows to limit the request rate to maxRate requests per second. The rate is halved
+// after every 429 response and then ramps back up by a twentieth of maxRate after every successful response,
+// so long-running jobs run at the maximum rate Pyrus sustains without manual tuning.
+// The rate never drops below one request per minute.
+func WithAdaptiveThrottling(maxRate float64) Option {
+	return func(c *Client) {
+		c.throttle = &adaptiveThrottle{
+			rate:    maxRate,
+			maxRate: maxRate,
+		}
+	}
+}
+
+// ThrottleRate returns the current request rate per second of WithAdaptiveThrottling, zero if it's disabled.
+func (c *Client) ThrottleRate() float64 {
+	if c.throttle == nil {
+		return 0
+	}
+
+	c.throttle.mu.Lock()
+	defer c.throttle.mu.Unlock()
+
+	return c.throttle.rate
+}
+
+// minThrottleRate is the lowest rate of adaptive throttling, one request per minute.
+const minThrottleRate = 1.0 / 60
+
+// adaptiveThrottle spaces requests by the current rate, decreasing it multiplicatively on 429 responses
+// and increasing it additively otherwise.
+type adaptiveThrottle struct {
+	mu      sync.Mutex
+	rate    float64
+	maxRate float64
+	next    time.Time
+}
+
+// waitThrottle waits for the turn of the request if throttling is enabled.
+func (c *Client) waitThrottle(ctx context.Context) error {
+	if c.throttle == nil {
+		return nil
+	}
+
+	delay := c.throttle.reserve(c.clock.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-c.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.Done():
+		return ErrClientClosed
+	}
+}
+
+// observeThrottle adjusts the rate by the response status.
+func (c *Client) observeThrottle(status int, h http.Header) {
+	if c.throttle == nil || status == 0 {
+		return
+	}
+
+	var retryAfter time.Duration
+	if status == http.StatusTooManyRequests {
+		if seconds, ok := headerInt(h, "Retry-After"); ok {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	c.throttle.observe(status == http.StatusTooManyRequests, retryAfter, c.clock.Now())
+}
+
+// reserve returns how long the request has to wait for its turn.
+func (t *adaptiveThrottle) reserve(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(float64(time.Second) / t.rate))
+
+	return delay
+}
+
+func (t *adaptiveThrottle) observe(limited bool, retryAfter time.Duration, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !limited {
+		t.rate += t.maxRate / 20
+		if t.rate > t.maxRate {
+			t.rate = t.maxRate
+		}
+		return
+	}
+
+	t.rate /= 2
+	if t.rate < minThrottleRate {
+		t.rate = minThrottleRate
+	}
+	// Nothing is sent until the quota is restored
+	if resume := now.Add(retryAfter); t.next.Before(resume) {
+		t.next = resume
+	}
+}
@@ -0,0 +1,73 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAdaptiveThrottling(t *testing.T) {
+	var limited int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case atomic.LoadInt32(&limited) == 1:
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error_code":"too_many_requests"}`)) //nolint:errcheck
+		default:
+			w.Write([]byte(`{}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithClock(clock), WithAdaptiveThrottling(10))
+	require.NoError(t, err)
+
+	t.Run("requests are spaced by the rate", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			_, err := c.Profile()
+			require.NoError(t, err)
+		}
+		// Authorization and four requests, the first is sent immediately
+		assert.Equal(t, start.Add(400*time.Millisecond), clock.Now())
+		assert.Equal(t, 10.0, c.ThrottleRate())
+	})
+
+	t.Run("rate is halved on 429 and restored gradually", func(t *testing.T) {
+		atomic.StoreInt32(&limited, 1)
+		_, err := c.Profile()
+		assert.Error(t, err)
+		_, err = c.Profile()
+		assert.Error(t, err)
+		assert.Equal(t, 2.5, c.ThrottleRate())
+
+		// Next request waits for Retry-After
+		atomic.StoreInt32(&limited, 0)
+		before := clock.Now()
+		_, err = c.Profile()
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, clock.Now().Sub(before), 29*time.Second)
+		assert.Equal(t, 3.0, c.ThrottleRate())
+
+		for i := 0; i < 20; i++ {
+			_, err := c.Profile()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 10.0, c.ThrottleRate())
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		_, err := NewClient("login", "key", WithAdaptiveThrottling(0))
+		assert.Error(t, err)
+	})
+}
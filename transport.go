@@ -0,0 +1,45 @@
+package pyrus
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithTransportProfile allows to use internally created http.Client tuned with the profile instead of http.DefaultClient.
+// Overall request timeout is not set, so big uploads and downloads are not capped.
+func WithTransportProfile(p TransportProfile) Option {
+	return func(c *Client) {
+		c.httpClient = &http.Client{
+			Transport: newTransport(p),
+		}
+	}
+}
+
+func newTransport(p TransportProfile) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	switch p {
+	case TransportProfileHighThroughput:
+		t.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+		t.MaxIdleConns = 200
+		t.MaxIdleConnsPerHost = 100
+		t.IdleConnTimeout = 90 * time.Second
+		t.TLSHandshakeTimeout = 10 * time.Second
+	case TransportProfileLowLatency:
+		t.DialContext = (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 15 * time.Second,
+		}).DialContext
+		t.MaxIdleConns = 20
+		t.MaxIdleConnsPerHost = 10
+		t.IdleConnTimeout = 30 * time.Second
+		t.TLSHandshakeTimeout = 5 * time.Second
+		t.ResponseHeaderTimeout = 15 * time.Second
+	}
+
+	return t
+}
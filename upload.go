@@ -0,0 +1,130 @@
+package pyrus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"os"
+)
+
+// MaxUploadSize is the maximum size of a file accepted by Pyrus.
+const MaxUploadSize = 250 << 20
+
+// UploadOption helps to create an option for UploadFileWithOptions and UploadFileContext.
+type UploadOption func(*fileRequest)
+
+// WithUploadSize passes the size of the file, so it's sent with Content-Length, when it can't be found from the reader.
+// Sizes of *os.File, io.Seeker and readers with Len method (bytes.Reader, strings.Reader, bytes.Buffer) are found automatically.
+func WithUploadSize(size int64) UploadOption {
+	return func(r *fileRequest) {
+		r.Size = size
+	}
+}
+
+// checkUploadSize fails fast with ErrTooLargeRequestLength if the size of the file is known and exceeds MaxUploadSize.
+func (c *Client) checkUploadSize(size int64) error {
+	if size <= MaxUploadSize {
+		return nil
+	}
+
+	lang := c.errorLanguage
+	if lang == "" {
+		lang = LanguageEnglish
+	}
+
+	return Error{
+		Code:        ErrTooLargeRequestLength,
+		Description: ErrTooLargeRequestLength.Description(lang),
+	}
+}
+
+// writeMultipartFile writes the file as a multipart form and closes the writer.
+func (c *Client) writeMultipartFile(ctx context.Context, w *multipart.Writer, file *fileRequest) error {
+	fw, err := w.CreateFormFile("file", file.Filename)
+	if err != nil {
+		c.logger.Error("Error while creating a new form file!", err)
+		return err
+	}
+	if _, err := io.Copy(fw, &contextReader{ctx: ctx, r: file.Reader}); err != nil {
+		// Closed pipe or done context means the request was aborted, the error is reported by the transport
+		if err != io.ErrClosedPipe && err != ctx.Err() {
+			c.logger.Error("Error while writing a file!", err)
+		}
+		return err
+	}
+	if err := w.Close(); err != nil {
+		c.logger.Error("Error while trying to close multipart writer!", err)
+		return err
+	}
+
+	return nil
+}
+
+// multipartLength returns the length of the multipart body written by writeMultipartFile or -1 if the file size is unknown.
+func multipartLength(boundary string, file *fileRequest) int64 {
+	if file.Size < 0 {
+		return -1
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(boundary); err != nil {
+		return -1
+	}
+	if _, err := w.CreateFormFile("file", file.Filename); err != nil {
+		return -1
+	}
+	if err := w.Close(); err != nil {
+		return -1
+	}
+
+	return int64(buf.Len()) + file.Size
+}
+
+// contextReader stops reading as soon as the context is done, so canceled transfers don't wait for slow readers.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+// readerSize returns the number of bytes left in the reader if it can be found without reading.
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	case io.Seeker:
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := v.Seek(offset, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - offset, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,222 @@
+package pyrus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type largeReader struct{}
+
+func (largeReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (largeReader) Len() int                 { return MaxUploadSize + 1 }
+
+func TestClient_UploadFile_tooLarge(t *testing.T) {
+	c, err := NewClient("login", "key", WithBaseURL("http://127.0.0.1:0"))
+	require.NoError(t, err)
+
+	_, err = c.UploadFile("large.bin", largeReader{})
+	var apiErr Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrTooLargeRequestLength, apiErr.Code)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "large.bin"))
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, f.Truncate(MaxUploadSize+1))
+
+	_, err = c.UploadFile("large.bin", f)
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrTooLargeRequestLength, apiErr.Code)
+}
+
+func TestReaderSize(t *testing.T) {
+	size, ok := readerSize(bytes.NewReader([]byte("abc")))
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, size)
+
+	r := strings.NewReader("abcdef")
+	_, err := r.Seek(2, io.SeekStart)
+	require.NoError(t, err)
+	size, ok = readerSize(io.NewSectionReader(r, 0, 6))
+	assert.True(t, ok)
+	assert.EqualValues(t, 6, size)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "file.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString("abcdef")
+	require.NoError(t, err)
+	_, err = f.Seek(4, io.SeekStart)
+	require.NoError(t, err)
+	size, ok = readerSize(f)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, size)
+
+	_, ok = readerSize(io.MultiReader(r))
+	assert.False(t, ok)
+}
+
+// gatedReader returns the first chunk and waits for the gate before returning the rest.
+type gatedReader struct {
+	first, rest []byte
+	gate        chan struct{}
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if len(r.first) > 0 {
+		n := copy(p, r.first)
+		r.first = r.first[n:]
+		return n, nil
+	}
+	if r.gate != nil {
+		select {
+		case <-r.gate:
+		case <-time.After(5 * time.Second):
+			return 0, errors.New("upload is not streamed")
+		}
+		r.gate = nil
+	}
+	if len(r.rest) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.rest)
+	r.rest = r.rest[n:]
+	return n, nil
+}
+
+func TestClient_UploadFile_streaming(t *testing.T) {
+	gate := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		mr, err := r.MultipartReader()
+		require.NoError(t, err)
+		part, err := mr.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "report.txt", part.FileName())
+
+		first := make([]byte, 5)
+		_, err = io.ReadFull(part, first)
+		require.NoError(t, err)
+		close(gate)
+
+		rest, err := ioutil.ReadAll(part)
+		require.NoError(t, err)
+		w.Write([]byte(`{"guid":"` + string(first) + string(rest) + `"}`)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	upload, err := c.UploadFile("report.txt", &gatedReader{first: []byte("first"), rest: []byte(" rest"), gate: gate})
+	require.NoError(t, err)
+	assert.Equal(t, "first rest", upload.GUID)
+}
+
+func TestClient_UploadFile_contentLength(t *testing.T) {
+	var lengths []int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if r.ContentLength >= 0 {
+			assert.EqualValues(t, len(b), r.ContentLength)
+			assert.Empty(t, r.TransferEncoding)
+		}
+		lengths = append(lengths, r.ContentLength)
+		w.Write([]byte(`{"guid":"guid"}`)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	_, err = c.UploadFile("report.txt", strings.NewReader("content"))
+	require.NoError(t, err)
+	_, err = c.UploadFileWithOptions("report.txt", io.MultiReader(strings.NewReader("content")), WithUploadSize(7))
+	require.NoError(t, err)
+	_, err = c.UploadFile("report.txt", io.MultiReader(strings.NewReader("content")))
+	require.NoError(t, err)
+
+	require.Len(t, lengths, 3)
+	assert.Positive(t, lengths[0])
+	assert.Equal(t, lengths[0], lengths[1])
+	assert.EqualValues(t, -1, lengths[2])
+
+	_, err = c.UploadFileWithOptions("large.bin", io.MultiReader(), WithUploadSize(MaxUploadSize+1))
+	var apiErr Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrTooLargeRequestLength, apiErr.Code)
+}
+
+func TestClient_transferContext(t *testing.T) {
+	received := make(chan struct{})
+	// Handlers report that the client has aborted the request
+	uploadAborted, downloadAborted := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/files/upload":
+			// FailNow can't be called from the handler goroutine, errors are only reported
+			_, err := io.ReadFull(r.Body, make([]byte, 1))
+			assert.NoError(t, err)
+			close(received)
+			// reading fails once the client aborts the request
+			io.Copy(ioutil.Discard, r.Body) //nolint:errcheck
+			close(uploadAborted)
+		case "/files/download/1":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="file.bin"`)
+			w.Write([]byte("first chunk")) //nolint:errcheck
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			close(downloadAborted)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// the upload is canceled only once the server got its first bytes
+	go func() {
+		<-received
+		cancel()
+	}()
+	// the reader fails with another error if the upload isn't aborted by cancellation
+	_, err = c.UploadFileContext(ctx, "endless.bin", &gatedReader{first: []byte("first"), gate: make(chan struct{})})
+	assert.ErrorIs(t, err, context.Canceled)
+	<-uploadAborted
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = c.DownloadFileContext(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-downloadAborted
+}
@@ -0,0 +1,116 @@
+package pyrus
+
+import (
+	"sync"
+	"time"
+)
+
+// APIUsage contains the number of API requests sent during the day, including authorization requests and retries.
+// Days start at midnight UTC. Responses served from the cache aren't counted.
+type APIUsage struct {
+	Day   time.Time `json:"day"`
+	Total int       `json:"total"`
+	// Endpoints contains counters per endpoint, see StatsCollector for the endpoint format.
+	Endpoints map[string]int `json:"endpoints"`
+}
+
+// UsageStore persists APIUsage between restarts, so several runs of a batch job during the day share the budget.
+// Load should return nil usage without error if there is no saved state yet.
+type UsageStore interface {
+	LoadUsage() (*APIUsage, error)
+	SaveUsage(u *APIUsage) error
+}
+
+// WithUsageStore allows to persist daily API usage in own storage. Usage is kept in memory only by default.
+// The store is saved after every request, so it should be fast.
+func WithUsageStore(s UsageStore) Option {
+	return func(c *Client) {
+		c.usage.store = s
+	}
+}
+
+// Usage returns API usage of the current day.
+func (c *Client) Usage() APIUsage {
+	return c.usage.get(c.clock.Now(), c.logger)
+}
+
+// recordUsage counts the request to the endpoint.
+func (c *Client) recordUsage(method, path string) {
+	c.usage.record(endpointName(method, path), c.clock.Now(), c.logger)
+}
+
+// usageTracker is shared by the client and its event clients, since they spend the same organization limits.
+type usageTracker struct {
+	mu     sync.Mutex
+	store  UsageStore
+	loaded bool
+	usage  APIUsage
+}
+
+func (t *usageTracker) get(now time.Time, logger Logger) APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.load(logger)
+	t.roll(now)
+
+	return t.usage.copy()
+}
+
+func (t *usageTracker) record(endpoint string, now time.Time, logger Logger) {
+	t.mu.Lock()
+	t.load(logger)
+	t.roll(now)
+	t.usage.Total++
+	t.usage.Endpoints[endpoint]++
+	usage := t.usage.copy()
+	t.mu.Unlock()
+
+	if t.store == nil {
+		return
+	}
+	if err := t.store.SaveUsage(&usage); err != nil {
+		logger.Error("Error while saving API usage!", err)
+	}
+}
+
+// load loads the saved usage once. It must be called with the lock held.
+func (t *usageTracker) load(logger Logger) {
+	if t.loaded {
+		return
+	}
+	t.loaded = true
+	if t.store == nil {
+		return
+	}
+
+	u, err := t.store.LoadUsage()
+	if err != nil {
+		logger.Error("Error while loading API usage!", err)
+		return
+	}
+	if u != nil {
+		t.usage = u.copy()
+	}
+}
+
+// roll resets counters when the day changes. It must be called with the lock held.
+func (t *usageTracker) roll(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !t.usage.Day.Equal(day) {
+		t.usage = APIUsage{Day: day}
+	}
+	if t.usage.Endpoints == nil {
+		t.usage.Endpoints = make(map[string]int)
+	}
+}
+
+func (u APIUsage) copy() APIUsage {
+	endpoints := make(map[string]int, len(u.Endpoints))
+	for k, v := range u.Endpoints {
+		endpoints[k] = v
+	}
+	u.Endpoints = endpoints
+
+	return u
+}
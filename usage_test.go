@@ -0,0 +1,108 @@
+package pyrus
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryUsageStore struct {
+	usage *APIUsage
+	saves int
+}
+
+func (s *memoryUsageStore) LoadUsage() (*APIUsage, error) {
+	return s.usage, nil
+}
+
+func (s *memoryUsageStore) SaveUsage(u *APIUsage) error {
+	s.usage = u
+	s.saves++
+	return nil
+}
+
+type failingUsageStore struct{}
+
+func (failingUsageStore) LoadUsage() (*APIUsage, error) {
+	return nil, errors.New("test")
+}
+
+func (failingUsageStore) SaveUsage(*APIUsage) error {
+	return errors.New("test")
+}
+
+func TestClient_Usage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		default:
+			w.Write([]byte(`{}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 23, 0, 0, 0, time.UTC)}
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryUsageStore{usage: &APIUsage{
+		Day:       day,
+		Total:     10,
+		Endpoints: map[string]int{"GET /forms/{id}": 10},
+	}}
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithClock(clock), WithUsageStore(store))
+	require.NoError(t, err)
+
+	t.Run("saved usage is continued", func(t *testing.T) {
+		_, err := c.Form(1)
+		require.NoError(t, err)
+		_, err = c.Form(2)
+		require.NoError(t, err)
+		_, err = c.Task(1)
+		require.NoError(t, err)
+
+		usage := c.Usage()
+		assert.Equal(t, day, usage.Day)
+		assert.Equal(t, 14, usage.Total)
+		assert.Equal(t, map[string]int{
+			"POST /auth":      1,
+			"GET /forms/{id}": 12,
+			"GET /tasks/{id}": 1,
+		}, usage.Endpoints)
+
+		assert.Equal(t, 4, store.saves)
+		assert.Equal(t, 14, store.usage.Total)
+
+		// Returned usage is a copy
+		usage.Endpoints["GET /tasks/{id}"] = 100
+		assert.Equal(t, 1, c.Usage().Endpoints["GET /tasks/{id}"])
+	})
+
+	t.Run("counters are reset next day", func(t *testing.T) {
+		clock.Advance(time.Hour)
+		assert.Equal(t, 0, c.Usage().Total)
+
+		_, err := c.Form(1)
+		require.NoError(t, err)
+
+		usage := c.Usage()
+		assert.Equal(t, day.Add(24*time.Hour), usage.Day)
+		assert.Equal(t, map[string]int{"GET /forms/{id}": 1}, usage.Endpoints)
+		assert.Equal(t, 1, store.usage.Total)
+	})
+
+	t.Run("store errors don't break requests", func(t *testing.T) {
+		c, err := NewClient("login", "key", WithBaseURL(ts.URL), WithUsageStore(failingUsageStore{}))
+		require.NoError(t, err)
+
+		_, err = c.Form(1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, c.Usage().Total)
+	})
+}
@@ -0,0 +1,44 @@
+package pyrus
+
+import (
+	"reflect"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// WithValidation allows to disable built-in validation of requests, e.g. when it rejects payloads accepted by Pyrus.
+func WithValidation(mode ValidationMode) Option {
+	return func(c *Client) {
+		c.validationMode = mode
+	}
+}
+
+// WithValidator allows to plug a custom validator called for every request passed to the client methods,
+// like *TaskRequest or *MemberRequest. It's called after built-in validation, if the latter is enabled.
+func WithValidator(fn func(req interface{}) error) Option {
+	return func(c *Client) {
+		c.validator = fn
+	}
+}
+
+// validate checks the request according to WithValidation and WithValidator.
+func (c *Client) validate(req interface{}) error {
+	if c.validationMode != ValidationDisabled && !isNilPointer(req) {
+		if v, ok := req.(validation.Validatable); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.validator != nil {
+		return c.validator(req)
+	}
+
+	return nil
+}
+
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return v == nil || rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,59 @@
+package pyrus
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidation(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		default:
+			requests++
+			w.Write([]byte(`{"task":{"id":1}}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+	_, err = c.CreateTask(&TaskRequest{})
+	assert.Error(t, err)
+	assert.Zero(t, requests)
+
+	c, err = NewClient("login", "key", WithBaseURL(ts.URL), WithValidation(ValidationDisabled))
+	require.NoError(t, err)
+	_, err = c.CreateTask(&TaskRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestWithValidator(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	var validated []interface{}
+	c, err := NewClient("login", "key", WithBaseURL("http://127.0.0.1:0"), WithValidator(func(req interface{}) error {
+		validated = append(validated, req)
+		return errRejected
+	}))
+	require.NoError(t, err)
+
+	member := &MemberRequest{}
+	_, err = c.CreateMember(member)
+	assert.ErrorIs(t, err, errRejected)
+
+	registry := &RegistryRequest{}
+	_, err = c.Registry(1, registry)
+	assert.ErrorIs(t, err, errRejected)
+
+	assert.Equal(t, []interface{}{member, registry}, validated)
+}
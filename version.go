@@ -0,0 +1,48 @@
+package pyrus
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// apiVersionRe matches the version segment of the base URL like v4 or v5-beta.
+var apiVersionRe = regexp.MustCompile(`^v\d+([.-][0-9A-Za-z.-]+)?$`)
+
+type apiVersionKey struct{}
+
+// WithAPIVersion allows to target another API version, e.g. "v5" or beta endpoints, without replacing the whole base URL.
+// The version segment of the base URL is replaced or appended if the base URL has none.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// ContextWithAPIVersion overrides API version for requests performed with the context.
+// Authorization still uses the version of the client.
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// requestBaseURL returns the base URL with the API version of the client or the context applied.
+func (c *Client) requestBaseURL(ctx context.Context) string {
+	version := c.apiVersion
+	if v, ok := ctx.Value(apiVersionKey{}).(string); ok && v != "" {
+		version = v
+	}
+	if version == "" {
+		return c.baseURL
+	}
+
+	return versionedBaseURL(c.baseURL, version)
+}
+
+func versionedBaseURL(base, version string) string {
+	base = strings.TrimRight(base, "/")
+	if i := strings.LastIndexByte(base, '/'); i >= 0 && apiVersionRe.MatchString(base[i+1:]) {
+		base = base[:i]
+	}
+
+	return base + "/" + strings.Trim(version, "/")
+}
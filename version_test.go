@@ -0,0 +1,44 @@
+package pyrus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAPIVersion(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v5/auth" {
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{}`)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL+"/v4"), WithAPIVersion("v5"))
+	require.NoError(t, err)
+
+	_, err = c.Forms()
+	require.NoError(t, err)
+
+	status, err := c.Ping(ContextWithAPIVersion(context.Background(), "v6-beta"))
+	require.NoError(t, err)
+	assert.Equal(t, PingStatusOK, status)
+
+	assert.Equal(t, []string{"/v5/auth", "/v5/forms", "/v6-beta/profile"}, paths)
+}
+
+func TestVersionedBaseURL(t *testing.T) {
+	assert.Equal(t, "https://api.pyrus.com/v5", versionedBaseURL("https://api.pyrus.com/v4", "v5"))
+	assert.Equal(t, "https://api.pyrus.com/v5", versionedBaseURL("https://api.pyrus.com/v4/", "/v5"))
+	assert.Equal(t, "https://pyrus.example.org/api/v4", versionedBaseURL("https://pyrus.example.org/api", "v4"))
+	assert.Equal(t, "https://api.pyrus.com/v4", versionedBaseURL("https://api.pyrus.com/v5-beta", "v4"))
+}
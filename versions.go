@@ -0,0 +1,97 @@
+package pyrus
+
+import (
+	"errors"
+	"io"
+	"sort"
+)
+
+// VersionRootID returns id of the first version of the file. All versions of the file share it.
+func (f *File) VersionRootID() int {
+	if f.RootID != 0 {
+		return f.RootID
+	}
+
+	return f.ID
+}
+
+// FileVersions returns all versions of the file found in the list ordered from the oldest to the latest.
+func FileVersions(files []*File, fileID int) []*File {
+	rootID := 0
+	for _, f := range files {
+		if f != nil && f.ID == fileID {
+			rootID = f.VersionRootID()
+			break
+		}
+	}
+	if rootID == 0 {
+		return nil
+	}
+
+	var versions []*File
+	for _, f := range files {
+		if f != nil && f.VersionRootID() == rootID {
+			versions = append(versions, f)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions
+}
+
+// LatestVersion returns the latest version of the file found in the list, e.g. in TaskAttachments.
+// The file is returned as is if the list has no other versions, nil is returned if the list doesn't contain the file.
+func LatestVersion(files []*File, fileID int) *File {
+	versions := FileVersions(files, fileID)
+	if len(versions) == 0 {
+		return nil
+	}
+
+	return versions[len(versions)-1]
+}
+
+// LatestVersions returns the latest version of every file in the list keeping the order of their first versions.
+func LatestVersions(files []*File) []*File {
+	var roots []int
+	latest := make(map[int]*File)
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		rootID := f.VersionRootID()
+		current, ok := latest[rootID]
+		if !ok {
+			roots = append(roots, rootID)
+		}
+		if !ok || f.Version > current.Version {
+			latest[rootID] = f
+		}
+	}
+
+	result := make([]*File, 0, len(roots))
+	for _, rootID := range roots {
+		result = append(result, latest[rootID])
+	}
+
+	return result
+}
+
+// UploadFileVersion uploads a new version of the existing file and returns the attachment which adds it
+// to the version chain of the file, e.g. when passed to TaskCommentRequest.Attachments.
+func (c *Client) UploadFileVersion(file *File, name string, r io.Reader, opts ...UploadOption) (*Attachment, error) {
+	if file == nil {
+		return nil, errors.New("file cannot be nil")
+	}
+
+	upload, err := c.UploadFileWithOptions(name, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		GUID:   upload.GUID,
+		RootID: file.VersionRootID(),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package pyrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileVersions(t *testing.T) {
+	files := []*File{
+		{ID: 1, Name: "contract.docx", Version: 1},
+		{ID: 5, Name: "scan.pdf", Version: 1},
+		{ID: 7, Name: "contract.docx", Version: 3, RootID: 1},
+		{ID: 3, Name: "contract.docx", Version: 2, RootID: 1},
+		nil,
+	}
+
+	versions := FileVersions(files, 3)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []int{1, 3, 7}, []int{versions[0].ID, versions[1].ID, versions[2].ID})
+
+	assert.Equal(t, 7, LatestVersion(files, 1).ID)
+	assert.Equal(t, 5, LatestVersion(files, 5).ID)
+	assert.Nil(t, LatestVersion(files, 100))
+
+	latest := LatestVersions(files)
+	require.Len(t, latest, 2)
+	assert.Equal(t, 7, latest[0].ID)
+	assert.Equal(t, 5, latest[1].ID)
+}
+
+func TestClient_UploadFileVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth":
+			w.Write([]byte(`{"access_token":"token"}`)) //nolint:errcheck
+		case "/files/upload":
+			w.Write([]byte(`{"guid":"5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5","md5_hash":"hash"}`)) //nolint:errcheck
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("login", "key", WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	attachment, err := c.UploadFileVersion(&File{ID: 7, Version: 3, RootID: 1}, "contract.docx", strings.NewReader("v4"))
+	require.NoError(t, err)
+	assert.Equal(t, &Attachment{GUID: "5d8dc3d6-27e7-4cd4-a057-2b4f4d74e0a5", RootID: 1}, attachment)
+	assert.NoError(t, attachment.Validate())
+
+	_, err = c.UploadFileVersion(nil, "contract.docx", strings.NewReader("v4"))
+	assert.Error(t, err)
+}
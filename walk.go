@@ -0,0 +1,87 @@
+package pyrus
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FieldPathElement is an ancestor of the field: a title, multiple choice or table containing it.
+type FieldPathElement struct {
+	Field *FormField
+	// RowID is the id of the table row containing the field, only set for tables.
+	RowID int
+	// InTable is true if the element is a table.
+	InTable bool
+}
+
+// FieldPath is a chain of ancestors of the field from the top level.
+type FieldPath []FieldPathElement
+
+// String returns the path as field names (or ids for unnamed fields) joined by "/", table rows are added in brackets:
+// "Товары[2]/Цена".
+func (p FieldPath) String() string {
+	parts := make([]string, 0, len(p))
+	for _, e := range p {
+		part := e.Field.Name
+		if part == "" {
+			part = strconv.Itoa(e.Field.ID)
+		}
+		if e.InTable {
+			part += "[" + strconv.Itoa(e.RowID) + "]"
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// Walk traverses the fields in depth-first order including fields nested into titles, multiple choices
+// and cells of table rows, calling fn with the path of ancestors for every field.
+// Traversal stops when fn returns false.
+func Walk(fields []*FormField, fn func(path FieldPath, f *FormField) bool) {
+	walkFields(nil, fields, fn)
+}
+
+func walkFields(path FieldPath, fields []*FormField, fn func(path FieldPath, f *FormField) bool) bool {
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+		if !fn(path, f) {
+			return false
+		}
+
+		value, _ := f.DecodedValue()
+		var nested []*FormField
+		switch v := value.(type) {
+		case *Title:
+			nested = v.Fields
+		case *MultipleChoice:
+			nested = v.Fields
+		case Table:
+			for _, row := range v {
+				if row == nil {
+					continue
+				}
+				rowPath := appendPath(path, FieldPathElement{Field: f, RowID: row.RowID, InTable: true})
+				if !walkFields(rowPath, row.Cells, fn) {
+					return false
+				}
+			}
+			continue
+		}
+
+		if len(nested) > 0 && !walkFields(appendPath(path, FieldPathElement{Field: f}), nested, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// appendPath returns a copy of the path with the element, so paths passed to fn could be retained.
+func appendPath(path FieldPath, e FieldPathElement) FieldPath {
+	p := make(FieldPath, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, e)
+}
@@ -0,0 +1,47 @@
+package pyrus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	var fields []*FormField
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id":1,"type":"text","name":"Описание","value":"Пример"},
+		{"id":2,"type":"title","name":"Заказ","value":{"fields":[
+			{"id":3,"type":"table","name":"Товары","value":[
+				{"row_id":0,"cells":[{"id":4,"type":"money","name":"Цена","value":100}]},
+				{"row_id":1,"cells":[{"id":4,"type":"money","name":"Цена","value":200}]}
+			]}
+		]}},
+		{"id":5,"type":"multiple_choice","name":"Доставка","value":{"choice_ids":[1],"fields":[
+			{"id":6,"type":"text","value":"Москва"}
+		]}}
+	]`), &fields))
+
+	var visited []string
+	Walk(fields, func(path FieldPath, f *FormField) bool {
+		visited = append(visited, path.String()+"#"+f.Name)
+		return true
+	})
+	assert.Equal(t, []string{
+		"#Описание",
+		"#Заказ",
+		"Заказ#Товары",
+		"Заказ/Товары[0]#Цена",
+		"Заказ/Товары[1]#Цена",
+		"#Доставка",
+		"Доставка#",
+	}, visited)
+
+	var count int
+	Walk(fields, func(path FieldPath, f *FormField) bool {
+		count++
+		return f.ID != 4
+	})
+	assert.Equal(t, 4, count)
+}
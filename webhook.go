@@ -0,0 +1,133 @@
+package pyrus
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultWebhookMaxBodySize limits webhook bodies accepted by webhook handlers.
+const defaultWebhookMaxBodySize = 10 << 20
+
+// WebhookMux is implemented by http.ServeMux and most of the routers, e.g. chi.
+type WebhookMux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// WithWebhookConcurrencyLimit allows to process at most limit webhook requests at once, so a burst of deliveries
+// can't exhaust memory. Requests beyond the limit wait up to wait for a free slot and get 503 after that,
+// so Pyrus redelivers them later. Zero disables the limit.
+func WithWebhookConcurrencyLimit(limit int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.webhookLimit = limit
+		c.webhookWait = wait
+		c.webhookSem = nil
+		if limit > 0 {
+			c.webhookSem = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithWebhookMaxBodySize allows to override default webhook body limit of 10 MB. Larger requests get 413.
+// Zero disables the limit.
+func WithWebhookMaxBodySize(n int64) Option {
+	return func(c *Client) {
+		c.webhookMaxBodySize = n
+	}
+}
+
+// WithWebhookSecrets allows to accept webhooks signed with additional secrets besides the security key,
+// e.g. both old and new ones while the key is being rotated.
+func WithWebhookSecrets(secrets ...string) Option {
+	return func(c *Client) {
+		c.webhookSecrets = append(c.webhookSecrets, secrets...)
+	}
+}
+
+// WebhookHTTPHandler returns webhook handler which accepts only POST requests with JSON body up to 10 MB,
+// see WithWebhookMaxBodySize.
+// Requests with other methods get 405, with other content types 415 and with larger bodies 413.
+func (c *Client) WebhookHTTPHandler() (http.Handler, <-chan Event) {
+	handler, events := c.webhookHandler()
+
+	// Limit is applied before reading the body, so waiting requests don't hold their bodies in memory
+	return c.limitWebhook(&webhookHandler{next: handler}), events
+}
+
+// MountWebhook registers WebhookHTTPHandler in the mux by the path and returns Event chan.
+func (c *Client) MountWebhook(path string, mux WebhookMux) <-chan Event {
+	handler, events := c.WebhookHTTPHandler()
+	mux.Handle(path, handler)
+
+	return events
+}
+
+// webhookHandler checks method and content type of requests, the body is read and limited by next.
+type webhookHandler struct {
+	next http.Handler
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Missing content type is tolerated, since signature check protects from garbage anyway
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err != nil || mt != "application/json" {
+			http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+
+	h.next.ServeHTTP(w, r)
+}
+
+// limitWebhook applies WithWebhookConcurrencyLimit to the handler.
+func (c *Client) limitWebhook(next http.Handler) http.HandlerFunc {
+	if c.webhookSem == nil {
+		return next.ServeHTTP
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case c.webhookSem <- struct{}{}:
+		default:
+			select {
+			case c.webhookSem <- struct{}{}:
+			case <-c.clock.After(c.webhookWait):
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+		defer func() { <-c.webhookSem }()
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// validSignature checks the signature against the security key and secrets of WithWebhookSecrets.
+// Every secret is checked, so the time doesn't depend on which one has matched.
+func (c *Client) validSignature(body []byte, signature string) bool {
+	signature = strings.ToLower(signature)
+
+	valid := 0
+	for _, secret := range append([]string{c.securityKey}, c.webhookSecrets...) {
+		hasher := hmac.New(sha1.New, []byte(secret))
+		hasher.Write(body)
+		hash := hex.EncodeToString(hasher.Sum(nil))
+		valid |= subtle.ConstantTimeCompare([]byte(hash), []byte(signature))
+	}
+
+	return valid == 1
+}